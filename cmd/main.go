@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"flag"
 	"net/http"
@@ -14,10 +15,13 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/infra/mysql"
 	"github.com/tusmasoma/go-tech-dojo/infra/redis"
 	"github.com/tusmasoma/go-tech-dojo/interfaces/handler"
 	"github.com/tusmasoma/go-tech-dojo/interfaces/middleware"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 	"github.com/tusmasoma/go-tech-dojo/usecase"
 
@@ -57,45 +61,135 @@ func Serve(addr string) {
 
 	client := redis.NewRedisClient(mainCtx)
 
+	passwordConf, err := config.NewPasswordConfig(mainCtx)
+	if err != nil {
+		log.Error("Failed to load password config", log.Ferror(err))
+		return
+	}
+	passwordHasher, err := auth.NewPasswordHasher(auth.PasswordHashParams{
+		Algorithm:     auth.HashAlgorithm(passwordConf.HashAlgorithm),
+		BcryptCost:    passwordConf.BcryptCost,
+		Argon2Time:    passwordConf.Argon2Time,
+		Argon2Memory:  passwordConf.Argon2Memory,
+		Argon2Threads: passwordConf.Argon2Threads,
+		ScryptLogN:    passwordConf.ScryptLogN,
+		ScryptR:       passwordConf.ScryptR,
+		ScryptP:       passwordConf.ScryptP,
+	})
+	if err != nil {
+		log.Error("Failed to create password hasher", log.Ferror(err))
+		return
+	}
+
+	gameSessionConf, err := loadGameSessionConfig(mainCtx)
+	if err != nil {
+		log.Error("Failed to load game config", log.Ferror(err))
+		return
+	}
+
+	gachaConf, err := config.NewGachaConfig(mainCtx)
+	if err != nil {
+		log.Error("Failed to load gacha config", log.Ferror(err))
+		return
+	}
+
+	idempotencyConf, err := config.NewIdempotencyConfig(mainCtx)
+	if err != nil {
+		log.Error("Failed to load idempotency config", log.Ferror(err))
+		return
+	}
+
+	keySet, err := loadJWTKeySet(mainCtx)
+	if err != nil {
+		log.Error("Failed to load JWT signing keys", log.Ferror(err))
+		return
+	}
+	auth.UseKeySet(keySet)
+	go reloadJWTKeySetOnSIGHUP(mainCtx)
+
 	transactionRepo := mysql.NewTransactionRepository(db)
 	userRepo := mysql.NewUserRepository(db)
 	userCollectionRepo := mysql.NewUserCollectionRepository(db)
 	collectionRepo := mysql.NewCollectionRepository(db)
 	scoreRepo := mysql.NewScoreRepository(db)
+	refreshTokenRepo := mysql.NewRefreshTokenRepository(db)
+	coinTransactionRepo := mysql.NewCoinTransactionRepository(db)
+	walletRepo := mysql.NewWalletRepository(db)
+	adminAuditLogRepo := mysql.NewAdminAuditLogRepository(db)
+	gameSessionRepo := mysql.NewGameSessionRepository(db)
+	bannerRepo := mysql.NewBannerRepository(db)
+	gachaPityRepo := mysql.NewGachaPityRepository(db)
+	gachaDrawRepo := mysql.NewGachaDrawRepository(db)
 	collectionCacheRepo := redis.NewCollectionRepository(client)
+	dropRatesCacheRepo := redis.NewDropRatesRepository(client)
+	tokenRevocationRepo := redis.NewTokenRevocationRepository(client)
 	rankingRepo := redis.NewRankingRepository(client)
-	userUseCase := usecase.NewUserUseCase(userRepo, transactionRepo, userCollectionRepo, collectionRepo, collectionCacheRepo)
+	idempotencyRepo := redis.NewIdempotencyRepository(client)
+	userUseCase, err := usecase.NewUserUseCase(userRepo, walletRepo, transactionRepo, userCollectionRepo, collectionRepo, collectionCacheRepo, refreshTokenRepo, passwordHasher)
+	if err != nil {
+		log.Error("Failed to create user usecase", log.Ferror(err))
+		return
+	}
+	authUseCase := usecase.NewAuthUseCase(transactionRepo, userRepo, refreshTokenRepo, tokenRevocationRepo)
 	rankingUseCase := usecase.NewRankingUseCase(rankingRepo)
-	gameUsecase := usecase.NewGameUseCase(transactionRepo, userRepo, userCollectionRepo, scoreRepo, rankingRepo, collectionRepo, collectionCacheRepo)
+	gameUsecase := usecase.NewGameUseCase(transactionRepo, userRepo, userCollectionRepo, scoreRepo, rankingRepo, collectionRepo, collectionCacheRepo, dropRatesCacheRepo, coinTransactionRepo, walletRepo, gameSessionRepo, bannerRepo, gachaPityRepo, gachaDrawRepo, *gameSessionConf, model.PityRules{
+		SoftPity:         gachaConf.SoftPity,
+		SoftPityRateStep: gachaConf.SoftPityRateStep,
+		HardPity:         gachaConf.HardPity,
+	}, model.NewCryptoRandomSource())
+	coinUseCase := usecase.NewCoinUseCase(coinTransactionRepo)
+	adminUseCase := usecase.NewAdminUseCase(transactionRepo, userRepo, coinTransactionRepo, walletRepo, adminAuditLogRepo, gachaDrawRepo, refreshTokenRepo, tokenRevocationRepo)
+	collectionUseCase := usecase.NewCollectionUseCase(transactionRepo, collectionRepo, collectionCacheRepo, dropRatesCacheRepo)
+	craftUseCase := usecase.NewCraftUseCase(transactionRepo, userRepo, userCollectionRepo, collectionRepo, coinTransactionRepo, walletRepo)
 	userHandler := handler.NewUserHandler(userUseCase)
+	authHandler := handler.NewAuthHandler(authUseCase)
 	rankingHandler := handler.NewRankingHandler(rankingUseCase)
 	gameHandler := handler.NewGameHandler(gameUsecase)
-	authMiddleware := middleware.NewAuthMiddleware()
+	coinHandler := handler.NewCoinHandler(coinUseCase)
+	adminHandler := handler.NewAdminHandler(adminUseCase)
+	collectionHandler := handler.NewCollectionHandler(collectionUseCase)
+	craftHandler := handler.NewCraftHandler(craftUseCase)
+	authMiddleware := middleware.NewAuthMiddleware(tokenRevocationRepo)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyRepo, idempotencyConf.TTL, idempotencyConf.InProgressTTL)
 
 	/* ===== URLマッピングを行う ===== */
 	r := chi.NewRouter()
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Origin"},
-		ExposedHeaders:   []string{"Link", "Authorization"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Origin", middleware.IdempotencyKeyHeader},
+		ExposedHeaders:   []string{"Link", "Authorization", middleware.IdempotentReplayedHeader},
 		AllowCredentials: false,
 		MaxAge:           PreflightCacheDurationSeconds,
 	}))
 
+	r.Get("/.well-known/jwks.json", authHandler.JWKS)
+
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/user", func(r chi.Router) {
 			r.Post("/create", userHandler.CreateUser)
+			r.Post("/login", userHandler.Login)
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.Authenticate)
 				r.Get("/get", userHandler.GetUser)
 				r.Put("/update", userHandler.UpdateUser)
+				r.Get("/coins/history", coinHandler.ListCoinHistory)
+			})
+		})
+		r.Route("/auth", func(r chi.Router) {
+			r.Post("/refresh", authHandler.Refresh)
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.Authenticate)
+				r.Post("/logout", authHandler.Logout)
+				r.Post("/logout/all", authHandler.LogoutAll)
 			})
 		})
 		r.Route("/collection", func(r chi.Router) {
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.Authenticate)
 				r.Get("/list", userHandler.ListUserCollections)
+				r.With(idempotencyMiddleware.Idempotent).Post("/dismantle", craftHandler.Dismantle)
+				r.With(idempotencyMiddleware.Idempotent).Post("/craft", craftHandler.Craft)
 			})
 		})
 		r.Route("/ranking", func(r chi.Router) {
@@ -104,13 +198,38 @@ func Serve(addr string) {
 		r.Route("/game", func(r chi.Router) {
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.Authenticate)
-				r.Post("/finish", gameHandler.FinishGame)
+				r.Post("/start", gameHandler.StartGame)
+				r.With(idempotencyMiddleware.Idempotent).Post("/finish", gameHandler.FinishGame)
 			})
 		})
 		r.Route("/gacha", func(r chi.Router) {
+			// 排出確率はガチャを引く前に確認できるよう、認証なしで公開する
+			r.Get("/rates", gameHandler.GetDropRates)
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.Authenticate)
-				r.Post("/draw", gameHandler.DrawGacha)
+				r.Get("/banners", gameHandler.ListBanners)
+				r.With(idempotencyMiddleware.Idempotent).Post("/draw", gameHandler.DrawGacha)
+				r.Get("/history", gameHandler.ListGachaHistory)
+			})
+		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authMiddleware.Authenticate)
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(model.RoleAdmin))
+				r.Put("/users/{user_id}/stats", adminHandler.UpdateUserStats)
+				r.Put("/users/{user_id}/role", adminHandler.UpdateUserRole)
+				// アイテムのマスタデータはガチャの排出確率を決めるため、変更はadminに限る
+				r.Post("/collections", collectionHandler.CreateCollection)
+				r.Post("/collections/import", collectionHandler.ImportCollections)
+				r.Put("/collections/{collection_id}", collectionHandler.UpdateCollection)
+				r.Delete("/collections/{collection_id}", collectionHandler.DeleteCollection)
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(model.RoleOperator, model.RoleAdmin))
+				r.Get("/users/{user_id}/audit-logs", adminHandler.ListAuditLogs)
+				r.Get("/gacha/draws", adminHandler.ListGachaDraws)
+				r.Get("/collections", collectionHandler.ListCollections)
+				r.Get("/collections/{collection_id}", collectionHandler.GetCollection)
 			})
 		})
 	})
@@ -146,3 +265,75 @@ func Serve(addr string) {
 	}
 	log.Info("Server exited")
 }
+
+// loadGameSessionConfig 署名鍵が設定されていない場合は起動に失敗させる
+// GAME_ALLOW_EPHEMERAL_SECRETが有効な開発環境でのみ一時的な鍵を使う
+func loadGameSessionConfig(ctx context.Context) (*usecase.GameSessionConfig, error) {
+	gameConf, err := config.NewGameConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret := []byte(gameConf.SessionSecret)
+	if len(secret) == 0 {
+		if !gameConf.AllowEphemeralSecret {
+			return nil, errors.New("GAME_SESSION_SECRET is not set, set GAME_ALLOW_EPHEMERAL_SECRET=true to use an ephemeral secret in development")
+		}
+		log.Warn("GAME_SESSION_SECRET is not set, using an ephemeral secret for development; game sessions will be invalidated on restart")
+		secret = make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &usecase.GameSessionConfig{
+		Secret: secret,
+		Rules: model.GameSessionRules{
+			MinDuration:       gameConf.MinDuration,
+			MaxDuration:       gameConf.MaxDuration,
+			MaxScorePerSecond: gameConf.MaxScorePerSecond,
+		},
+	}, nil
+}
+
+// loadJWTKeySet 鍵が設定されていない場合は起動に失敗させる
+// JWT_ALLOW_EPHEMERAL_KEYが有効な開発環境でのみ一時的な鍵を使う。iss,audの設定もあわせて反映する
+func loadJWTKeySet(ctx context.Context) (*auth.KeySet, error) {
+	jwtConf, err := config.NewJWTConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	auth.UseIssuer(jwtConf.Issuer, jwtConf.Audience)
+	if len(jwtConf.PrivateKeyFiles) == 0 {
+		if !jwtConf.AllowEphemeralKey {
+			return nil, errors.New("JWT_PRIVATE_KEY_FILES is not set, set JWT_ALLOW_EPHEMERAL_KEY=true to use an ephemeral key in development")
+		}
+		log.Warn("JWT_PRIVATE_KEY_FILES is not set, using an ephemeral key for development; tokens will be invalidated on restart")
+		return auth.NewEphemeralKeySet()
+	}
+	return auth.LoadKeySet(jwtConf.ActiveKeyID, jwtConf.PrivateKeyFiles, jwtConf.PublicKeyFiles)
+}
+
+// reloadJWTKeySetOnSIGHUP SIGHUPを受け取ったら.envと鍵ファイルを読み直して鍵を差し替える
+// 読み込みに失敗した場合は現在の鍵を使い続ける
+func reloadJWTKeySetOnSIGHUP(ctx context.Context) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			if err := godotenv.Overload(); err != nil {
+				log.Info("No .env file found", log.Ferror(err))
+			}
+			keySet, err := loadJWTKeySet(ctx)
+			if err != nil {
+				log.Error("Failed to reload JWT signing keys", log.Ferror(err))
+				continue
+			}
+			auth.UseKeySet(keySet)
+			log.Info("JWT signing keys reloaded", log.Fstring("kid", keySet.ActiveKeyID()))
+		}
+	}
+}
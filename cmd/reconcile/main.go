@@ -0,0 +1,44 @@
+// reconcile 台帳(Coin_Transactions)の合計とUser_Balancesの残高が、ユーザ・通貨ごとに一致しているかを検証する
+// 不一致のユーザが見つかった場合は終了コード1で終了する
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/tusmasoma/go-tech-dojo/infra/mysql"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Info("No .env file found", log.Ferror(err))
+	}
+	os.Exit(run(context.Background()))
+}
+
+func run(ctx context.Context) int {
+	db, err := mysql.NewMySQLDB(ctx)
+	if err != nil {
+		log.Error("Failed to connect to DB", log.Ferror(err))
+		return 1
+	}
+	defer db.Close()
+
+	coinUseCase := usecase.NewCoinUseCase(mysql.NewCoinTransactionRepository(db))
+	mismatches, err := coinUseCase.ReconcileCoins(ctx)
+	if err != nil {
+		return 1
+	}
+	if len(mismatches) > 0 {
+		log.Error("Coin ledger is out of balance", log.Fint("mismatches", len(mismatches)))
+		return 1
+	}
+	log.Info("Coin ledger is balanced")
+	return 0
+}
@@ -10,9 +10,14 @@ import (
 )
 
 const (
-	dbPrefix     = "MYSQL_"
-	cachePrefix  = "REDIS_"
-	serverPrefix = "SERVER_"
+	dbPrefix          = "MYSQL_"
+	cachePrefix       = "REDIS_"
+	serverPrefix      = "SERVER_"
+	passwordPrefix    = "PASSWORD_"
+	jwtPrefix         = "JWT_"
+	gamePrefix        = "GAME_"
+	idempotencyPrefix = "IDEMPOTENCY_"
+	gachaPrefix       = "GACHA_"
 )
 
 type DBConfig struct {
@@ -37,6 +42,54 @@ type ServerConfig struct {
 	PreflightCacheDurationSec int           `env:"PREFLIGHT_CACHE_DURATION_SEC,default=300"`
 }
 
+type PasswordConfig struct {
+	HashAlgorithm string `env:"HASH_ALGORITHM,default=argon2id"`
+	BcryptCost    int    `env:"BCRYPT_COST,default=12"`
+	Argon2Time    uint32 `env:"ARGON2_TIME,default=2"`
+	Argon2Memory  uint32 `env:"ARGON2_MEMORY,default=19456"`
+	Argon2Threads uint8  `env:"ARGON2_THREADS,default=1"`
+	ScryptLogN    uint8  `env:"SCRYPT_LOG_N,default=17"`
+	ScryptR       int    `env:"SCRYPT_R,default=8"`
+	ScryptP       int    `env:"SCRYPT_P,default=1"`
+}
+
+// JWTConfig 鍵ファイルはkid:pathの形式でカンマ区切りで指定する
+// AllowEphemeralKeyは開発環境専用で、鍵ファイルが無い場合に起動ごとに生成される鍵を使う
+type JWTConfig struct {
+	Issuer            string            `env:"ISSUER,default=go-tech-dojo"`
+	Audience          string            `env:"AUDIENCE,default=go-tech-dojo-api"`
+	ActiveKeyID       string            `env:"ACTIVE_KEY_ID"`
+	PrivateKeyFiles   map[string]string `env:"PRIVATE_KEY_FILES"`
+	PublicKeyFiles    map[string]string `env:"PUBLIC_KEY_FILES"`
+	AllowEphemeralKey bool              `env:"ALLOW_EPHEMERAL_KEY,default=false"`
+}
+
+// GameConfig SessionSecretはゲームセッションのトークンの署名に使う
+// 複数のサーバで同じ値を設定する必要がある
+// AllowEphemeralSecretは開発環境専用で、SessionSecretが無い場合に起動ごとに生成される鍵を使う
+type GameConfig struct {
+	SessionSecret        string        `env:"SESSION_SECRET"`
+	AllowEphemeralSecret bool          `env:"ALLOW_EPHEMERAL_SECRET,default=false"`
+	MinDuration          time.Duration `env:"MIN_DURATION,default=10s"`
+	MaxDuration          time.Duration `env:"MAX_DURATION,default=30m"`
+	MaxScorePerSecond    int           `env:"MAX_SCORE_PER_SECOND,default=50"`
+}
+
+// IdempotencyConfig TTLの間はIdempotency-Keyごとのレスポンスを保存する。
+// 処理中の予約はInProgressTTLで失効し、その後は同じキーで再実行できる
+type IdempotencyConfig struct {
+	TTL           time.Duration `env:"TTL,default=24h"`
+	InProgressTTL time.Duration `env:"IN_PROGRESS_TTL,default=1m"`
+}
+
+// GachaConfig 天井の設定。最高レアリティが出ないまま抽選を続けると、
+// SoftPity回目から排出率がSoftPityRateStepずつ上がり、HardPity回目で必ず排出される
+type GachaConfig struct {
+	SoftPity         int     `env:"SOFT_PITY,default=74"`
+	HardPity         int     `env:"HARD_PITY,default=90"`
+	SoftPityRateStep float64 `env:"SOFT_PITY_RATE_STEP,default=0.06"`
+}
+
 func NewDBConfig(ctx context.Context) (*DBConfig, error) {
 	conf := &DBConfig{}
 	pl := envconfig.PrefixLookuper(dbPrefix, envconfig.OsLookuper())
@@ -66,3 +119,53 @@ func NewServerConfig(ctx context.Context) (*ServerConfig, error) {
 	}
 	return conf, nil
 }
+
+func NewPasswordConfig(ctx context.Context) (*PasswordConfig, error) {
+	conf := &PasswordConfig{}
+	pl := envconfig.PrefixLookuper(passwordPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, conf, pl); err != nil {
+		log.Error("Failed to load password config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewJWTConfig(ctx context.Context) (*JWTConfig, error) {
+	conf := &JWTConfig{}
+	pl := envconfig.PrefixLookuper(jwtPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, conf, pl); err != nil {
+		log.Error("Failed to load jwt config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewGameConfig(ctx context.Context) (*GameConfig, error) {
+	conf := &GameConfig{}
+	pl := envconfig.PrefixLookuper(gamePrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, conf, pl); err != nil {
+		log.Error("Failed to load game config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewIdempotencyConfig(ctx context.Context) (*IdempotencyConfig, error) {
+	conf := &IdempotencyConfig{}
+	pl := envconfig.PrefixLookuper(idempotencyPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, conf, pl); err != nil {
+		log.Error("Failed to load idempotency config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewGachaConfig(ctx context.Context) (*GachaConfig, error) {
+	conf := &GachaConfig{}
+	pl := envconfig.PrefixLookuper(gachaPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, conf, pl); err != nil {
+		log.Error("Failed to load gacha config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
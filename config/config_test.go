@@ -161,3 +161,253 @@ func Test_NewServerConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewPasswordConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *PasswordConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &PasswordConfig{
+				HashAlgorithm: "argon2id",
+				BcryptCost:    12,
+				Argon2Time:    2,
+				Argon2Memory:  19456,
+				Argon2Threads: 1,
+				ScryptLogN:    17,
+				ScryptR:       8,
+				ScryptP:       1,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
+				t.Setenv("PASSWORD_BCRYPT_COST", "10")
+			},
+			want: &PasswordConfig{
+				HashAlgorithm: "bcrypt",
+				BcryptCost:    10,
+				Argon2Time:    2,
+				Argon2Memory:  19456,
+				Argon2Threads: 1,
+				ScryptLogN:    17,
+				ScryptR:       8,
+				ScryptP:       1,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewPasswordConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewJWTConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *JWTConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &JWTConfig{
+				Issuer:   "go-tech-dojo",
+				Audience: "go-tech-dojo-api",
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("JWT_ISSUER", "https://dojo.example.com")
+				t.Setenv("JWT_AUDIENCE", "dojo-api")
+				t.Setenv("JWT_ACTIVE_KEY_ID", "2024-02")
+				t.Setenv("JWT_PRIVATE_KEY_FILES", "2024-02:/etc/keys/2024-02.pem")
+				t.Setenv("JWT_PUBLIC_KEY_FILES", "2024-01:/etc/keys/2024-01.pub.pem")
+				t.Setenv("JWT_ALLOW_EPHEMERAL_KEY", "true")
+			},
+			want: &JWTConfig{
+				Issuer:            "https://dojo.example.com",
+				Audience:          "dojo-api",
+				ActiveKeyID:       "2024-02",
+				PrivateKeyFiles:   map[string]string{"2024-02": "/etc/keys/2024-02.pem"},
+				PublicKeyFiles:    map[string]string{"2024-01": "/etc/keys/2024-01.pub.pem"},
+				AllowEphemeralKey: true,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewJWTConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewGameConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *GameConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &GameConfig{
+				MinDuration:       10 * time.Second,
+				MaxDuration:       30 * time.Minute,
+				MaxScorePerSecond: 50,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("GAME_SESSION_SECRET", "secret")
+				t.Setenv("GAME_ALLOW_EPHEMERAL_SECRET", "true")
+				t.Setenv("GAME_MIN_DURATION", "5s")
+				t.Setenv("GAME_MAX_DURATION", "1h")
+				t.Setenv("GAME_MAX_SCORE_PER_SECOND", "100")
+			},
+			want: &GameConfig{
+				SessionSecret:        "secret",
+				AllowEphemeralSecret: true,
+				MinDuration:          5 * time.Second,
+				MaxDuration:          time.Hour,
+				MaxScorePerSecond:    100,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewGameConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewIdempotencyConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *IdempotencyConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &IdempotencyConfig{
+				TTL:           24 * time.Hour,
+				InProgressTTL: time.Minute,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("IDEMPOTENCY_TTL", "1h")
+				t.Setenv("IDEMPOTENCY_IN_PROGRESS_TTL", "30s")
+			},
+			want: &IdempotencyConfig{
+				TTL:           time.Hour,
+				InProgressTTL: 30 * time.Second,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewIdempotencyConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewGachaConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *GachaConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &GachaConfig{
+				SoftPity:         74,
+				HardPity:         90,
+				SoftPityRateStep: 0.06,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("GACHA_SOFT_PITY", "50")
+				t.Setenv("GACHA_HARD_PITY", "80")
+				t.Setenv("GACHA_SOFT_PITY_RATE_STEP", "0.1")
+			},
+			want: &GachaConfig{
+				SoftPity:         50,
+				HardPity:         80,
+				SoftPityRateStep: 0.1,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewGachaConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
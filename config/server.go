@@ -4,6 +4,15 @@ import "errors"
 
 type ContextKey string
 
-const ContextUserIDKey ContextKey = "userID"
+const (
+	// ContextPrincipalKey 認証されたユーザのIDと役割(model.Principal)
+	ContextPrincipalKey ContextKey = "principal"
+	ContextTokenKey     ContextKey = "token"
+)
 
-var ErrCacheMiss = errors.New("cache: key not found")
+var (
+	ErrCacheMiss = errors.New("cache: key not found")
+	ErrNotFound  = errors.New("record not found")
+	// ErrReferenced 他のレコードから参照されているため削除できない
+	ErrReferenced = errors.New("record is referenced by other records")
+)
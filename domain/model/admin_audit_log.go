@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// AdminAuditAction 管理者が行った操作の種類
+type AdminAuditAction string
+
+const (
+	AuditActionUpdateUserStats AdminAuditAction = "update_user_stats"
+	AuditActionUpdateUserRole  AdminAuditAction = "update_user_role"
+)
+
+// AuditChange 項目ごとの変更前後の値
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AdminAuditLog 管理者が誰の何をどう変更したかの記録
+// 監査のために追記のみ行い、更新・削除はしない
+type AdminAuditLog struct {
+	ID        string                 `json:"id"`
+	ActorID   string                 `json:"actor_id"`
+	Action    AdminAuditAction       `json:"action"`
+	TargetID  string                 `json:"target_id"`
+	Changes   map[string]AuditChange `json:"changes"`
+	Note      string                 `json:"note"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func NewAdminAuditLog(actorID string, action AdminAuditAction, targetID string, changes map[string]AuditChange, note string) (*AdminAuditLog, error) {
+	if actorID == "" || action == "" || targetID == "" {
+		log.Error("ActorID, Action or TargetID is empty", log.Fstring("actorID", actorID), log.Fstring("targetID", targetID))
+		return nil, fmt.Errorf("actorID, action or targetID is empty")
+	}
+	if changes == nil {
+		changes = map[string]AuditChange{}
+	}
+	return &AdminAuditLog{
+		ID:        uuid.New().String(),
+		ActorID:   actorID,
+		Action:    action,
+		TargetID:  targetID,
+		Changes:   changes,
+		Note:      note,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}, nil
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"sync"
+)
+
+var errEmptyPool = errors.New("failed to pick an item")
+
+// AliasTable Walker/Voseのエイリアス法で、重み付きの抽選を排出対象の数によらず定数時間で行うための表
+// 確率を整数で持つため、Weightに比例した排出確率を誤差なく再現する
+type AliasTable struct {
+	items Collections
+	total int
+	// prob i番目の枠を選んだときに、total分のprob[i]の確率でitems[i]、それ以外はitems[alias[i]]を排出する
+	prob  []int
+	alias []int
+}
+
+func NewAliasTable(collections Collections) (*AliasTable, error) {
+	n := len(collections)
+	total := collections.TotalWeight()
+	if n == 0 || total <= 0 {
+		return nil, errEmptyPool
+	}
+
+	t := &AliasTable{
+		items: collections,
+		total: total,
+		prob:  make([]int, n),
+		alias: make([]int, n),
+	}
+	// 各枠の重みをn倍し、totalを1枠分として過不足を他の枠から埋める
+	scaled := make([]int, n)
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, item := range collections {
+		scaled[i] = item.Weight * n
+		if scaled[i] < total {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		t.prob[s] = scaled[s]
+		t.alias[s] = l
+		scaled[l] -= total - scaled[s]
+		if scaled[l] < total {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+	for _, i := range append(small, large...) {
+		t.prob[i] = total
+		t.alias[i] = i
+	}
+	return t, nil
+}
+
+func (t *AliasTable) Sample(rng RandomSource) *Collection {
+	i := rng.Intn(len(t.items))
+	if rng.Intn(t.total) < t.prob[i] {
+		return t.items[i]
+	}
+	return t.items[t.alias[i]]
+}
+
+// GachaPool 排出対象から抽選用の表を前計算したもの
+// 天井のために最高レアリティとそれ以外を分けた表も持ち、Versionが変わらない間は使い回す
+type GachaPool struct {
+	// Version 排出対象のアイテムと重みから計算した値で、排出対象が変わると変わる
+	Version   string
+	topRarity int
+	// baseRate 天井を考慮しない場合に最高レアリティが排出される確率
+	baseRate float64
+	top      *AliasTable
+	// others 最高レアリティ以外が排出されない場合はnil
+	others *AliasTable
+
+	mu sync.Mutex
+	// filtered MinRarityごとの、そのレアリティ以上のアイテムに絞った排出対象
+	filtered map[int]*GachaPool
+}
+
+func NewGachaPool(collections Collections) (*GachaPool, error) {
+	var topRarity int
+	for _, item := range collections {
+		if item.Weight > 0 && item.Rarity > topRarity {
+			topRarity = item.Rarity
+		}
+	}
+	var top, others Collections
+	for _, item := range collections {
+		if item.Rarity == topRarity {
+			top = append(top, item)
+		} else {
+			others = append(others, item)
+		}
+	}
+
+	topTable, err := NewAliasTable(top)
+	if err != nil {
+		return nil, err
+	}
+	pool := &GachaPool{
+		Version:   collections.Fingerprint(),
+		topRarity: topRarity,
+		baseRate:  1,
+		top:       topTable,
+		filtered:  make(map[int]*GachaPool),
+	}
+	if others.TotalWeight() > 0 {
+		if pool.others, err = NewAliasTable(others); err != nil {
+			return nil, err
+		}
+		pool.baseRate = float64(top.TotalWeight()) / float64(collections.TotalWeight())
+	}
+	return pool, nil
+}
+
+// Filter minRarity以上のアイテムに絞った排出対象。該当するアイテムがない場合はnilを返す
+func (p *GachaPool) Filter(minRarity int) *GachaPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if filtered, ok := p.filtered[minRarity]; ok {
+		return filtered
+	}
+
+	var items Collections
+	for _, table := range []*AliasTable{p.top, p.others} {
+		if table == nil {
+			continue
+		}
+		for _, item := range table.items {
+			if item.Rarity >= minRarity && item.Weight > 0 {
+				items = append(items, item)
+			}
+		}
+	}
+	filtered, err := NewGachaPool(items)
+	if err != nil {
+		filtered = nil
+	}
+	p.filtered[minRarity] = filtered
+	return filtered
+}
+
+// Fingerprint アイテムの内容と並び順から計算するハッシュ値
+func (cs Collections) Fingerprint() string {
+	h := fnv.New64a()
+	var buf [16]byte
+	for _, c := range cs {
+		binary.BigEndian.PutUint32(buf[:4], uint32(len(c.ID)))
+		binary.BigEndian.PutUint32(buf[4:8], uint32(len(c.Name)))
+		binary.BigEndian.PutUint32(buf[8:12], uint32(c.Rarity))
+		binary.BigEndian.PutUint32(buf[12:], uint32(c.Weight))
+		h.Write(buf[:])
+		h.Write([]byte(c.ID))
+		h.Write([]byte(c.Name))
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,200 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestModel_NewAliasTable(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name        string
+		collections Collections
+		wantErr     bool
+	}{
+		{
+			name: "success: uneven weights",
+			collections: Collections{
+				{ID: "a", Weight: 40}, {ID: "b", Weight: 30}, {ID: "c", Weight: 20}, {ID: "d", Weight: 9}, {ID: "e", Weight: 1},
+			},
+		},
+		{
+			name:        "success: zero weight is never drawn",
+			collections: Collections{{ID: "a", Weight: 3}, {ID: "b", Weight: 0}, {ID: "c", Weight: 7}},
+		},
+		{
+			name:        "success: single item",
+			collections: Collections{{ID: "a", Weight: 1}},
+		},
+		{name: "Fail: empty", collections: Collections{}, wantErr: true},
+		{name: "Fail: all weights are zero", collections: Collections{{ID: "a", Weight: 0}}, wantErr: true},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			table, err := NewAliasTable(tt.collections)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAliasTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// 各枠からアイテムに割り当てられた確率の合計が、線形の走査で引いた場合と一致することを確かめる
+			n := len(tt.collections)
+			got := make([]int, n)
+			for i := 0; i < n; i++ {
+				got[i] += table.prob[i]
+				got[table.alias[i]] += table.total - table.prob[i]
+			}
+			for i, c := range tt.collections {
+				if want := c.Weight * n; got[i] != want {
+					t.Errorf("NewAliasTable() weight of %v = %v/%v, want %v/%v", c.ID, got[i], n*table.total, want, n*table.total)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_AliasTable_Sample_ChiSquared(t *testing.T) {
+	t.Parallel()
+
+	collections := Collections{
+		{ID: "a", Rarity: 1, Weight: 40},
+		{ID: "b", Rarity: 1, Weight: 30},
+		{ID: "c", Rarity: 2, Weight: 20},
+		{ID: "d", Rarity: 3, Weight: 9},
+		{ID: "e", Rarity: 5, Weight: 1},
+	}
+	table, err := NewAliasTable(collections)
+	if err != nil {
+		t.Fatalf("NewAliasTable() error = %v", err)
+	}
+
+	const draws = 100000
+	rng := NewSeededRandomSource(5)
+	observed := make(map[string]int, len(collections))
+	for i := 0; i < draws; i++ {
+		observed[table.Sample(rng).ID]++
+	}
+
+	var chiSquared float64
+	total := float64(collections.TotalWeight())
+	for _, c := range collections {
+		expected := draws * float64(c.Weight) / total
+		diff := float64(observed[c.ID]) - expected
+		chiSquared += diff * diff / expected
+	}
+	if chiSquared > chiSquaredCritical {
+		t.Errorf("chi-squared = %v exceeds %v, observed %v", chiSquared, chiSquaredCritical, observed)
+	}
+}
+
+func TestModel_GachaPool_Filter(t *testing.T) {
+	t.Parallel()
+
+	pool := mustNewGachaPool(t, Collections{
+		{ID: "n", Rarity: 1, Weight: 90},
+		{ID: "sr", Rarity: 3, Weight: 9},
+		{ID: "ssr", Rarity: 5, Weight: 1},
+		{ID: "ur", Rarity: 6, Weight: 0},
+	})
+
+	filtered := pool.Filter(3)
+	if filtered == nil {
+		t.Fatal("Filter(3) = nil")
+	}
+	if filtered != pool.Filter(3) {
+		t.Error("Filter() should reuse the pool for the same rarity")
+	}
+	if filtered.topRarity != 5 || filtered.baseRate != 0.1 {
+		t.Errorf("Filter(3) topRarity = %v, baseRate = %v, want 5 and 0.1", filtered.topRarity, filtered.baseRate)
+	}
+	if got := pool.Filter(6); got != nil {
+		t.Errorf("Filter(6) = %v, want nil", got)
+	}
+}
+
+func TestModel_Collections_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	base := Collections{{ID: "a", Name: "A", Rarity: 1, Weight: 10}, {ID: "b", Name: "B", Rarity: 3, Weight: 1}}
+	patterns := []struct {
+		name        string
+		collections Collections
+		same        bool
+	}{
+		{name: "same items", collections: Collections{{ID: "a", Name: "A", Rarity: 1, Weight: 10}, {ID: "b", Name: "B", Rarity: 3, Weight: 1}}, same: true},
+		{name: "weight changed", collections: Collections{{ID: "a", Name: "A", Rarity: 1, Weight: 11}, {ID: "b", Name: "B", Rarity: 3, Weight: 1}}},
+		{name: "name changed", collections: Collections{{ID: "a", Name: "AA", Rarity: 1, Weight: 10}, {ID: "b", Name: "B", Rarity: 3, Weight: 1}}},
+		{name: "item added", collections: append(base[:len(base):len(base)], &Collection{ID: "c", Name: "C", Rarity: 1, Weight: 1})},
+		{name: "order changed", collections: Collections{base[1], base[0]}},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.collections.Fingerprint() == base.Fingerprint(); got != tt.same {
+				t.Errorf("Fingerprint() same = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func benchmarkCollections(n int) Collections {
+	collections := make(Collections, n)
+	for i := range collections {
+		collections[i] = &Collection{ID: fmt.Sprintf("item-%d", i), Rarity: i%5 + 1, Weight: i%100 + 1}
+	}
+	return collections
+}
+
+var benchmarkSizes = []int{10, 1000, 5000}
+
+func BenchmarkGacha_Draw(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		collections := benchmarkCollections(n)
+		gacha := NewGacha(NewSeededRandomSource(1))
+		b.Run(fmt.Sprintf("items=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := gacha.Draw(collections); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAliasTable_Sample(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		table, err := NewAliasTable(benchmarkCollections(n))
+		if err != nil {
+			b.Fatal(err)
+		}
+		rng := NewSeededRandomSource(1)
+		b.Run(fmt.Sprintf("items=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				table.Sample(rng)
+			}
+		})
+	}
+}
+
+func BenchmarkNewAliasTable(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		collections := benchmarkCollections(n)
+		b.Run(fmt.Sprintf("items=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := NewAliasTable(collections); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// ErrBannerInactive 開催期間外のガチャは引けない
+var ErrBannerInactive = errors.New("banner is not active")
+
+// BannerItem ガチャの排出対象のアイテムとその重み
+type BannerItem struct {
+	CollectionID string `json:"collection_id"`
+	Weight       int    `json:"weight"`
+	// RateUp ピックアップ対象のアイテム。排出率はWeightで調整する
+	RateUp bool `json:"rate_up"`
+}
+
+// MultiDrawRule まとめて引いた場合の保証。Drawsが0の場合は保証しない
+type MultiDrawRule struct {
+	// Draws この回数ごとに、少なくとも1つはMinRarity以上のアイテムを排出する
+	Draws     int `json:"draws"`
+	MinRarity int `json:"min_rarity"`
+}
+
+// Banner 開催期間・コスト・排出対象が異なるガチャ
+type Banner struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+	// Currency Costの通貨。支払いに使う通貨とその順序はWallet.Payに従う
+	Currency  Currency      `json:"currency"`
+	StartAt   time.Time     `json:"start_at"`
+	EndAt     time.Time     `json:"end_at"`
+	Items     []BannerItem  `json:"items"`
+	Guarantee MultiDrawRule `json:"guarantee"`
+}
+
+func NewBanner(
+	name string,
+	cost int,
+	currency Currency,
+	startAt, endAt time.Time,
+	items []BannerItem,
+	guarantee MultiDrawRule,
+) (*Banner, error) {
+	if name == "" {
+		log.Error("Name is empty")
+		return nil, fmt.Errorf("name is empty")
+	}
+	if cost <= 0 {
+		log.Error("Cost is invalid", log.Fint("cost", cost))
+		return nil, fmt.Errorf("cost is invalid")
+	}
+	if !currency.IsValid() {
+		log.Error("Currency is invalid", log.Fstring("currency", string(currency)))
+		return nil, ErrInvalidCurrency
+	}
+	if !endAt.After(startAt) {
+		log.Error("EndAt is not after StartAt")
+		return nil, fmt.Errorf("end_at must be after start_at")
+	}
+	if len(items) == 0 {
+		log.Error("Items are empty")
+		return nil, fmt.Errorf("items are empty")
+	}
+	seen := make(map[string]bool, len(items))
+	var totalWeight int
+	for _, item := range items {
+		if item.CollectionID == "" || seen[item.CollectionID] {
+			log.Error("CollectionID is empty or duplicated", log.Fstring("collectionID", item.CollectionID))
+			return nil, fmt.Errorf("collection_id is empty or duplicated")
+		}
+		if item.Weight < 0 {
+			log.Error("Weight is invalid", log.Fint("weight", item.Weight))
+			return nil, fmt.Errorf("weight is invalid")
+		}
+		seen[item.CollectionID] = true
+		totalWeight += item.Weight
+	}
+	if totalWeight == 0 {
+		log.Error("Total weight is 0")
+		return nil, fmt.Errorf("total weight is 0")
+	}
+	if guarantee.Draws < 0 || (guarantee.Draws > 0 && (guarantee.MinRarity <= 0 || guarantee.MinRarity > 5)) {
+		log.Error("Guarantee is invalid", log.Fint("draws", guarantee.Draws), log.Fint("minRarity", guarantee.MinRarity))
+		return nil, fmt.Errorf("guarantee is invalid")
+	}
+	return &Banner{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Cost:      cost,
+		Currency:  currency,
+		StartAt:   startAt,
+		EndAt:     endAt,
+		Items:     items,
+		Guarantee: guarantee,
+	}, nil
+}
+
+// IsActive StartAtを含み、EndAtを含まない期間を開催中とする
+func (b *Banner) IsActive(now time.Time) bool {
+	return !now.Before(b.StartAt) && now.Before(b.EndAt)
+}
+
+func (b *Banner) DrawCost(times int) int {
+	return b.Cost * times
+}
+
+func (b *Banner) IsRateUp(collectionID string) bool {
+	for _, item := range b.Items {
+		if item.CollectionID == collectionID {
+			return item.RateUp
+		}
+	}
+	return false
+}
+
+// Pool 排出対象のアイテムを、Weightをバナーごとの重みに置き換えて返す
+// collectionsは全てのアイテムで、排出対象が含まれていない場合はエラーとする
+func (b *Banner) Pool(collections Collections) (Collections, error) {
+	byID := make(map[string]*Collection, len(collections))
+	for _, c := range collections {
+		byID[c.ID] = c
+	}
+	pool := make(Collections, 0, len(b.Items))
+	for _, item := range b.Items {
+		c, ok := byID[item.CollectionID]
+		if !ok {
+			log.Error("Collection of banner not found", log.Fstring("bannerID", b.ID), log.Fstring("collectionID", item.CollectionID))
+			return nil, fmt.Errorf("collection %s of banner %s not found", item.CollectionID, b.ID)
+		}
+		pool = append(pool, &Collection{
+			ID:     c.ID,
+			Name:   c.Name,
+			Rarity: c.Rarity,
+			Weight: item.Weight,
+		})
+	}
+	return pool, nil
+}
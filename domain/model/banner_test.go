@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestModel_NewBanner(t *testing.T) {
+	t.Parallel()
+
+	startAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endAt := startAt.Add(7 * 24 * time.Hour)
+	items := []BannerItem{{CollectionID: "a", Weight: 10}, {CollectionID: "b", Weight: 1, RateUp: true}}
+
+	patterns := []struct {
+		name     string
+		bname    string
+		cost     int
+		currency Currency
+		startAt  time.Time
+		endAt    time.Time
+		items    []BannerItem
+		rule     MultiDrawRule
+		err      error
+	}{
+		{name: "success", bname: "banner", cost: 100, startAt: startAt, endAt: endAt, items: items},
+		{name: "success: with guarantee", bname: "banner", cost: 100, startAt: startAt, endAt: endAt, items: items, rule: MultiDrawRule{Draws: 10, MinRarity: 4}},
+		{name: "success: priced in event tokens", bname: "banner", cost: 5, currency: CurrencyEventToken, startAt: startAt, endAt: endAt, items: items},
+		{name: "Fail: name is required", bname: "", cost: 100, startAt: startAt, endAt: endAt, items: items, err: fmt.Errorf("name is empty")},
+		{name: "Fail: cost must be positive", bname: "banner", cost: 0, startAt: startAt, endAt: endAt, items: items, err: fmt.Errorf("cost is invalid")},
+		{name: "Fail: unknown currency", bname: "banner", cost: 100, currency: "diamond", startAt: startAt, endAt: endAt, items: items, err: ErrInvalidCurrency},
+		{name: "Fail: period is reversed", bname: "banner", cost: 100, startAt: endAt, endAt: startAt, items: items, err: fmt.Errorf("end_at must be after start_at")},
+		{name: "Fail: items are required", bname: "banner", cost: 100, startAt: startAt, endAt: endAt, err: fmt.Errorf("items are empty")},
+		{
+			name: "Fail: duplicated item", bname: "banner", cost: 100, startAt: startAt, endAt: endAt,
+			items: []BannerItem{{CollectionID: "a", Weight: 1}, {CollectionID: "a", Weight: 1}},
+			err:   fmt.Errorf("collection_id is empty or duplicated"),
+		},
+		{
+			name: "Fail: total weight is 0", bname: "banner", cost: 100, startAt: startAt, endAt: endAt,
+			items: []BannerItem{{CollectionID: "a", Weight: 0}},
+			err:   fmt.Errorf("total weight is 0"),
+		},
+		{
+			name: "Fail: guarantee without rarity", bname: "banner", cost: 100, startAt: startAt, endAt: endAt, items: items,
+			rule: MultiDrawRule{Draws: 10},
+			err:  fmt.Errorf("guarantee is invalid"),
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			currency := tt.currency
+			if currency == "" {
+				currency = CurrencyCoin
+			}
+			banner, err := NewBanner(tt.bname, tt.cost, currency, tt.startAt, tt.endAt, tt.items, tt.rule)
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("NewBanner() error = %v, wantErr %v", err, tt.err)
+			} else if err != nil {
+				if err.Error() != tt.err.Error() {
+					t.Errorf("NewBanner() error = %v, wantErr %v", err, tt.err)
+				}
+				return
+			}
+			if banner.ID == "" || banner.DrawCost(10) != tt.cost*10 || banner.Guarantee != tt.rule || banner.Currency != currency {
+				t.Errorf("NewBanner() = %+v", banner)
+			}
+		})
+	}
+}
+
+func TestModel_Banner_IsActive(t *testing.T) {
+	t.Parallel()
+
+	startAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	banner := &Banner{StartAt: startAt, EndAt: startAt.Add(time.Hour)}
+
+	patterns := []struct {
+		now  time.Time
+		want bool
+	}{
+		{now: startAt.Add(-time.Second), want: false},
+		{now: startAt, want: true},
+		{now: startAt.Add(30 * time.Minute), want: true},
+		{now: startAt.Add(time.Hour), want: false},
+	}
+	for _, tt := range patterns {
+		if got := banner.IsActive(tt.now); got != tt.want {
+			t.Errorf("IsActive(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestModel_Banner_Pool(t *testing.T) {
+	t.Parallel()
+
+	collections := Collections{
+		{ID: "a", Name: "a", Rarity: 1, Weight: 10},
+		{ID: "b", Name: "b", Rarity: 5, Weight: 1},
+		{ID: "c", Name: "c", Rarity: 3, Weight: 5},
+	}
+	banner := &Banner{ID: "banner", Items: []BannerItem{{CollectionID: "b", Weight: 30, RateUp: true}, {CollectionID: "a", Weight: 70}}}
+
+	pool, err := banner.Pool(collections)
+	if err != nil {
+		t.Fatalf("Pool() error = %v", err)
+	}
+	if len(pool) != 2 || pool[0].ID != "b" || pool[0].Weight != 30 || pool[1].Weight != 70 {
+		t.Errorf("Pool() = %v", pool)
+	}
+	// 全アイテムの重みは変更しない
+	if collections[1].Weight != 1 {
+		t.Errorf("Pool() modified collections: %v", collections[1])
+	}
+	if !banner.IsRateUp("b") || banner.IsRateUp("a") {
+		t.Errorf("IsRateUp() mismatch")
+	}
+
+	banner.Items = append(banner.Items, BannerItem{CollectionID: "missing", Weight: 1})
+	if _, err = banner.Pool(collections); err == nil {
+		t.Errorf("Pool() with missing collection error = nil")
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// CoinTransactionReason 通貨が増減した理由
+type CoinTransactionReason string
+
+const (
+	CoinReasonGameReward CoinTransactionReason = "game_reward"
+	CoinReasonGachaDraw  CoinTransactionReason = "gacha_draw"
+	// CoinReasonGachaDuplicateRefund 重複したアイテムをコインに変換していた頃の記録。現在は重複したアイテムを所持数に加算する
+	CoinReasonGachaDuplicateRefund CoinTransactionReason = "gacha_duplicate_refund"
+	CoinReasonAdminAdjustment      CoinTransactionReason = "admin_adjustment"
+	CoinReasonDismantle            CoinTransactionReason = "dismantle"
+	CoinReasonCraft                CoinTransactionReason = "craft"
+)
+
+// ErrInvalidCursor ページングのカーソルが不正
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CoinTransaction 通貨の増減を記録する台帳のエントリ
+// 追記のみで更新・削除はしないため、ユーザ・通貨ごとのDeltaの合計は常にUser_Balancesの残高と一致する
+type CoinTransaction struct {
+	ID           string                `json:"id"`
+	UserID       string                `json:"user_id"`
+	Currency     Currency              `json:"currency"`
+	Reason       CoinTransactionReason `json:"reason"`
+	Delta        int                   `json:"delta"`
+	BalanceAfter int                   `json:"balance_after"`
+	ReferenceID  string                `json:"reference_id"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+func NewCoinTransaction(
+	userID string,
+	currency Currency,
+	reason CoinTransactionReason,
+	delta, balanceAfter int,
+	referenceID string,
+) (*CoinTransaction, error) {
+	if userID == "" || reason == "" {
+		log.Error("UserID or Reason is empty", log.Fstring("userID", userID))
+		return nil, fmt.Errorf("userID or reason is empty")
+	}
+	if !currency.IsValid() {
+		log.Error("Currency is invalid", log.Fstring("userID", userID), log.Fstring("currency", string(currency)))
+		return nil, ErrInvalidCurrency
+	}
+	if balanceAfter < 0 {
+		log.Error("BalanceAfter is less than 0", log.Fstring("userID", userID), log.Fint("balanceAfter", balanceAfter))
+		return nil, fmt.Errorf("balanceAfter is less than 0")
+	}
+	return &CoinTransaction{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Currency:     currency,
+		Reason:       reason,
+		Delta:        delta,
+		BalanceAfter: balanceAfter,
+		ReferenceID:  referenceID,
+		// DATETIME(6)に保存されるため、マイクロ秒に丸めてDBから読み出した値と一致させる
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+// CoinTransactionCursor 履歴を新しい順に辿るためのカーソル
+// 同時刻のエントリがあってもIDで順序が決まるように(CreatedAt, ID)の組で位置を表す
+type CoinTransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func (c CoinTransactionCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID),
+	)
+}
+
+func DecodeCoinTransactionCursor(s string) (*CoinTransactionCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(b), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &CoinTransactionCursor{CreatedAt: t, ID: id}, nil
+}
+
+// CoinBalanceMismatch 台帳の合計とUser_Balancesの残高が一致しないユーザの通貨
+type CoinBalanceMismatch struct {
+	UserID        string   `json:"user_id"`
+	Currency      Currency `json:"currency"`
+	Balance       int      `json:"balance"`
+	LedgerBalance int      `json:"ledger_balance"`
+}
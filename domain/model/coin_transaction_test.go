@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestModel_NewCoinTransaction(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name         string
+		userID       string
+		currency     Currency
+		reason       CoinTransactionReason
+		delta        int
+		balanceAfter int
+		err          error
+	}{
+		{name: "success: credit", userID: "user", reason: CoinReasonGameReward, delta: 100, balanceAfter: 100},
+		{name: "success: debit", userID: "user", reason: CoinReasonGachaDraw, delta: -100, balanceAfter: 0},
+		{name: "Fail: userID is required", userID: "", reason: CoinReasonGameReward, err: fmt.Errorf("userID or reason is empty")},
+		{name: "Fail: reason is required", userID: "user", reason: "", err: fmt.Errorf("userID or reason is empty")},
+		{name: "Fail: negative balance", userID: "user", reason: CoinReasonGachaDraw, delta: -100, balanceAfter: -1, err: fmt.Errorf("balanceAfter is less than 0")},
+		{name: "Fail: invalid currency", userID: "user", currency: "diamond", reason: CoinReasonGachaDraw, delta: -100, err: ErrInvalidCurrency},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			currency := tt.currency
+			if currency == "" {
+				currency = CurrencyCoin
+			}
+			ct, err := NewCoinTransaction(tt.userID, currency, tt.reason, tt.delta, tt.balanceAfter, "ref")
+
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("NewCoinTransaction() error = %v, wantErr %v", err, tt.err)
+			} else if err != nil {
+				if err.Error() != tt.err.Error() {
+					t.Errorf("NewCoinTransaction() error = %v, wantErr %v", err, tt.err)
+				}
+				return
+			}
+
+			if ct.ID == "" || ct.Delta != tt.delta || ct.BalanceAfter != tt.balanceAfter {
+				t.Errorf("NewCoinTransaction() = %+v", ct)
+			}
+		})
+	}
+}
+
+func TestModel_CoinTransactionCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := CoinTransactionCursor{
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 678000, time.UTC),
+		ID:        "id",
+	}
+
+	got, err := DecodeCoinTransactionCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCoinTransactionCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(cursor.CreatedAt) || got.ID != cursor.ID {
+		t.Errorf("DecodeCoinTransactionCursor() = %+v, want %+v", got, cursor)
+	}
+
+	for _, invalid := range []string{"!!", "bm8tc2VwYXJhdG9y", "bm90LWEtdGltZXxpZA"} {
+		if _, err = DecodeCoinTransactionCursor(invalid); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCoinTransactionCursor(%q) error = %v, want %v", invalid, err, ErrInvalidCursor)
+		}
+	}
+}
@@ -2,14 +2,20 @@ package model
 
 import (
 	"fmt"
-	"math/rand"
-	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 )
 
+const (
+	// CollectionsCacheKey 全てのコレクションアイテムをキャッシュするキー
+	CollectionsCacheKey = "collections"
+
+	collectionNameMaxLength = 255
+)
+
 type Collection struct {
 	ID     string `db:"id" json:"id"`
 	Name   string `db:"name" json:"name"`
@@ -22,6 +28,10 @@ func NewCollection(name string, rarity, weight int) (*Collection, error) {
 		log.Error("Name is empty", log.Fstring("name", name))
 		return nil, fmt.Errorf("name is empty")
 	}
+	if utf8.RuneCountInString(name) > collectionNameMaxLength {
+		log.Error("Name is too long", log.Fint("length", utf8.RuneCountInString(name)))
+		return nil, fmt.Errorf("name is too long")
+	}
 	if rarity < 0 || rarity > 5 {
 		log.Error("Rarity is invalid", log.Fint("rarity", rarity))
 		return nil, fmt.Errorf("rarity is invalid")
@@ -48,9 +58,10 @@ func (cs Collections) TotalWeight() int {
 	return total
 }
 
-func (cs Collections) Shuffle() {
-	r := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // Use time.Now().UnixNano() as seed
-	r.Shuffle(len(cs), func(i, j int) {
+// Shuffle Fisher-Yatesで並び替える
+func (cs Collections) Shuffle(rng RandomSource) {
+	for i := len(cs) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
 		cs[i], cs[j] = cs[j], cs[i]
-	})
+	}
 }
@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -67,6 +68,25 @@ func TestModel_NewCollection(t *testing.T) {
 				err:        fmt.Errorf("name is empty"),
 			},
 		},
+		{
+			name: "Fail: name is too long",
+			arg: struct {
+				name   string
+				rarity int
+				weight int
+			}{
+				name:   strings.Repeat("a", 256),
+				rarity: 3,
+				weight: 10,
+			},
+			want: struct {
+				collection *Collection
+				err        error
+			}{
+				collection: nil,
+				err:        fmt.Errorf("name is too long"),
+			},
+		},
 		{
 			name: "Fail: rarity is invalid",
 			arg: struct {
@@ -185,20 +205,36 @@ func TestModel_Shuffle(t *testing.T) {
 	originalOrder := make(Collections, len(collections))
 	copy(originalOrder, collections)
 
-	shuffledDifferently := false
+	// 同じシードからは同じ並びになる
+	collections.Shuffle(NewSeededRandomSource(1))
+	again := make(Collections, len(originalOrder))
+	copy(again, originalOrder)
+	again.Shuffle(NewSeededRandomSource(1))
+	if !reflect.DeepEqual(collections, again) {
+		t.Errorf("Shuffle() with the same seed = %v, want %v", again, collections)
+	}
 
-	for i := 0; i < 3; i++ {
-		collections.Shuffle()
+	// 並び替えても要素は変わらない
+	seen := make(map[string]bool, len(collections))
+	for _, c := range collections {
+		seen[c.ID] = true
+	}
+	for _, c := range originalOrder {
+		if !seen[c.ID] {
+			t.Errorf("Shuffle() lost %v", c)
+		}
+	}
 
+	shuffledDifferently := false
+	for seed := int64(1); seed <= 10; seed++ {
+		copy(collections, originalOrder)
+		collections.Shuffle(NewSeededRandomSource(seed))
 		if !reflect.DeepEqual(collections, originalOrder) {
 			shuffledDifferently = true
 			break
 		}
-
-		copy(collections, originalOrder)
 	}
-
 	if !shuffledDifferently {
-		t.Errorf("Shuffle() did not produce a different order in 3 attempts")
+		t.Errorf("Shuffle() did not produce a different order with 10 seeds")
 	}
 }
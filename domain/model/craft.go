@@ -0,0 +1,31 @@
+package model
+
+import "errors"
+
+var (
+	// ErrInvalidQuantity 分解する数が1未満
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	// ErrInsufficientItems 分解する数に対して重複して所持しているアイテムが足りない。最後の1個は分解できない
+	ErrInsufficientItems = errors.New("insufficient items")
+	// ErrNotCraftable 作成に必要な欠片が0以下のアイテムは、無償で増やせてしまうため作成できない
+	ErrNotCraftable = errors.New("item is not craftable")
+)
+
+const (
+	// DismantleShardsPerRarity 重複したアイテム1個を分解して得られる欠片(レアリティ倍)
+	DismantleShardsPerRarity = 10
+	// CraftShardsPerRarity アイテム1個の作成に必要な欠片(レアリティ倍)
+	CraftShardsPerRarity = 100
+)
+
+// DismantleShards アイテムをquantity個分解して得られる欠片
+func DismantleShards(item *Collection, quantity int) int {
+	return DismantleShardsPerRarity * item.Rarity * quantity
+}
+
+// CraftCost アイテムを1個作成するのに必要な欠片
+// 分解で得られる欠片より多くすることで、分解と作成の繰り返しで欠片が増えないようにする
+// レアリティ0のアイテムは0になるため、呼び出し側で作成を拒否する
+func CraftCost(item *Collection) int {
+	return CraftShardsPerRarity * item.Rarity
+}
@@ -0,0 +1,37 @@
+package model
+
+import "testing"
+
+func TestModel_CraftCost(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name          string
+		item          *Collection
+		quantity      int
+		wantDismantle int
+		wantCraft     int
+	}{
+		{name: "rarity 1", item: &Collection{ID: "n", Rarity: 1}, quantity: 1, wantDismantle: 10, wantCraft: 100},
+		{name: "rarity 3, multiple copies", item: &Collection{ID: "sr", Rarity: 3}, quantity: 4, wantDismantle: 120, wantCraft: 300},
+		{name: "rarity 0", item: &Collection{ID: "none", Rarity: 0}, quantity: 1, wantDismantle: 0, wantCraft: 0},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := DismantleShards(tt.item, tt.quantity); got != tt.wantDismantle {
+				t.Errorf("DismantleShards() = %v, want %v", got, tt.wantDismantle)
+			}
+			if got := CraftCost(tt.item); got != tt.wantCraft {
+				t.Errorf("CraftCost() = %v, want %v", got, tt.wantCraft)
+			}
+			// 1個分解して得た欠片で同じアイテムを作成できてはいけない
+			if CraftCost(tt.item) > 0 && DismantleShards(tt.item, 1) >= CraftCost(tt.item) {
+				t.Errorf("DismantleShards(1) = %v should be less than CraftCost() = %v", DismantleShards(tt.item, 1), CraftCost(tt.item))
+			}
+		})
+	}
+}
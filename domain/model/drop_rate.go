@@ -0,0 +1,60 @@
+package model
+
+import "sort"
+
+// DropRatesCacheKey バナーごとの排出確率をまとめてキャッシュするキー
+// 排出確率はコレクションアイテムから計算するため、CollectionsCacheKeyと同時に破棄する
+const DropRatesCacheKey = "drop_rates"
+
+type ItemDropRate struct {
+	CollectionID string  `json:"collection_id"`
+	Name         string  `json:"name"`
+	Rarity       int     `json:"rarity"`
+	Rate         float64 `json:"rate"`
+}
+
+type RarityDropRate struct {
+	Rarity int     `json:"rarity"`
+	Rate   float64 `json:"rate"`
+}
+
+// DropRates バナーの1回あたりの排出確率。天井や複数回の抽選の保証は含まない
+type DropRates struct {
+	BannerID string           `json:"banner_id"`
+	Items    []ItemDropRate   `json:"items"`
+	Rarities []RarityDropRate `json:"rarities"`
+}
+
+// NewDropRates poolはBanner.Poolで取得した、重みがバナーごとの値になっているアイテム
+// レアリティごとの排出確率はレアリティの高い順に並べる
+func NewDropRates(bannerID string, pool Collections) *DropRates {
+	rates := &DropRates{
+		BannerID: bannerID,
+		Items:    make([]ItemDropRate, 0, len(pool)),
+	}
+	total := pool.TotalWeight()
+	if total == 0 {
+		return rates
+	}
+
+	rarityWeights := make(map[int]int)
+	for _, item := range pool {
+		rates.Items = append(rates.Items, ItemDropRate{
+			CollectionID: item.ID,
+			Name:         item.Name,
+			Rarity:       item.Rarity,
+			Rate:         float64(item.Weight) / float64(total),
+		})
+		rarityWeights[item.Rarity] += item.Weight
+	}
+	for rarity, weight := range rarityWeights {
+		rates.Rarities = append(rates.Rarities, RarityDropRate{
+			Rarity: rarity,
+			Rate:   float64(weight) / float64(total),
+		})
+	}
+	sort.Slice(rates.Rarities, func(i, j int) bool {
+		return rates.Rarities[i].Rarity > rates.Rarities[j].Rarity
+	})
+	return rates
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModel_NewDropRates(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name string
+		pool Collections
+		want *DropRates
+	}{
+		{
+			name: "success",
+			pool: Collections{
+				{ID: "a", Name: "a", Rarity: 1, Weight: 60},
+				{ID: "b", Name: "b", Rarity: 1, Weight: 30},
+				{ID: "c", Name: "c", Rarity: 3, Weight: 10},
+			},
+			want: &DropRates{
+				BannerID: "banner",
+				Items: []ItemDropRate{
+					{CollectionID: "a", Name: "a", Rarity: 1, Rate: 0.6},
+					{CollectionID: "b", Name: "b", Rarity: 1, Rate: 0.3},
+					{CollectionID: "c", Name: "c", Rarity: 3, Rate: 0.1},
+				},
+				Rarities: []RarityDropRate{
+					{Rarity: 3, Rate: 0.1},
+					{Rarity: 1, Rate: 0.9},
+				},
+			},
+		},
+		{
+			name: "empty pool",
+			pool: Collections{},
+			want: &DropRates{BannerID: "banner", Items: []ItemDropRate{}},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NewDropRates("banner", tt.pool)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDropRates() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// ErrInvalidPeriod 期間の開始が終了と同じか、終了より後になっている
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// GachaDrawRecord ガチャで排出されたアイテム1枠分の記録
+// 問い合わせの際に抽選内容を確認できるよう、抽選と同じトランザクションで追記し、更新・削除はしない
+type GachaDrawRecord struct {
+	// DrawID 1回のリクエストで引いた枠に共通のID。コインの台帳のReferenceIDと同じ値になる
+	DrawID string `json:"draw_id"`
+	// Slot 抽選内での順番(0始まり)
+	Slot         int    `json:"slot"`
+	UserID       string `json:"user_id"`
+	BannerID     string `json:"banner_id"`
+	CollectionID string `json:"collection_id"`
+	Rarity       int    `json:"rarity"`
+	// Cost この枠の価格。通貨ごとに実際に消費した額は台帳に記録する
+	Cost     int      `json:"cost"`
+	Currency Currency `json:"currency"`
+	// PityCount この枠を引いた後の天井カウンタ
+	PityCount  int  `json:"pity_count"`
+	Guaranteed bool `json:"guaranteed"`
+	// RNG 抽選に使った乱数の種類
+	RNG string `json:"rng"`
+	// PoolVersion 抽選に使った排出対象のFingerprint
+	PoolVersion string    `json:"pool_version"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewGachaDrawRecord(
+	drawID string,
+	slot int,
+	userID string,
+	banner *Banner,
+	item *DrawnItem,
+	rng, poolVersion string,
+	createdAt time.Time,
+) (*GachaDrawRecord, error) {
+	if drawID == "" || userID == "" || banner == nil || banner.ID == "" {
+		log.Error("DrawID, UserID or BannerID is empty", log.Fstring("drawID", drawID), log.Fstring("userID", userID))
+		return nil, fmt.Errorf("drawID, userID or bannerID is empty")
+	}
+	if item == nil || item.Collection == nil {
+		log.Error("Drawn item is empty", log.Fstring("drawID", drawID))
+		return nil, fmt.Errorf("drawn item is empty")
+	}
+	return &GachaDrawRecord{
+		DrawID:       drawID,
+		Slot:         slot,
+		UserID:       userID,
+		BannerID:     banner.ID,
+		CollectionID: item.ID,
+		Rarity:       item.Rarity,
+		Cost:         banner.Cost,
+		Currency:     banner.Currency,
+		PityCount:    item.PityCount,
+		Guaranteed:   item.Guaranteed,
+		RNG:          rng,
+		PoolVersion:  poolVersion,
+		// DATETIME(6)に保存されるため、マイクロ秒に丸めてDBから読み出した値と一致させる
+		CreatedAt: createdAt.UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+// GachaDrawCursor 抽選の記録を新しい順に辿るためのカーソル
+// 同じ抽選の枠は同時刻になるため、(CreatedAt, DrawID, Slot)の組で位置を表す
+type GachaDrawCursor struct {
+	CreatedAt time.Time
+	DrawID    string
+	Slot      int
+}
+
+func (c GachaDrawCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.DrawID + "|" + strconv.Itoa(c.Slot)),
+	)
+}
+
+func DecodeGachaDrawCursor(s string) (*GachaDrawCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(b), "|")
+	if len(parts) != 3 || parts[1] == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	slot, err := strconv.Atoi(parts[2])
+	if err != nil || slot < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &GachaDrawCursor{CreatedAt: t, DrawID: parts[1], Slot: slot}, nil
+}
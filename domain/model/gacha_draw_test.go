@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestModel_NewGachaDrawRecord(t *testing.T) {
+	t.Parallel()
+
+	item := &DrawnItem{Collection: &Collection{ID: "ssr", Rarity: 5}, PityCount: 0, Guaranteed: true}
+	banner := &Banner{ID: "banner", Cost: 100, Currency: CurrencyGem}
+	patterns := []struct {
+		name   string
+		drawID string
+		userID string
+		banner *Banner
+		item   *DrawnItem
+		err    error
+	}{
+		{name: "success", drawID: "draw", userID: "user", banner: banner, item: item},
+		{name: "Fail: drawID is required", userID: "user", banner: banner, item: item, err: fmt.Errorf("drawID, userID or bannerID is empty")},
+		{name: "Fail: banner is required", drawID: "draw", userID: "user", item: item, err: fmt.Errorf("drawID, userID or bannerID is empty")},
+		{name: "Fail: item is required", drawID: "draw", userID: "user", banner: banner, err: fmt.Errorf("drawn item is empty")},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			record, err := NewGachaDrawRecord(tt.drawID, 2, tt.userID, tt.banner, tt.item, "crypto/rand", "version", time.Now())
+
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("NewGachaDrawRecord() error = %v, wantErr %v", err, tt.err)
+			} else if err != nil {
+				if err.Error() != tt.err.Error() {
+					t.Errorf("NewGachaDrawRecord() error = %v, wantErr %v", err, tt.err)
+				}
+				return
+			}
+
+			if record.Slot != 2 || record.CollectionID != "ssr" || record.Rarity != 5 || !record.Guaranteed || record.Cost != 100 || record.Currency != CurrencyGem {
+				t.Errorf("NewGachaDrawRecord() = %+v", record)
+			}
+		})
+	}
+}
+
+func TestModel_GachaDrawCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := GachaDrawCursor{
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 678000, time.UTC),
+		DrawID:    "draw",
+		Slot:      9,
+	}
+
+	got, err := DecodeGachaDrawCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeGachaDrawCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(cursor.CreatedAt) || got.DrawID != cursor.DrawID || got.Slot != cursor.Slot {
+		t.Errorf("DecodeGachaDrawCursor() = %+v, want %+v", got, cursor)
+	}
+
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for _, invalid := range []string{
+		"!!",
+		encode("2024-01-02T03:04:05Z|draw"),
+		encode("not-a-time|draw|0"),
+		encode("2024-01-02T03:04:05Z||0"),
+		encode("2024-01-02T03:04:05Z|draw|-1"),
+	} {
+		if _, err = DecodeGachaDrawCursor(invalid); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeGachaDrawCursor(%q) error = %v, want %v", invalid, err, ErrInvalidCursor)
+		}
+	}
+}
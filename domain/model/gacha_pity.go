@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// PityRules 天井のルール。SoftPity,HardPityが0の場合はその天井を設けない
+type PityRules struct {
+	// SoftPity この回数目の抽選から最高レアリティの排出率がSoftPityRateStepずつ上がる
+	SoftPity         int
+	SoftPityRateStep float64
+	// HardPity この回数目の抽選では最高レアリティが必ず排出される
+	HardPity int
+}
+
+// TopRarityRate count回連続で最高レアリティが出なかった後の抽選で、最高レアリティが排出される確率
+func (r PityRules) TopRarityRate(baseRate float64, count int) float64 {
+	draw := count + 1
+	if r.HardPity > 0 && draw >= r.HardPity {
+		return 1
+	}
+	if r.SoftPity > 0 && draw >= r.SoftPity {
+		return math.Min(baseRate+r.SoftPityRateStep*float64(draw-r.SoftPity+1), 1)
+	}
+	return baseRate
+}
+
+// GachaPity ユーザのバナーごとの天井カウンタ
+type GachaPity struct {
+	UserID   string `json:"user_id"`
+	BannerID string `json:"banner_id"`
+	// Count 最後に最高レアリティが排出されてからの抽選回数
+	Count int `json:"count"`
+}
+
+func NewGachaPity(userID, bannerID string) (*GachaPity, error) {
+	if userID == "" || bannerID == "" {
+		log.Error("UserID or BannerID is empty", log.Fstring("userID", userID), log.Fstring("bannerID", bannerID))
+		return nil, fmt.Errorf("userID or bannerID is empty")
+	}
+	return &GachaPity{
+		UserID:   userID,
+		BannerID: bannerID,
+	}, nil
+}
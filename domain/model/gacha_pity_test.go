@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestModel_NewGachaPity(t *testing.T) {
+	t.Parallel()
+
+	pity, err := NewGachaPity("user", "banner")
+	if err != nil {
+		t.Fatalf("NewGachaPity() error = %v", err)
+	}
+	if pity.UserID != "user" || pity.BannerID != "banner" || pity.Count != 0 {
+		t.Errorf("NewGachaPity() = %+v", pity)
+	}
+
+	wantErr := fmt.Errorf("userID or bannerID is empty")
+	if _, err = NewGachaPity("", "banner"); err == nil || err.Error() != wantErr.Error() {
+		t.Errorf("NewGachaPity() error = %v, wantErr %v", err, wantErr)
+	}
+}
+
+func TestModel_PityRules_TopRarityRate(t *testing.T) {
+	t.Parallel()
+
+	rules := PityRules{SoftPity: 5, SoftPityRateStep: 0.1, HardPity: 10}
+
+	patterns := []struct {
+		name  string
+		rules PityRules
+		count int
+		want  float64
+	}{
+		{name: "before soft pity", rules: rules, count: 3, want: 0.05},
+		{name: "first soft pity draw", rules: rules, count: 4, want: 0.15},
+		{name: "soft pity increases", rules: rules, count: 6, want: 0.35},
+		{name: "hard pity", rules: rules, count: 9, want: 1},
+		{name: "capped at 1", rules: PityRules{SoftPity: 1, SoftPityRateStep: 0.5}, count: 5, want: 1},
+		{name: "no pity", rules: PityRules{}, count: 1000, want: 0.05},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.rules.TopRarityRate(0.05, tt.count)
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("TopRarityRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_Gacha_DrawWithPity(t *testing.T) {
+	t.Parallel()
+
+	gacha := NewGacha(NewSeededRandomSource(4))
+	collections := Collections{
+		{ID: "common", Rarity: 1, Weight: 1000},
+		{ID: "top", Rarity: 5, Weight: 1},
+	}
+
+	// 天井に達した抽選では必ず最高レアリティが排出され、カウンタが戻る
+	pity := &GachaPity{UserID: "user", BannerID: "banner", Count: 9}
+	item := gacha.DrawWithPity(mustNewGachaPool(t, collections), pity, PityRules{HardPity: 10})
+	if item.ID != "top" || pity.Count != 0 {
+		t.Errorf("DrawWithPity() = %v, count = %v, want top and 0", item, pity.Count)
+	}
+
+	// 最高レアリティの重みが0の場合は、排出されうる中で最も高いレアリティを対象とする
+	pity = &GachaPity{UserID: "user", BannerID: "banner"}
+	collections = Collections{
+		{ID: "common", Rarity: 1, Weight: 1},
+		{ID: "top", Rarity: 5, Weight: 0},
+	}
+	pool := mustNewGachaPool(t, collections)
+	for i := 0; i < 3; i++ {
+		item = gacha.DrawWithPity(pool, pity, PityRules{HardPity: 10})
+		if item.ID != "common" || pity.Count != 0 {
+			t.Errorf("DrawWithPity() = %v, count = %v, want common and 0", item, pity.Count)
+		}
+	}
+
+	// 最高レアリティが出なかった場合はカウンタが増える
+	collections = Collections{
+		{ID: "common", Rarity: 1, Weight: 1},
+		{ID: "top", Rarity: 5, Weight: 1},
+	}
+	pool = mustNewGachaPool(t, collections)
+	pity = &GachaPity{UserID: "user", BannerID: "banner"}
+	for i := 0; i < 20; i++ {
+		before := pity.Count
+		item = gacha.DrawWithPity(pool, pity, PityRules{})
+		if (item.ID == "top" && pity.Count != 0) || (item.ID == "common" && pity.Count != before+1) {
+			t.Errorf("DrawWithPity() = %v, count = %v -> %v", item, before, pity.Count)
+		}
+	}
+}
@@ -1,14 +1,8 @@
 package model
 
-import (
-	"errors"
-	"math/rand"
-)
-
 const (
 	BaseReward      = 100 // ゲーム基本報酬コイン
 	ScoreMultiplier = 2   // ゲームスコア倍率
-	GachaCost       = 100 // ガチャコスト
 )
 
 type Game struct{}
@@ -24,24 +18,87 @@ func (g *Game) Reward(score int) int {
 	return 0
 }
 
-type Gacha struct{}
+type Gacha struct {
+	rng RandomSource
+}
 
-func NewGacha() *Gacha {
-	return &Gacha{}
+func NewGacha(rng RandomSource) *Gacha {
+	return &Gacha{
+		rng: rng,
+	}
 }
 
+// Draw 排出対象を先頭から走査して抽選する。同じ排出対象から何度も引く場合はGachaPoolを使う
 func (g *Gacha) Draw(collections Collections) (*Collection, error) {
-	// シャッフル 重み付け
-	target := rand.Intn(collections.TotalWeight()) //nolint:gosec // Use math/rand
+	total := collections.TotalWeight()
+	if total <= 0 {
+		return nil, errEmptyPool
+	}
+	// 重み付け
+	target := g.rng.Intn(total)
 	for _, item := range collections {
 		target -= item.Weight
 		if target < 0 {
 			return item, nil
 		}
 	}
-	return nil, errors.New("failed to pick an item")
+	return nil, errEmptyPool
+}
+
+// DrawWithPity 天井を考慮して抽選し、pityの抽選回数を更新する
+// 最高レアリティとそれ以外のどちらを排出するかを先に決め、それぞれの中では重みで抽選する
+func (g *Gacha) DrawWithPity(pool *GachaPool, pity *GachaPity, rules PityRules) *Collection {
+	table := pool.top
+	if pool.others != nil && g.rng.Float64() >= rules.TopRarityRate(pool.baseRate, pity.Count) {
+		table = pool.others
+	}
+	item := table.Sample(g.rng)
+
+	if item.Rarity == pool.topRarity {
+		pity.Count = 0
+	} else {
+		pity.Count++
+	}
+	return item
+}
+
+// DrawnItem DrawMultiで排出されたアイテムと、その枠を引いた後の天井の状態
+type DrawnItem struct {
+	*Collection
+	// PityCount この枠を引いた後の天井カウンタ
+	PityCount int
+	// Guaranteed 最低保証により引き直した枠
+	Guaranteed bool
 }
 
-func (g *Gacha) Cost(times int) int {
-	return GachaCost * times
+// DrawMulti times回抽選する
+// ruleのDraws回ごとにMinRarity以上のアイテムが排出されなかった場合は、その最後の枠をMinRarity以上のアイテムから引き直す
+// 排出対象にMinRarity以上のアイテムがない場合は引き直さない
+func (g *Gacha) DrawMulti(pool *GachaPool, times int, pity *GachaPity, rules PityRules, rule MultiDrawRule) []*DrawnItem {
+	var guaranteed *GachaPool
+	if rule.Draws > 0 {
+		guaranteed = pool.Filter(rule.MinRarity)
+	}
+
+	results := make([]*DrawnItem, 0, times)
+	var satisfied bool
+	for i := 0; i < times; i++ {
+		count := pity.Count
+		drawn := &DrawnItem{Collection: g.DrawWithPity(pool, pity, rules)}
+		if rule.Draws > 0 {
+			satisfied = satisfied || drawn.Rarity >= rule.MinRarity
+			if (i+1)%rule.Draws == 0 {
+				// 引き直した結果で天井カウンタを数え直す
+				if !satisfied && guaranteed != nil {
+					pity.Count = count
+					drawn.Collection = g.DrawWithPity(guaranteed, pity, rules)
+					drawn.Guaranteed = true
+				}
+				satisfied = false
+			}
+		}
+		drawn.PityCount = pity.Count
+		results = append(results, drawn)
+	}
+	return results
 }
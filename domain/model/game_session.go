@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// FinishでGameSessionが拒否される理由
+var (
+	ErrGameSessionInvalid  = errors.New("game session is invalid")
+	ErrGameSessionFinished = errors.New("game session is already finished")
+	ErrGameTooShort        = errors.New("game finished too quickly")
+	ErrGameSessionExpired  = errors.New("game session is expired")
+	ErrImplausibleScore    = errors.New("score is implausible for the play time")
+)
+
+// GameSession サーバ側で開始を記録したゲーム
+// 終了時にプレイ時間からスコアの妥当性を検証し、同じセッションでの二重の終了を拒否する
+type GameSession struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Seed       int64      `json:"seed"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Score      *int       `json:"score"`
+}
+
+// GameSessionRules プレイ時間とスコアの上限
+type GameSessionRules struct {
+	MinDuration       time.Duration
+	MaxDuration       time.Duration
+	MaxScorePerSecond int
+}
+
+func NewGameSession(userID string, seed int64, now time.Time) (*GameSession, error) {
+	if userID == "" {
+		log.Error("userID is required")
+		return nil, fmt.Errorf("userID is required")
+	}
+	return &GameSession{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Seed:      seed,
+		StartedAt: now.UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+// Finish セッションを終了済みにする
+// 検証に失敗した場合はセッションを変更しない
+func (gs *GameSession) Finish(userID string, score int, now time.Time, rules GameSessionRules) error {
+	if gs.UserID != userID {
+		return ErrGameSessionInvalid
+	}
+	if gs.FinishedAt != nil {
+		return ErrGameSessionFinished
+	}
+	duration := now.Sub(gs.StartedAt)
+	if duration < rules.MinDuration {
+		return ErrGameTooShort
+	}
+	if duration > rules.MaxDuration {
+		return ErrGameSessionExpired
+	}
+	if float64(score) > float64(rules.MaxScorePerSecond)*duration.Seconds() {
+		return ErrImplausibleScore
+	}
+
+	finishedAt := now.UTC().Truncate(time.Microsecond)
+	gs.FinishedAt = &finishedAt
+	gs.Score = &score
+	return nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestModel_GameSessionFinish(t *testing.T) {
+	t.Parallel()
+
+	rules := GameSessionRules{
+		MinDuration:       10 * time.Second,
+		MaxDuration:       10 * time.Minute,
+		MaxScorePerSecond: 10,
+	}
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(time.Minute)
+
+	patterns := []struct {
+		name     string
+		finished bool
+		userID   string
+		score    int
+		elapsed  time.Duration
+		wantErr  error
+	}{
+		{name: "success", userID: "user", score: 600, elapsed: time.Minute},
+		{name: "Fail: other user", userID: "other", score: 100, elapsed: time.Minute, wantErr: ErrGameSessionInvalid},
+		{name: "Fail: already finished", finished: true, userID: "user", score: 100, elapsed: time.Minute, wantErr: ErrGameSessionFinished},
+		{name: "Fail: too short", userID: "user", score: 0, elapsed: 9 * time.Second, wantErr: ErrGameTooShort},
+		{name: "Fail: expired", userID: "user", score: 100, elapsed: 11 * time.Minute, wantErr: ErrGameSessionExpired},
+		{name: "Fail: implausible score", userID: "user", score: 601, elapsed: time.Minute, wantErr: ErrImplausibleScore},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			session, err := NewGameSession("user", 1, startedAt)
+			if err != nil {
+				t.Fatalf("NewGameSession() error = %v", err)
+			}
+			if tt.finished {
+				session.FinishedAt = &finishedAt
+			}
+
+			err = session.Finish(tt.userID, tt.score, startedAt.Add(tt.elapsed), rules)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Finish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (session.FinishedAt == nil || *session.Score != tt.score) {
+				t.Errorf("Finish() session = %+v", session)
+			}
+			if tt.wantErr != nil && !tt.finished && session.FinishedAt != nil {
+				t.Errorf("Finish() modified session on error")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModel_Gacha_DrawMulti_Guarantee(t *testing.T) {
+	t.Parallel()
+
+	gacha := NewGacha(NewSeededRandomSource(1))
+	collections := Collections{
+		{ID: "n", Rarity: 1, Weight: 1000},
+		{ID: "sr", Rarity: 3, Weight: 1},
+		{ID: "ssr", Rarity: 5, Weight: 1},
+	}
+	rule := MultiDrawRule{Draws: 10, MinRarity: 3}
+	pool := mustNewGachaPool(t, collections)
+	pity := &GachaPity{UserID: "user", BannerID: "banner"}
+
+	for trial := 0; trial < 1000; trial++ {
+		results := gacha.DrawMulti(pool, 10, pity, PityRules{}, rule)
+		if len(results) != 10 {
+			t.Fatalf("DrawMulti() returned %v items, want 10", len(results))
+		}
+		var guaranteed int
+		for _, item := range results {
+			if item.Rarity >= rule.MinRarity {
+				guaranteed++
+			}
+		}
+		if guaranteed == 0 {
+			t.Fatalf("DrawMulti() = %v, want at least one rarity >= %v", results, rule.MinRarity)
+		}
+	}
+
+	// 保証の回数に満たない場合は引き直さない
+	var hits int
+	for trial := 0; trial < 1000; trial++ {
+		results := gacha.DrawMulti(pool, 9, pity, PityRules{}, rule)
+		for _, item := range results {
+			if item.Rarity >= rule.MinRarity {
+				hits++
+			}
+		}
+	}
+	if hits > 100 {
+		t.Errorf("DrawMulti() with fewer draws than the rule hit %v times, want around 18", hits)
+	}
+}
+
+func TestModel_Gacha_DrawMulti_Distribution(t *testing.T) {
+	t.Parallel()
+
+	gacha := NewGacha(NewSeededRandomSource(2))
+	collections := Collections{
+		{ID: "n", Rarity: 1, Weight: 90},
+		{ID: "sr", Rarity: 3, Weight: 9},
+		{ID: "ssr", Rarity: 5, Weight: 1},
+	}
+	rule := MultiDrawRule{Draws: 10, MinRarity: 3}
+	pool := mustNewGachaPool(t, collections)
+	pity := &GachaPity{UserID: "user", BannerID: "banner"}
+
+	const trials = 20000
+	var lastSSR, lastSR, firstSRPlus int
+	for trial := 0; trial < trials; trial++ {
+		results := gacha.DrawMulti(pool, 10, pity, PityRules{}, rule)
+		if results[0].Rarity >= rule.MinRarity {
+			firstSRPlus++
+		}
+		switch results[9].ID {
+		case "sr":
+			lastSR++
+		case "ssr":
+			lastSSR++
+		}
+	}
+
+	// 最初の9枠がすべてNになる確率はq=0.9^9で、その場合の最後の枠はSR以上の中から重みの比で排出される
+	q := math.Pow(0.9, 9)
+	patterns := []struct {
+		name string
+		got  int
+		want float64
+	}{
+		{name: "first slot is not affected", got: firstSRPlus, want: 0.1},
+		{name: "last slot SR", got: lastSR, want: (1-q)*0.09 + q*0.9},
+		{name: "last slot SSR", got: lastSSR, want: (1-q)*0.01 + q*0.1},
+	}
+	for _, tt := range patterns {
+		rate := float64(tt.got) / trials
+		// 標準誤差の5倍を許容する
+		tolerance := 5 * math.Sqrt(tt.want*(1-tt.want)/trials)
+		if math.Abs(rate-tt.want) > tolerance {
+			t.Errorf("%s: rate = %v, want %v ± %v", tt.name, rate, tt.want, tolerance)
+		}
+	}
+}
+
+func TestModel_Gacha_DrawMulti_Pity(t *testing.T) {
+	t.Parallel()
+
+	gacha := NewGacha(NewSeededRandomSource(3))
+	collections := Collections{
+		{ID: "n", Rarity: 1, Weight: 1 << 20},
+		{ID: "sr", Rarity: 3, Weight: 1},
+		{ID: "ssr", Rarity: 5, Weight: 1},
+	}
+	pool := mustNewGachaPool(t, collections)
+	pity := &GachaPity{UserID: "user", BannerID: "banner"}
+
+	// 引き直した枠は元の抽選結果ではなく、引き直した結果で天井カウンタを数える
+	results := gacha.DrawMulti(pool, 10, pity, PityRules{}, MultiDrawRule{Draws: 10, MinRarity: 3})
+	var want int
+	for i, item := range results {
+		if item.Rarity == 5 {
+			want = 0
+		} else {
+			want++
+		}
+		if item.PityCount != want {
+			t.Errorf("DrawMulti() results[%d] pity count = %v, want %v", i, item.PityCount, want)
+		}
+		if item.Guaranteed != (i == 9 && item.Rarity >= 3) {
+			t.Errorf("DrawMulti() results[%d] = %v, guaranteed = %v", i, item.Collection, item.Guaranteed)
+		}
+	}
+	if pity.Count != want {
+		t.Errorf("DrawMulti() pity count = %v, want %v (results %v)", pity.Count, want, results)
+	}
+}
+
+func mustNewGachaPool(t testing.TB, collections Collections) *GachaPool {
+	t.Helper()
+	pool, err := NewGachaPool(collections)
+	if err != nil {
+		t.Fatalf("NewGachaPool() error = %v", err)
+	}
+	return pool
+}
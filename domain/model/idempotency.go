@@ -0,0 +1,47 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IdempotencyKeyMaxLength Idempotency-Keyヘッダとして受け付ける最大の長さ
+const IdempotencyKeyMaxLength = 255
+
+// IdempotencyRecord Idempotency-Keyごとに保存する最初のリクエストとそのレスポンス
+// Completedがfalseの間は最初のリクエストを処理中であることを表す
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// NewIdempotencyRecord 処理中のレコードを作成する
+func NewIdempotencyRecord(method, path string, body []byte) *IdempotencyRecord {
+	return &IdempotencyRecord{
+		RequestHash: HashIdempotentRequest(method, path, body),
+	}
+}
+
+// Complete 最初のリクエストのレスポンスを記録する
+func (ir *IdempotencyRecord) Complete(statusCode int, contentType string, body []byte) {
+	ir.Completed = true
+	ir.StatusCode = statusCode
+	ir.ContentType = contentType
+	ir.Body = body
+}
+
+// HashIdempotentRequest 同じキーで異なるリクエストが送られたことを検出するためのハッシュ
+func HashIdempotentRequest(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IdempotencyStorageKey キーはユーザごとに独立させる
+func IdempotencyStorageKey(userID, key string) string {
+	return userID + ":" + key
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestModel_HashIdempotentRequest(t *testing.T) {
+	t.Parallel()
+
+	base := HashIdempotentRequest(http.MethodPost, "/api/gacha/draw", []byte(`{"times":1}`))
+	if got := HashIdempotentRequest(http.MethodPost, "/api/gacha/draw", []byte(`{"times":1}`)); got != base {
+		t.Errorf("same request: got %v want %v", got, base)
+	}
+	for _, other := range []string{
+		HashIdempotentRequest(http.MethodPost, "/api/gacha/draw", []byte(`{"times":10}`)),
+		HashIdempotentRequest(http.MethodPost, "/api/game/finish", []byte(`{"times":1}`)),
+		HashIdempotentRequest(http.MethodPut, "/api/gacha/draw", []byte(`{"times":1}`)),
+	} {
+		if other == base {
+			t.Errorf("different request has the same hash %v", base)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	mathrand "math/rand"
+	"sync"
+)
+
+// RandomSource ガチャの抽選やシャッフルに使う乱数
+// 本番ではNewCryptoRandomSource、テストでは再現できるようNewSeededRandomSourceを使う
+type RandomSource interface {
+	// Intn [0,n)の整数を返す。nが0以下の場合はpanicする
+	Intn(n int) int
+	// Float64 [0,1)の浮動小数点数を返す
+	Float64() float64
+	// Name 抽選の記録に残す乱数の種類
+	Name() string
+}
+
+type cryptoRandomSource struct{}
+
+// NewCryptoRandomSource crypto/randを使う乱数。並行して使用できる
+func NewCryptoRandomSource() RandomSource {
+	return cryptoRandomSource{}
+}
+
+func (cryptoRandomSource) uint64() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/randが失敗するのはOSの乱数源が使えない場合のみで、抽選を続けられない
+		panic(err)
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Intn 偏りが出ないよう、nの倍数に収まらない範囲の値は捨てて引き直す
+func (s cryptoRandomSource) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	bound := uint64(n)
+	limit := ^uint64(0) - (^uint64(0) % bound)
+	for {
+		v := s.uint64()
+		if v < limit {
+			return int(v % bound)
+		}
+	}
+}
+
+func (cryptoRandomSource) Name() string {
+	return "crypto/rand"
+}
+
+func (s cryptoRandomSource) Float64() float64 {
+	// 53bitの整数を2^53で割り、float64で表現できる一様な値にする
+	return float64(s.uint64()>>11) / (1 << 53)
+}
+
+type seededRandomSource struct {
+	mu   sync.Mutex
+	r    *mathrand.Rand
+	seed int64
+}
+
+// NewSeededRandomSource 同じシードからは同じ乱数列を返す。並行して使用できる
+func NewSeededRandomSource(seed int64) RandomSource {
+	return &seededRandomSource{
+		r:    mathrand.New(mathrand.NewSource(seed)), //nolint:gosec // Deterministic for tests
+		seed: seed,
+	}
+}
+
+func (s *seededRandomSource) Intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Intn(n)
+}
+
+func (s *seededRandomSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Float64()
+}
+
+func (s *seededRandomSource) Name() string {
+	return fmt.Sprintf("math/rand(seed=%d)", s.seed)
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestModel_SeededRandomSource(t *testing.T) {
+	t.Parallel()
+
+	r1 := NewSeededRandomSource(42)
+	r2 := NewSeededRandomSource(42)
+	for i := 0; i < 100; i++ {
+		if a, b := r1.Intn(1000), r2.Intn(1000); a != b {
+			t.Fatalf("Intn() with the same seed = %v, %v", a, b)
+		}
+		if a, b := r1.Float64(), r2.Float64(); a != b {
+			t.Fatalf("Float64() with the same seed = %v, %v", a, b)
+		}
+	}
+}
+
+func TestModel_CryptoRandomSource(t *testing.T) {
+	t.Parallel()
+
+	r := NewCryptoRandomSource()
+	for i := 0; i < 1000; i++ {
+		if v := r.Intn(7); v < 0 || v >= 7 {
+			t.Fatalf("Intn(7) = %v", v)
+		}
+		if v := r.Float64(); v < 0 || v >= 1 {
+			t.Fatalf("Float64() = %v", v)
+		}
+	}
+}
+
+// chiSquaredCritical 自由度4、有意水準0.0001のカイ二乗分布の臨界値
+const chiSquaredCritical = 23.513
+
+func TestModel_Gacha_Draw_ChiSquared(t *testing.T) {
+	t.Parallel()
+
+	collections := Collections{
+		{ID: "a", Rarity: 1, Weight: 40},
+		{ID: "b", Rarity: 1, Weight: 30},
+		{ID: "c", Rarity: 2, Weight: 20},
+		{ID: "d", Rarity: 3, Weight: 9},
+		{ID: "e", Rarity: 5, Weight: 1},
+	}
+
+	patterns := []struct {
+		name string
+		rng  RandomSource
+	}{
+		{name: "seeded", rng: NewSeededRandomSource(1)},
+		{name: "crypto", rng: NewCryptoRandomSource()},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			const draws = 100000
+			gacha := NewGacha(tt.rng)
+			observed := make(map[string]int, len(collections))
+			for i := 0; i < draws; i++ {
+				item, err := gacha.Draw(collections)
+				if err != nil {
+					t.Fatalf("Draw() error = %v", err)
+				}
+				observed[item.ID]++
+			}
+
+			// 各アイテムの排出回数がWeightに比例しているかをカイ二乗検定で確かめる
+			var chiSquared float64
+			total := float64(collections.TotalWeight())
+			for _, c := range collections {
+				expected := draws * float64(c.Weight) / total
+				diff := float64(observed[c.ID]) - expected
+				chiSquared += diff * diff / expected
+			}
+			if chiSquared > chiSquaredCritical {
+				t.Errorf("chi-squared = %v exceeds %v, observed %v", chiSquared, chiSquaredCritical, observed)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// RefreshToken ローテーションされるリフレッシュトークン
+// 同じログインから派生したトークンは同じFamilyIDを持ち、再利用が検知された場合はファミリーごと失効させる
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+func NewRefreshToken(userID, familyID, tokenHash string, now time.Time, lifetime time.Duration) (*RefreshToken, error) {
+	if userID == "" || tokenHash == "" {
+		log.Error("UserID or TokenHash is empty", log.Fstring("userID", userID))
+		return nil, fmt.Errorf("userID or tokenHash is empty")
+	}
+	if familyID == "" {
+		familyID = uuid.New().String()
+	}
+	return &RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(lifetime),
+		CreatedAt: now,
+	}, nil
+}
+
+// IsRotated 既に新しいトークンと交換済みであればtrue
+func (rt *RefreshToken) IsRotated() bool {
+	return rt.RotatedAt != nil
+}
+
+func (rt *RefreshToken) IsActive(now time.Time) bool {
+	return rt.RotatedAt == nil && rt.RevokedAt == nil && now.Before(rt.ExpiresAt)
+}
+
+func (rt *RefreshToken) Rotate(now time.Time) {
+	rt.RotatedAt = &now
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestModel_NewRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	patterns := []struct {
+		name      string
+		userID    string
+		familyID  string
+		tokenHash string
+		err       error
+	}{
+		{name: "success: new family", userID: "user", familyID: "", tokenHash: "hash"},
+		{name: "success: existing family", userID: "user", familyID: "family", tokenHash: "hash"},
+		{name: "Fail: userID is required", userID: "", tokenHash: "hash", err: fmt.Errorf("userID or tokenHash is empty")},
+		{name: "Fail: tokenHash is required", userID: "user", tokenHash: "", err: fmt.Errorf("userID or tokenHash is empty")},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token, err := NewRefreshToken(tt.userID, tt.familyID, tt.tokenHash, now, time.Hour)
+
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("NewRefreshToken() error = %v, wantErr %v", err, tt.err)
+			} else if err != nil {
+				if err.Error() != tt.err.Error() {
+					t.Errorf("NewRefreshToken() error = %v, wantErr %v", err, tt.err)
+				}
+				return
+			}
+
+			if token.FamilyID == "" || (tt.familyID != "" && token.FamilyID != tt.familyID) {
+				t.Errorf("NewRefreshToken() family = %v, want %v", token.FamilyID, tt.familyID)
+			}
+			if !token.ExpiresAt.Equal(now.Add(time.Hour)) {
+				t.Errorf("NewRefreshToken() expiresAt = %v, want %v", token.ExpiresAt, now.Add(time.Hour))
+			}
+		})
+	}
+}
+
+func TestModel_RefreshTokenIsActive(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	token, _ := NewRefreshToken("user", "", "hash", now, time.Hour)
+
+	if !token.IsActive(now) {
+		t.Errorf("IsActive() = false for a fresh token")
+	}
+	if token.IsActive(now.Add(2 * time.Hour)) {
+		t.Errorf("IsActive() = true for an expired token")
+	}
+
+	token.Rotate(now)
+	if token.IsActive(now) || !token.IsRotated() {
+		t.Errorf("IsActive() = true for a rotated token")
+	}
+}
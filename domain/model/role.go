@@ -0,0 +1,64 @@
+package model
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+)
+
+// Role ユーザの権限
+type Role string
+
+const (
+	RolePlayer Role = "player"
+	// RoleOperator マスタデータの管理や監査ログの閲覧を行う運用担当者
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var (
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrOwnRoleChange 管理者が不在になるのを防ぐため、自分自身の役割は変更できない
+	ErrOwnRoleChange = errors.New("cannot change own role")
+)
+
+// ParseRole 役割が設定されていない場合はplayerとして扱う
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case "":
+		return RolePlayer, nil
+	case RolePlayer, RoleOperator, RoleAdmin:
+		return r, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// Principal 認証されたリクエストの主体
+type Principal struct {
+	UserID string
+	Role   Role
+}
+
+// HasRole rolesのいずれかを持っているか
+func (p Principal) HasRole(roles ...Role) bool {
+	for _, r := range roles {
+		if p.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, config.ContextPrincipalKey, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(config.ContextPrincipalKey).(Principal)
+	if !ok || principal.UserID == "" {
+		return Principal{}, false
+	}
+	return principal, true
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestModel_ParseRole(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		in   string
+		want Role
+		err  error
+	}{
+		{in: "player", want: RolePlayer},
+		{in: "operator", want: RoleOperator},
+		{in: "admin", want: RoleAdmin},
+		{in: "", want: RolePlayer},
+		{in: "root", err: ErrInvalidRole},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseRole(tt.in)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Errorf("ParseRole(%q) = %v, %v, want %v, %v", tt.in, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestModel_Principal(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Errorf("PrincipalFromContext() of empty context ok = %v", ok)
+	}
+
+	principal := Principal{UserID: "user", Role: RoleOperator}
+	got, ok := PrincipalFromContext(ContextWithPrincipal(context.Background(), principal))
+	if !ok || got != principal {
+		t.Errorf("PrincipalFromContext() = %v, %v, want %v", got, ok, principal)
+	}
+	if !got.HasRole(RoleOperator, RoleAdmin) || got.HasRole(RoleAdmin) {
+		t.Errorf("HasRole() mismatch for %v", got.Role)
+	}
+}
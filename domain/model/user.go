@@ -2,7 +2,12 @@ package model
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
@@ -13,9 +18,11 @@ type User struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
 	Email     string `json:"email"`
-	Password  string `json:"password"`
-	Coins     int    `json:"coins"`
+	Password  string `json:"-"`
 	HighScore int    `json:"highscore"`
+	AvatarURL string `json:"avatar_url"`
+	Locale    string `json:"locale"`
+	Role      Role   `json:"role"`
 }
 
 func NewUser(email, password string) (*User, error) {
@@ -29,8 +36,8 @@ func NewUser(email, password string) (*User, error) {
 		Name:      name,
 		Email:     email,
 		Password:  password,
-		Coins:     0,
 		HighScore: 0,
+		Role:      RolePlayer,
 	}, nil
 }
 
@@ -42,18 +49,112 @@ func extractNameFromEmail(email string) string {
 	return "unknown"
 }
 
+const (
+	MaxUserNameLength  = 32
+	MaxAvatarURLLength = 512
+)
+
+// localePattern BCP 47の言語タグのうち、言語・用字・地域の組み合わせのみを許可する (例: ja, en-US, zh-Hant-TW)
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// ProfileUpdate ユーザ自身が変更できる項目
+// nilの項目は変更しない
+type ProfileUpdate struct {
+	Name      *string
+	AvatarURL *string
+	Locale    *string
+}
+
+// UpdateProfile 全ての項目を検証し、エラーがなければまとめて反映する
+// 検証に失敗した場合は*ValidationErrorを返し、userは変更しない
+func (u *User) UpdateProfile(update ProfileUpdate) error {
+	verr := &ValidationError{}
+	if update.Name != nil {
+		if msg := validateUserName(*update.Name); msg != "" {
+			verr.add("name", msg)
+		}
+	}
+	if update.AvatarURL != nil {
+		if msg := validateAvatarURL(*update.AvatarURL); msg != "" {
+			verr.add("avatar_url", msg)
+		}
+	}
+	if update.Locale != nil {
+		if *update.Locale != "" && !localePattern.MatchString(*update.Locale) {
+			verr.add("locale", "must be a BCP 47 language tag such as ja or en-US")
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return err
+	}
+
+	if update.Name != nil {
+		u.Name = *update.Name
+	}
+	if update.AvatarURL != nil {
+		u.AvatarURL = *update.AvatarURL
+	}
+	if update.Locale != nil {
+		u.Locale = *update.Locale
+	}
+	return nil
+}
+
+func validateUserName(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return "must not be empty"
+	}
+	if utf8.RuneCountInString(name) > MaxUserNameLength {
+		return fmt.Sprintf("must be at most %d characters", MaxUserNameLength)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return "must not contain control characters"
+		}
+	}
+	return ""
+}
+
+// validateAvatarURL 空文字列はアバターの削除として許可する
+func validateAvatarURL(avatarURL string) string {
+	if avatarURL == "" {
+		return ""
+	}
+	if len(avatarURL) > MaxAvatarURLLength {
+		return fmt.Sprintf("must be at most %d bytes", MaxAvatarURLLength)
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "must be an absolute https URL"
+	}
+	return ""
+}
+
 type UserCollection struct {
 	UserID       string `json:"user_id"`
 	CollectionID string `json:"collection_id"`
+	// Quantity 所持数。ガチャで重複して排出されたアイテムも所持数として加算する
+	Quantity        int       `json:"quantity"`
+	FirstObtainedAt time.Time `json:"first_obtained_at"`
+	LastObtainedAt  time.Time `json:"last_obtained_at"`
 }
 
-func NewUserCollection(userID, collectionID string) (*UserCollection, error) {
+func NewUserCollection(userID, collectionID string, quantity int, obtainedAt time.Time) (*UserCollection, error) {
 	if userID == "" || collectionID == "" {
 		log.Error("UserID or CollectionID is empty", log.Fstring("userID", userID), log.Fstring("collectionID", collectionID))
 		return nil, fmt.Errorf("userID or collectionID is empty")
 	}
+	if quantity < 1 {
+		log.Error("Quantity is less than 1", log.Fstring("userID", userID), log.Fint("quantity", quantity))
+		return nil, fmt.Errorf("quantity is less than 1")
+	}
+	// DATETIME(6)に保存されるため、マイクロ秒に丸めてDBから読み出した値と一致させる
+	obtainedAt = obtainedAt.UTC().Truncate(time.Microsecond)
 	return &UserCollection{
-		UserID:       userID,
-		CollectionID: collectionID,
+		UserID:          userID,
+		CollectionID:    collectionID,
+		Quantity:        quantity,
+		FirstObtainedAt: obtainedAt,
+		LastObtainedAt:  obtainedAt,
 	}, nil
 }
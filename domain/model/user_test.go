@@ -1,8 +1,11 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -40,8 +43,8 @@ func TestModel_NewUser(t *testing.T) {
 					Name:      "test",
 					Email:     "test@gmail.com",
 					Password:  "password123",
-					Coins:     0,
 					HighScore: 0,
+					Role:      RolePlayer,
 				},
 				err: nil,
 			},
@@ -107,12 +110,15 @@ func TestModel_NewUserCollections(t *testing.T) {
 
 	userID := uuid.New().String()
 	collectionID := uuid.New().String()
+	obtainedAt := time.Date(2024, 1, 2, 3, 4, 5, 678901234, time.FixedZone("JST", 9*60*60))
+	want := time.Date(2024, 1, 1, 18, 4, 5, 678901000, time.UTC)
 
 	patterns := []struct {
 		name string
 		arg  struct {
 			userID       string
 			collectionID string
+			quantity     int
 		}
 		want struct {
 			userCollection *UserCollection
@@ -124,17 +130,22 @@ func TestModel_NewUserCollections(t *testing.T) {
 			arg: struct {
 				userID       string
 				collectionID string
+				quantity     int
 			}{
 				userID:       userID,
 				collectionID: collectionID,
+				quantity:     2,
 			},
 			want: struct {
 				userCollection *UserCollection
 				err            error
 			}{
 				userCollection: &UserCollection{
-					UserID:       userID,
-					CollectionID: collectionID,
+					UserID:          userID,
+					CollectionID:    collectionID,
+					Quantity:        2,
+					FirstObtainedAt: want,
+					LastObtainedAt:  want,
 				},
 				err: nil,
 			},
@@ -144,9 +155,11 @@ func TestModel_NewUserCollections(t *testing.T) {
 			arg: struct {
 				userID       string
 				collectionID string
+				quantity     int
 			}{
 				userID:       "",
 				collectionID: collectionID,
+				quantity:     1,
 			},
 			want: struct {
 				userCollection *UserCollection
@@ -161,9 +174,11 @@ func TestModel_NewUserCollections(t *testing.T) {
 			arg: struct {
 				userID       string
 				collectionID string
+				quantity     int
 			}{
 				userID:       userID,
 				collectionID: "",
+				quantity:     1,
 			},
 			want: struct {
 				userCollection *UserCollection
@@ -173,6 +188,25 @@ func TestModel_NewUserCollections(t *testing.T) {
 				err:            fmt.Errorf("userID or collectionID is empty"),
 			},
 		},
+		{
+			name: "Fail: quantity must be positive",
+			arg: struct {
+				userID       string
+				collectionID string
+				quantity     int
+			}{
+				userID:       userID,
+				collectionID: collectionID,
+				quantity:     0,
+			},
+			want: struct {
+				userCollection *UserCollection
+				err            error
+			}{
+				userCollection: nil,
+				err:            fmt.Errorf("quantity is less than 1"),
+			},
+		},
 	}
 
 	for _, tt := range patterns {
@@ -180,7 +214,7 @@ func TestModel_NewUserCollections(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			getUserCollections, err := NewUserCollection(tt.arg.userID, tt.arg.collectionID)
+			getUserCollections, err := NewUserCollection(tt.arg.userID, tt.arg.collectionID, tt.arg.quantity, obtainedAt)
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("NewUserCollection() error = %v, wantErr %v", err, tt.want.err)
@@ -194,3 +228,88 @@ func TestModel_NewUserCollections(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_UserUpdateProfile(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(s string) *string { return &s }
+
+	patterns := []struct {
+		name       string
+		update     ProfileUpdate
+		want       User
+		wantFields []string
+	}{
+		{
+			name: "success: all fields",
+			update: ProfileUpdate{
+				Name:      ptr("新しい名前"),
+				AvatarURL: ptr("https://example.com/avatar.png"),
+				Locale:    ptr("en-US"),
+			},
+			want: User{Name: "新しい名前", AvatarURL: "https://example.com/avatar.png", Locale: "en-US"},
+		},
+		{
+			name:   "success: nil fields are unchanged",
+			update: ProfileUpdate{Locale: ptr("zh-Hant-TW")},
+			want:   User{Name: "test", AvatarURL: "https://example.com/old.png", Locale: "zh-Hant-TW"},
+		},
+		{
+			name:   "success: clear avatar and locale",
+			update: ProfileUpdate{AvatarURL: ptr(""), Locale: ptr("")},
+			want:   User{Name: "test"},
+		},
+		{
+			name: "Fail: every invalid field is reported",
+			update: ProfileUpdate{
+				Name:      ptr("  "),
+				AvatarURL: ptr("http://example.com/avatar.png"),
+				Locale:    ptr("english"),
+			},
+			want:       User{Name: "test", AvatarURL: "https://example.com/old.png", Locale: "ja"},
+			wantFields: []string{"name", "avatar_url", "locale"},
+		},
+		{
+			name:       "Fail: name too long",
+			update:     ProfileUpdate{Name: ptr(strings.Repeat("あ", MaxUserNameLength+1))},
+			want:       User{Name: "test", AvatarURL: "https://example.com/old.png", Locale: "ja"},
+			wantFields: []string{"name"},
+		},
+		{
+			name:       "Fail: name with control characters",
+			update:     ProfileUpdate{Name: ptr("te\nst")},
+			want:       User{Name: "test", AvatarURL: "https://example.com/old.png", Locale: "ja"},
+			wantFields: []string{"name"},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := User{Name: "test", AvatarURL: "https://example.com/old.png", Locale: "ja"}
+			err := user.UpdateProfile(tt.update)
+
+			var verr *ValidationError
+			if len(tt.wantFields) == 0 && err != nil {
+				t.Fatalf("UpdateProfile() error = %v", err)
+			}
+			if len(tt.wantFields) > 0 {
+				if !errors.As(err, &verr) {
+					t.Fatalf("UpdateProfile() error = %v, want ValidationError", err)
+				}
+				var fields []string
+				for _, f := range verr.Fields {
+					fields = append(fields, f.Field)
+				}
+				if diff := cmp.Diff(tt.wantFields, fields); diff != "" {
+					t.Errorf("UpdateProfile() fields mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tt.want, user); diff != "" {
+				t.Errorf("UpdateProfile() user mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError 入力値の検証に失敗した項目
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError 複数の項目の検証エラーをまとめて返す
+// 呼び出し側はerrors.Asで取り出し、項目ごとのエラーをクライアントに返す
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+	return "validation failed: " + strings.Join(messages, ", ")
+}
+
+func (e *ValidationError) add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// errOrNil 検証エラーがなければnilを返す
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
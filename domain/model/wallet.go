@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+)
+
+// ErrInsufficientBalance 支払いに使える通貨の残高の合計が価格に足りない
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// ErrInvalidCurrency 存在しない通貨が指定された
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+// Currency ウォレットで管理する通貨の種類
+type Currency string
+
+const (
+	// CurrencyCoin ゲームの報酬などで無償で得るコイン
+	CurrencyCoin Currency = "coin"
+	// CurrencyGem 購入した有償のジェム
+	CurrencyGem Currency = "gem"
+	// CurrencyShard 重複したアイテムを分解して得られる欠片
+	CurrencyShard Currency = "shard"
+	// CurrencyEventToken イベントの報酬として配布されるトークン
+	CurrencyEventToken Currency = "event_token"
+)
+
+// Currencies 全ての通貨。ウォレットはこの順に表示する
+var Currencies = []Currency{CurrencyCoin, CurrencyGem, CurrencyShard, CurrencyEventToken}
+
+func (c Currency) IsValid() bool {
+	for _, currency := range Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// spendPriority 価格の通貨ごとに、支払いに使う通貨を消費する順に並べたもの
+// コインで価格が付いたものは無償のコインを先に消費し、足りない分を有償のジェムで支払う
+// ジェムで価格が付いたもの(有償限定)は無償のコインでは支払えない
+var spendPriority = map[Currency][]Currency{
+	CurrencyCoin:       {CurrencyCoin, CurrencyGem},
+	CurrencyGem:        {CurrencyGem},
+	CurrencyShard:      {CurrencyShard},
+	CurrencyEventToken: {CurrencyEventToken},
+}
+
+// Wallet ユーザの通貨ごとの残高。残高の記録がない通貨は0として扱う
+type Wallet map[Currency]int
+
+// NewWallet 全ての通貨の残高を0で初期化する
+func NewWallet() Wallet {
+	wallet := make(Wallet, len(Currencies))
+	for _, currency := range Currencies {
+		wallet[currency] = 0
+	}
+	return wallet
+}
+
+// Payment 1つの通貨から支払う額
+type Payment struct {
+	Currency Currency `json:"currency"`
+	Amount   int      `json:"amount"`
+}
+
+// Pay currencyで付けられた価格を、支払いに使う通貨の優先順に残高から差し引く
+// 残高の合計が足りない場合はErrInsufficientBalanceを返し、walletは変更しない
+func (w Wallet) Pay(currency Currency, price int) ([]Payment, error) {
+	priority, ok := spendPriority[currency]
+	if !ok {
+		return nil, ErrInvalidCurrency
+	}
+	var available int
+	for _, c := range priority {
+		available += w[c]
+	}
+	if available < price {
+		return nil, ErrInsufficientBalance
+	}
+
+	var payments []Payment
+	remaining := price
+	for _, c := range priority {
+		if remaining == 0 {
+			break
+		}
+		amount := min(w[c], remaining)
+		if amount == 0 {
+			continue
+		}
+		w[c] -= amount
+		remaining -= amount
+		payments = append(payments, Payment{Currency: c, Amount: amount})
+	}
+	return payments, nil
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestModel_Wallet_Pay(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name         string
+		wallet       Wallet
+		currency     Currency
+		price        int
+		wantPayments []Payment
+		wantWallet   Wallet
+		err          error
+	}{
+		{
+			name:         "success: free coins only",
+			wallet:       Wallet{CurrencyCoin: 300, CurrencyGem: 100},
+			currency:     CurrencyCoin,
+			price:        200,
+			wantPayments: []Payment{{Currency: CurrencyCoin, Amount: 200}},
+			wantWallet:   Wallet{CurrencyCoin: 100, CurrencyGem: 100},
+		},
+		{
+			name:         "success: free coins first, then paid gems",
+			wallet:       Wallet{CurrencyCoin: 50, CurrencyGem: 100},
+			currency:     CurrencyCoin,
+			price:        120,
+			wantPayments: []Payment{{Currency: CurrencyCoin, Amount: 50}, {Currency: CurrencyGem, Amount: 70}},
+			wantWallet:   Wallet{CurrencyCoin: 0, CurrencyGem: 30},
+		},
+		{
+			name:         "success: paid gems only when no free coins",
+			wallet:       Wallet{CurrencyGem: 100},
+			currency:     CurrencyCoin,
+			price:        100,
+			wantPayments: []Payment{{Currency: CurrencyGem, Amount: 100}},
+			wantWallet:   Wallet{CurrencyGem: 0},
+		},
+		{
+			name:         "success: event tokens",
+			wallet:       Wallet{CurrencyCoin: 100, CurrencyEventToken: 5},
+			currency:     CurrencyEventToken,
+			price:        5,
+			wantPayments: []Payment{{Currency: CurrencyEventToken, Amount: 5}},
+			wantWallet:   Wallet{CurrencyCoin: 100, CurrencyEventToken: 0},
+		},
+		{
+			name:       "Fail: gem-priced items cannot be paid with free coins",
+			wallet:     Wallet{CurrencyCoin: 1000, CurrencyGem: 10},
+			currency:   CurrencyGem,
+			price:      100,
+			wantWallet: Wallet{CurrencyCoin: 1000, CurrencyGem: 10},
+			err:        ErrInsufficientBalance,
+		},
+		{
+			name:       "Fail: insufficient balance leaves the wallet unchanged",
+			wallet:     Wallet{CurrencyCoin: 50, CurrencyGem: 49},
+			currency:   CurrencyCoin,
+			price:      100,
+			wantWallet: Wallet{CurrencyCoin: 50, CurrencyGem: 49},
+			err:        ErrInsufficientBalance,
+		},
+		{
+			name:       "Fail: unknown currency",
+			wallet:     Wallet{CurrencyCoin: 100},
+			currency:   Currency("diamond"),
+			price:      1,
+			wantWallet: Wallet{CurrencyCoin: 100},
+			err:        ErrInvalidCurrency,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			payments, err := tt.wallet.Pay(tt.currency, tt.price)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Pay() error = %v, wantErr %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(payments, tt.wantPayments) {
+				t.Errorf("Pay() payments = %v, want %v", payments, tt.wantPayments)
+			}
+			if !reflect.DeepEqual(tt.wallet, tt.wantWallet) {
+				t.Errorf("Pay() wallet = %v, want %v", tt.wallet, tt.wantWallet)
+			}
+		})
+	}
+}
+
+func TestModel_NewWallet(t *testing.T) {
+	t.Parallel()
+
+	wallet := NewWallet()
+	for _, currency := range Currencies {
+		if balance, ok := wallet[currency]; !ok || balance != 0 {
+			t.Errorf("NewWallet()[%v] = %v, %v, want 0, true", currency, balance, ok)
+		}
+	}
+	if Currency("diamond").IsValid() {
+		t.Error("IsValid() = true for an unknown currency")
+	}
+}
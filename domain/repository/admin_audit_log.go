@@ -0,0 +1,15 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type AdminAuditLogRepository interface {
+	// Create 変更を行ったのと同じトランザクション内で呼び出す
+	Create(ctx context.Context, auditLog model.AdminAuditLog) error
+	// ListByTarget 対象に対する操作を新しい順に最大limit件返す
+	ListByTarget(ctx context.Context, targetID string, limit int) ([]*model.AdminAuditLog, error)
+}
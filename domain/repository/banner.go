@@ -0,0 +1,16 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type BannerRepository interface {
+	Get(ctx context.Context, id string) (*model.Banner, error)
+	// ListActive nowの時点で開催中のバナーを終了が近い順に返す
+	ListActive(ctx context.Context, now time.Time) ([]*model.Banner, error)
+	Create(ctx context.Context, banner model.Banner) error
+}
@@ -0,0 +1,17 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type CoinTransactionRepository interface {
+	// Create WalletRepositoryで残高を変更するのと同じトランザクション内で呼び出す
+	Create(ctx context.Context, ct model.CoinTransaction) error
+	// List 新しい順にlimit件を返す。cursorが指定された場合はその位置より古いエントリのみを返す
+	List(ctx context.Context, userID string, cursor *model.CoinTransactionCursor, limit int) ([]*model.CoinTransaction, error)
+	// ListBalanceMismatches 台帳の合計とUser_Balancesの残高が一致しないユーザの通貨を返す
+	ListBalanceMismatches(ctx context.Context) ([]*model.CoinBalanceMismatch, error)
+}
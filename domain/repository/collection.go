@@ -19,5 +19,8 @@ type CollectionRepository interface {
 type CollectionCacheRepository interface {
 	Get(ctx context.Context, key string) (model.Collections, error)
 	Create(ctx context.Context, key string, collection model.Collections) error
+	// Delete キャッシュを破棄し、keyのVersionを進める
 	Delete(ctx context.Context, key string) error
+	// Version keyのキャッシュが破棄された回数。一度も破棄されていない場合は0を返す
+	Version(ctx context.Context, key string) (int64, error)
 }
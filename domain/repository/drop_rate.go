@@ -0,0 +1,15 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// DropRatesCacheRepository バナーごとの排出確率をkeyにまとめて保存し、Deleteで一括して破棄する
+type DropRatesCacheRepository interface {
+	Get(ctx context.Context, key, bannerID string) (*model.DropRates, error)
+	Create(ctx context.Context, key string, rates model.DropRates) error
+	Delete(ctx context.Context, key string) error
+}
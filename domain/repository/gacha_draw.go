@@ -0,0 +1,18 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type GachaDrawRepository interface {
+	// BatchCreate 抽選と同じトランザクション内で呼び出す
+	BatchCreate(ctx context.Context, records []*model.GachaDrawRecord) error
+	// List ユーザの記録を新しい順にlimit件返す。cursorが指定された場合はその位置より古い記録のみを返す
+	List(ctx context.Context, userID string, cursor *model.GachaDrawCursor, limit int) ([]*model.GachaDrawRecord, error)
+	// ListByPeriod 全ユーザの[from, to)の記録を新しい順にlimit件返す
+	ListByPeriod(ctx context.Context, from, to time.Time, cursor *model.GachaDrawCursor, limit int) ([]*model.GachaDrawRecord, error)
+}
@@ -0,0 +1,14 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type GachaPityRepository interface {
+	// GetForUpdate まだ抽選していないバナーの場合はconfig.ErrNotFoundを返す
+	GetForUpdate(ctx context.Context, userID, bannerID string) (*model.GachaPity, error)
+	Upsert(ctx context.Context, pity model.GachaPity) error
+}
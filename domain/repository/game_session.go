@@ -0,0 +1,16 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type GameSessionRepository interface {
+	Create(ctx context.Context, session model.GameSession) error
+	// GetForUpdate トランザクション内で呼び出し、同じセッションの終了を直列化する
+	// 存在しない場合はconfig.ErrNotFoundを返す
+	GetForUpdate(ctx context.Context, id string) (*model.GameSession, error)
+	Update(ctx context.Context, session model.GameSession) error
+}
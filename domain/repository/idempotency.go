@@ -0,0 +1,17 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type IdempotencyRepository interface {
+	// Reserve keyが未使用の場合のみrecordを保存し、保存できたかを返す
+	Reserve(ctx context.Context, key string, record model.IdempotencyRecord, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (*model.IdempotencyRecord, error)
+	Update(ctx context.Context, key string, record model.IdempotencyRecord, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admin_audit_log.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockAdminAuditLogRepository is a mock of AdminAuditLogRepository interface.
+type MockAdminAuditLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminAuditLogRepositoryMockRecorder
+}
+
+// MockAdminAuditLogRepositoryMockRecorder is the mock recorder for MockAdminAuditLogRepository.
+type MockAdminAuditLogRepositoryMockRecorder struct {
+	mock *MockAdminAuditLogRepository
+}
+
+// NewMockAdminAuditLogRepository creates a new mock instance.
+func NewMockAdminAuditLogRepository(ctrl *gomock.Controller) *MockAdminAuditLogRepository {
+	mock := &MockAdminAuditLogRepository{ctrl: ctrl}
+	mock.recorder = &MockAdminAuditLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminAuditLogRepository) EXPECT() *MockAdminAuditLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAdminAuditLogRepository) Create(ctx context.Context, auditLog model.AdminAuditLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, auditLog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAdminAuditLogRepositoryMockRecorder) Create(ctx, auditLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAdminAuditLogRepository)(nil).Create), ctx, auditLog)
+}
+
+// ListByTarget mocks base method.
+func (m *MockAdminAuditLogRepository) ListByTarget(ctx context.Context, targetID string, limit int) ([]*model.AdminAuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTarget", ctx, targetID, limit)
+	ret0, _ := ret[0].([]*model.AdminAuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTarget indicates an expected call of ListByTarget.
+func (mr *MockAdminAuditLogRepositoryMockRecorder) ListByTarget(ctx, targetID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTarget", reflect.TypeOf((*MockAdminAuditLogRepository)(nil).ListByTarget), ctx, targetID, limit)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: banner.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockBannerRepository is a mock of BannerRepository interface.
+type MockBannerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBannerRepositoryMockRecorder
+}
+
+// MockBannerRepositoryMockRecorder is the mock recorder for MockBannerRepository.
+type MockBannerRepositoryMockRecorder struct {
+	mock *MockBannerRepository
+}
+
+// NewMockBannerRepository creates a new mock instance.
+func NewMockBannerRepository(ctrl *gomock.Controller) *MockBannerRepository {
+	mock := &MockBannerRepository{ctrl: ctrl}
+	mock.recorder = &MockBannerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBannerRepository) EXPECT() *MockBannerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBannerRepository) Create(ctx context.Context, banner model.Banner) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, banner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBannerRepositoryMockRecorder) Create(ctx, banner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBannerRepository)(nil).Create), ctx, banner)
+}
+
+// Get mocks base method.
+func (m *MockBannerRepository) Get(ctx context.Context, id string) (*model.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*model.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBannerRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBannerRepository)(nil).Get), ctx, id)
+}
+
+// ListActive mocks base method.
+func (m *MockBannerRepository) ListActive(ctx context.Context, now time.Time) ([]*model.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx, now)
+	ret0, _ := ret[0].([]*model.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockBannerRepositoryMockRecorder) ListActive(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockBannerRepository)(nil).ListActive), ctx, now)
+}
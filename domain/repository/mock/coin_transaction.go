@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: coin_transaction.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockCoinTransactionRepository is a mock of CoinTransactionRepository interface.
+type MockCoinTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoinTransactionRepositoryMockRecorder
+}
+
+// MockCoinTransactionRepositoryMockRecorder is the mock recorder for MockCoinTransactionRepository.
+type MockCoinTransactionRepositoryMockRecorder struct {
+	mock *MockCoinTransactionRepository
+}
+
+// NewMockCoinTransactionRepository creates a new mock instance.
+func NewMockCoinTransactionRepository(ctrl *gomock.Controller) *MockCoinTransactionRepository {
+	mock := &MockCoinTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockCoinTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoinTransactionRepository) EXPECT() *MockCoinTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCoinTransactionRepository) Create(ctx context.Context, ct model.CoinTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, ct)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCoinTransactionRepositoryMockRecorder) Create(ctx, ct interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCoinTransactionRepository)(nil).Create), ctx, ct)
+}
+
+// List mocks base method.
+func (m *MockCoinTransactionRepository) List(ctx context.Context, userID string, cursor *model.CoinTransactionCursor, limit int) ([]*model.CoinTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, cursor, limit)
+	ret0, _ := ret[0].([]*model.CoinTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCoinTransactionRepositoryMockRecorder) List(ctx, userID, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCoinTransactionRepository)(nil).List), ctx, userID, cursor, limit)
+}
+
+// ListBalanceMismatches mocks base method.
+func (m *MockCoinTransactionRepository) ListBalanceMismatches(ctx context.Context) ([]*model.CoinBalanceMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalanceMismatches", ctx)
+	ret0, _ := ret[0].([]*model.CoinBalanceMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalanceMismatches indicates an expected call of ListBalanceMismatches.
+func (mr *MockCoinTransactionRepositoryMockRecorder) ListBalanceMismatches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalanceMismatches", reflect.TypeOf((*MockCoinTransactionRepository)(nil).ListBalanceMismatches), ctx)
+}
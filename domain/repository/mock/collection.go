@@ -187,3 +187,18 @@ func (mr *MockCollectionCacheRepositoryMockRecorder) Get(ctx, key interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCollectionCacheRepository)(nil).Get), ctx, key)
 }
+
+// Version mocks base method.
+func (m *MockCollectionCacheRepository) Version(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockCollectionCacheRepositoryMockRecorder) Version(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockCollectionCacheRepository)(nil).Version), ctx, key)
+}
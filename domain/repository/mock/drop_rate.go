@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: drop_rate.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockDropRatesCacheRepository is a mock of DropRatesCacheRepository interface.
+type MockDropRatesCacheRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDropRatesCacheRepositoryMockRecorder
+}
+
+// MockDropRatesCacheRepositoryMockRecorder is the mock recorder for MockDropRatesCacheRepository.
+type MockDropRatesCacheRepositoryMockRecorder struct {
+	mock *MockDropRatesCacheRepository
+}
+
+// NewMockDropRatesCacheRepository creates a new mock instance.
+func NewMockDropRatesCacheRepository(ctrl *gomock.Controller) *MockDropRatesCacheRepository {
+	mock := &MockDropRatesCacheRepository{ctrl: ctrl}
+	mock.recorder = &MockDropRatesCacheRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDropRatesCacheRepository) EXPECT() *MockDropRatesCacheRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDropRatesCacheRepository) Create(ctx context.Context, key string, rates model.DropRates) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key, rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDropRatesCacheRepositoryMockRecorder) Create(ctx, key, rates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDropRatesCacheRepository)(nil).Create), ctx, key, rates)
+}
+
+// Delete mocks base method.
+func (m *MockDropRatesCacheRepository) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDropRatesCacheRepositoryMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDropRatesCacheRepository)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockDropRatesCacheRepository) Get(ctx context.Context, key, bannerID string) (*model.DropRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key, bannerID)
+	ret0, _ := ret[0].(*model.DropRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDropRatesCacheRepositoryMockRecorder) Get(ctx, key, bannerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDropRatesCacheRepository)(nil).Get), ctx, key, bannerID)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gacha_draw.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockGachaDrawRepository is a mock of GachaDrawRepository interface.
+type MockGachaDrawRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGachaDrawRepositoryMockRecorder
+}
+
+// MockGachaDrawRepositoryMockRecorder is the mock recorder for MockGachaDrawRepository.
+type MockGachaDrawRepositoryMockRecorder struct {
+	mock *MockGachaDrawRepository
+}
+
+// NewMockGachaDrawRepository creates a new mock instance.
+func NewMockGachaDrawRepository(ctrl *gomock.Controller) *MockGachaDrawRepository {
+	mock := &MockGachaDrawRepository{ctrl: ctrl}
+	mock.recorder = &MockGachaDrawRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGachaDrawRepository) EXPECT() *MockGachaDrawRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BatchCreate mocks base method.
+func (m *MockGachaDrawRepository) BatchCreate(ctx context.Context, records []*model.GachaDrawRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreate", ctx, records)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchCreate indicates an expected call of BatchCreate.
+func (mr *MockGachaDrawRepositoryMockRecorder) BatchCreate(ctx, records interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockGachaDrawRepository)(nil).BatchCreate), ctx, records)
+}
+
+// List mocks base method.
+func (m *MockGachaDrawRepository) List(ctx context.Context, userID string, cursor *model.GachaDrawCursor, limit int) ([]*model.GachaDrawRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, cursor, limit)
+	ret0, _ := ret[0].([]*model.GachaDrawRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockGachaDrawRepositoryMockRecorder) List(ctx, userID, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockGachaDrawRepository)(nil).List), ctx, userID, cursor, limit)
+}
+
+// ListByPeriod mocks base method.
+func (m *MockGachaDrawRepository) ListByPeriod(ctx context.Context, from, to time.Time, cursor *model.GachaDrawCursor, limit int) ([]*model.GachaDrawRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPeriod", ctx, from, to, cursor, limit)
+	ret0, _ := ret[0].([]*model.GachaDrawRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPeriod indicates an expected call of ListByPeriod.
+func (mr *MockGachaDrawRepositoryMockRecorder) ListByPeriod(ctx, from, to, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPeriod", reflect.TypeOf((*MockGachaDrawRepository)(nil).ListByPeriod), ctx, from, to, cursor, limit)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gacha_pity.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockGachaPityRepository is a mock of GachaPityRepository interface.
+type MockGachaPityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGachaPityRepositoryMockRecorder
+}
+
+// MockGachaPityRepositoryMockRecorder is the mock recorder for MockGachaPityRepository.
+type MockGachaPityRepositoryMockRecorder struct {
+	mock *MockGachaPityRepository
+}
+
+// NewMockGachaPityRepository creates a new mock instance.
+func NewMockGachaPityRepository(ctrl *gomock.Controller) *MockGachaPityRepository {
+	mock := &MockGachaPityRepository{ctrl: ctrl}
+	mock.recorder = &MockGachaPityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGachaPityRepository) EXPECT() *MockGachaPityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetForUpdate mocks base method.
+func (m *MockGachaPityRepository) GetForUpdate(ctx context.Context, userID, bannerID string) (*model.GachaPity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, userID, bannerID)
+	ret0, _ := ret[0].(*model.GachaPity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockGachaPityRepositoryMockRecorder) GetForUpdate(ctx, userID, bannerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockGachaPityRepository)(nil).GetForUpdate), ctx, userID, bannerID)
+}
+
+// Upsert mocks base method.
+func (m *MockGachaPityRepository) Upsert(ctx context.Context, pity model.GachaPity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, pity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockGachaPityRepositoryMockRecorder) Upsert(ctx, pity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockGachaPityRepository)(nil).Upsert), ctx, pity)
+}
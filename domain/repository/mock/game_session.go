@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: game_session.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockGameSessionRepository is a mock of GameSessionRepository interface.
+type MockGameSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGameSessionRepositoryMockRecorder
+}
+
+// MockGameSessionRepositoryMockRecorder is the mock recorder for MockGameSessionRepository.
+type MockGameSessionRepositoryMockRecorder struct {
+	mock *MockGameSessionRepository
+}
+
+// NewMockGameSessionRepository creates a new mock instance.
+func NewMockGameSessionRepository(ctrl *gomock.Controller) *MockGameSessionRepository {
+	mock := &MockGameSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockGameSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGameSessionRepository) EXPECT() *MockGameSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockGameSessionRepository) Create(ctx context.Context, session model.GameSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockGameSessionRepositoryMockRecorder) Create(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockGameSessionRepository)(nil).Create), ctx, session)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockGameSessionRepository) GetForUpdate(ctx context.Context, id string) (*model.GameSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(*model.GameSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockGameSessionRepositoryMockRecorder) GetForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockGameSessionRepository)(nil).GetForUpdate), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockGameSessionRepository) Update(ctx context.Context, session model.GameSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockGameSessionRepositoryMockRecorder) Update(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockGameSessionRepository)(nil).Update), ctx, session)
+}
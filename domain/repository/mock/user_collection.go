@@ -36,18 +36,18 @@ func (m *MockUserCollectionRepository) EXPECT() *MockUserCollectionRepositoryMoc
 	return m.recorder
 }
 
-// BatchCreate mocks base method.
-func (m *MockUserCollectionRepository) BatchCreate(ctx context.Context, userCollections []*model.UserCollection) error {
+// BatchIncrement mocks base method.
+func (m *MockUserCollectionRepository) BatchIncrement(ctx context.Context, userCollections []*model.UserCollection) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BatchCreate", ctx, userCollections)
+	ret := m.ctrl.Call(m, "BatchIncrement", ctx, userCollections)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// BatchCreate indicates an expected call of BatchCreate.
-func (mr *MockUserCollectionRepositoryMockRecorder) BatchCreate(ctx, userCollections interface{}) *gomock.Call {
+// BatchIncrement indicates an expected call of BatchIncrement.
+func (mr *MockUserCollectionRepositoryMockRecorder) BatchIncrement(ctx, userCollections interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockUserCollectionRepository)(nil).BatchCreate), ctx, userCollections)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchIncrement", reflect.TypeOf((*MockUserCollectionRepository)(nil).BatchIncrement), ctx, userCollections)
 }
 
 // Create mocks base method.
@@ -64,6 +64,20 @@ func (mr *MockUserCollectionRepositoryMockRecorder) Create(ctx, userCollection i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserCollectionRepository)(nil).Create), ctx, userCollection)
 }
 
+// Decrement mocks base method.
+func (m *MockUserCollectionRepository) Decrement(ctx context.Context, userID, collectionID string, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, userID, collectionID, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockUserCollectionRepositoryMockRecorder) Decrement(ctx, userID, collectionID, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockUserCollectionRepository)(nil).Decrement), ctx, userID, collectionID, quantity)
+}
+
 // Delete mocks base method.
 func (m *MockUserCollectionRepository) Delete(ctx context.Context, userID, collectionID string) error {
 	m.ctrl.T.Helper()
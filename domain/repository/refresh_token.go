@@ -0,0 +1,17 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+type RefreshTokenRepository interface {
+	GetForUpdate(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	Create(ctx context.Context, token model.RefreshToken) error
+	Update(ctx context.Context, token model.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error
+}
@@ -0,0 +1,13 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type TokenRevocationRepository interface {
+	Revoke(ctx context.Context, jti string, ttl time.Duration) error
+	RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time, ttl time.Duration) error
+	IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error)
+}
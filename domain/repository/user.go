@@ -9,8 +9,14 @@ import (
 
 type UserRepository interface {
 	Get(ctx context.Context, id string) (*model.User, error)
+	// GetForUpdate トランザクション内で呼び出し、コミットまで他のトランザクションからの更新をブロックする
+	GetForUpdate(ctx context.Context, id string) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Create(ctx context.Context, user model.User) error
 	Update(ctx context.Context, user model.User) error
+	// UpdatePassword パスワードのハッシュがcurrentHashのままの場合だけnewHashに置き換え、置き換えたかどうかを返す
+	// 行ロックを取らずに読み込んだユーザでも、他の項目の同時更新を上書きしない
+	UpdatePassword(ctx context.Context, id string, currentHash string, newHash string) (bool, error)
 	Delete(ctx context.Context, id string) error
 	LockUserByEmail(ctx context.Context, email string) (bool, error)
 }
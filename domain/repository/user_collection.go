@@ -11,6 +11,9 @@ type UserCollectionRepository interface {
 	List(ctx context.Context, userID string) ([]*model.UserCollection, error)
 	Get(ctx context.Context, userID, collectionID string) (*model.UserCollection, error)
 	Create(ctx context.Context, userCollection model.UserCollection) error
-	BatchCreate(ctx context.Context, userCollections []*model.UserCollection) error
+	// BatchIncrement 所持していないアイテムは追加し、既に所持しているアイテムはQuantityを所持数に加算してLastObtainedAtを更新する
+	BatchIncrement(ctx context.Context, userCollections []*model.UserCollection) error
+	// Decrement 最後の1個を残せる場合のみ所持数からquantityを減算し、足りない場合はmodel.ErrInsufficientItemsを返す
+	Decrement(ctx context.Context, userID, collectionID string, quantity int) error
 	Delete(ctx context.Context, userID, collectionID string) error
 }
@@ -0,0 +1,19 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// WalletRepository ユーザの通貨ごとの残高
+// 残高を変更する場合は、同じトランザクション内で台帳(CoinTransactionRepository)にも記録する
+type WalletRepository interface {
+	// Get 全ての通貨の残高を返す。残高の記録がない通貨は0とする
+	Get(ctx context.Context, userID string) (model.Wallet, error)
+	// Debit 残高がamount以上の場合のみ減算し、不足している場合はmodel.ErrInsufficientBalanceを返す
+	Debit(ctx context.Context, userID string, currency model.Currency, amount int) error
+	// Credit 残高の記録がない通貨は作成する
+	Credit(ctx context.Context, userID string, currency model.Currency, amount int) error
+}
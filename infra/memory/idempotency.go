@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type idempotencyEntry struct {
+	record    model.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// idempotencyRepository Redisを使わないテスト用の実装。Redisと同じくキャンセルされたコンテキストでは失敗する
+type idempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]idempotencyEntry
+}
+
+func NewIdempotencyRepository() repository.IdempotencyRepository {
+	return &idempotencyRepository{
+		records: make(map[string]idempotencyEntry),
+	}
+}
+
+func (ir *idempotencyRepository) Reserve(ctx context.Context, key string, record model.IdempotencyRecord, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if _, ok := ir.get(key); ok {
+		return false, nil
+	}
+	ir.records[key] = idempotencyEntry{record: record, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (ir *idempotencyRepository) Get(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	record, ok := ir.get(key)
+	if !ok {
+		return nil, config.ErrCacheMiss
+	}
+	return &record, nil
+}
+
+func (ir *idempotencyRepository) Update(ctx context.Context, key string, record model.IdempotencyRecord, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	ir.records[key] = idempotencyEntry{record: record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (ir *idempotencyRepository) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	delete(ir.records, key)
+	return nil
+}
+
+// get 期限切れのレコードは削除して存在しないものとして扱う
+func (ir *idempotencyRepository) get(key string) (model.IdempotencyRecord, bool) {
+	entry, ok := ir.records[key]
+	if !ok {
+		return model.IdempotencyRecord{}, false
+	}
+	if time.Now().Before(entry.expiresAt) {
+		return entry.record, true
+	}
+	delete(ir.records, key)
+	return model.IdempotencyRecord{}, false
+}
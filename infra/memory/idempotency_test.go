@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_IdempotencyRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := NewIdempotencyRepository()
+	record := model.NewIdempotencyRecord(http.MethodPost, "/api/gacha/draw", []byte(`{"times":1}`))
+
+	if ok, _ := repo.Reserve(ctx, "key", *record, time.Minute); !ok {
+		t.Errorf("first reserve want: %v, got: %v", true, ok)
+	}
+	if ok, _ := repo.Reserve(ctx, "key", *record, time.Minute); ok {
+		t.Errorf("second reserve want: %v, got: %v", false, ok)
+	}
+
+	record.Complete(http.StatusOK, "application/json", []byte(`{}`))
+	_ = repo.Update(ctx, "key", *record, time.Minute)
+	got, err := repo.Get(ctx, "key")
+	if err != nil || !got.Completed || got.StatusCode != http.StatusOK {
+		t.Errorf("Get() = %v, %v", got, err)
+	}
+
+	_ = repo.Delete(ctx, "key")
+	if _, err = repo.Get(ctx, "key"); !errors.Is(err, config.ErrCacheMiss) {
+		t.Errorf("Get() after delete error = %v, want %v", err, config.ErrCacheMiss)
+	}
+
+	// 期限切れのキーは再度予約できる
+	_, _ = repo.Reserve(ctx, "expired", *record, -time.Second)
+	if ok, _ := repo.Reserve(ctx, "expired", *record, time.Minute); !ok {
+		t.Errorf("reserve after expiry want: %v, got: %v", true, ok)
+	}
+}
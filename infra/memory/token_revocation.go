@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type revocation struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+// tokenRevocationRepository Redisを使わないテスト用の実装
+type tokenRevocationRepository struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+	users  map[string]revocation
+}
+
+func NewTokenRevocationRepository() repository.TokenRevocationRepository {
+	return &tokenRevocationRepository{
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]revocation),
+	}
+}
+
+func (trr *tokenRevocationRepository) Revoke(_ context.Context, jti string, ttl time.Duration) error {
+	trr.mu.Lock()
+	defer trr.mu.Unlock()
+	trr.tokens[jti] = time.Now().Add(ttl)
+	return nil
+}
+
+func (trr *tokenRevocationRepository) RevokeAllForUser(_ context.Context, userID string, revokedAt time.Time, ttl time.Duration) error {
+	trr.mu.Lock()
+	defer trr.mu.Unlock()
+	trr.users[userID] = revocation{
+		revokedAt: revokedAt,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+func (trr *tokenRevocationRepository) IsRevoked(_ context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	trr.mu.Lock()
+	defer trr.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := trr.tokens[jti]; ok {
+		if now.Before(expiresAt) {
+			return true, nil
+		}
+		delete(trr.tokens, jti)
+	}
+	if r, ok := trr.users[userID]; ok {
+		if now.Before(r.expiresAt) {
+			return issuedAt.UnixMilli() <= r.revokedAt.UnixMilli(), nil
+		}
+		delete(trr.users, userID)
+	}
+	return false, nil
+}
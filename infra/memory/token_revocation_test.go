@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_TokenRevocationRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := NewTokenRevocationRepository()
+	issuedAt := time.Now()
+	sameSecond := issuedAt.Truncate(time.Second)
+
+	patterns := []struct {
+		name     string
+		setup    func(t *testing.T)
+		jti      string
+		userID   string
+		issuedAt time.Time
+		want     bool
+	}{
+		{
+			name:     "not revoked",
+			jti:      "jti1",
+			userID:   "user1",
+			issuedAt: issuedAt,
+			want:     false,
+		},
+		{
+			name: "revoked token",
+			setup: func(t *testing.T) {
+				t.Helper()
+				_ = repo.Revoke(ctx, "jti2", time.Minute)
+			},
+			jti:      "jti2",
+			userID:   "user2",
+			issuedAt: issuedAt,
+			want:     true,
+		},
+		{
+			name: "revocation expired",
+			setup: func(t *testing.T) {
+				t.Helper()
+				_ = repo.Revoke(ctx, "jti3", -time.Second)
+			},
+			jti:      "jti3",
+			userID:   "user3",
+			issuedAt: issuedAt,
+			want:     false,
+		},
+		{
+			name: "all tokens of user revoked",
+			setup: func(t *testing.T) {
+				t.Helper()
+				_ = repo.RevokeAllForUser(ctx, "user4", issuedAt, time.Minute)
+			},
+			jti:      "jti4",
+			userID:   "user4",
+			issuedAt: issuedAt,
+			want:     true,
+		},
+		{
+			name: "token issued after user revocation",
+			setup: func(t *testing.T) {
+				t.Helper()
+				_ = repo.RevokeAllForUser(ctx, "user5", issuedAt, time.Minute)
+			},
+			jti:      "jti5",
+			userID:   "user5",
+			issuedAt: issuedAt.Add(time.Second),
+			want:     false,
+		},
+		{
+			name: "token issued in the same second after user revocation",
+			setup: func(t *testing.T) {
+				t.Helper()
+				_ = repo.RevokeAllForUser(ctx, "user6", sameSecond, time.Minute)
+			},
+			jti:      "jti6",
+			userID:   "user6",
+			issuedAt: sameSecond.Add(500 * time.Millisecond),
+			want:     false,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.setup != nil {
+				tt.setup(t)
+			}
+
+			got, err := repo.IsRevoked(ctx, tt.jti, tt.userID, tt.issuedAt)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
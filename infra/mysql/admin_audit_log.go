@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type adminAuditLogRepository struct {
+	db SQLExecutor
+}
+
+func NewAdminAuditLogRepository(db *sql.DB) repository.AdminAuditLogRepository {
+	return &adminAuditLogRepository{
+		db: db,
+	}
+}
+
+func (aalr *adminAuditLogRepository) Create(ctx context.Context, auditLog model.AdminAuditLog) error {
+	executor := aalr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	changes, err := json.Marshal(auditLog.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO Admin_Audit_Logs (
+	id, actor_id, action, target_id, changes, note, created_at
+	)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err = executor.ExecContext(
+		ctx,
+		query,
+		auditLog.ID,
+		auditLog.ActorID,
+		auditLog.Action,
+		auditLog.TargetID,
+		changes,
+		auditLog.Note,
+		auditLog.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (aalr *adminAuditLogRepository) ListByTarget(ctx context.Context, targetID string, limit int) ([]*model.AdminAuditLog, error) {
+	executor := aalr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT id, actor_id, action, target_id, changes, note, created_at
+	FROM Admin_Audit_Logs
+	WHERE target_id = ?
+	ORDER BY created_at DESC, id DESC
+	LIMIT ?`
+
+	rows, err := executor.QueryContext(ctx, query, targetID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditLogs []*model.AdminAuditLog
+	for rows.Next() {
+		var auditLog model.AdminAuditLog
+		var changes []byte
+		if err = rows.Scan(
+			&auditLog.ID,
+			&auditLog.ActorID,
+			&auditLog.Action,
+			&auditLog.TargetID,
+			&changes,
+			&auditLog.Note,
+			&auditLog.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(changes, &auditLog.Changes); err != nil {
+			return nil, err
+		}
+		auditLogs = append(auditLogs, &auditLog)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return auditLogs, nil
+}
@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_AdminAuditLogRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAdminAuditLogRepository(db)
+
+	actorID := uuid.New().String()
+	targetID := uuid.New().String()
+
+	// Create
+	var auditLogs []*model.AdminAuditLog
+	for i, after := range []float64{100, 200} {
+		auditLog, err := model.NewAdminAuditLog(
+			actorID,
+			model.AuditActionUpdateUserStats,
+			targetID,
+			map[string]model.AuditChange{"coins": {Before: after - 100, After: after}},
+			"compensation",
+		)
+		ValidateErr(t, err, nil)
+		auditLog.CreatedAt = auditLog.CreatedAt.Add(time.Duration(i) * time.Second)
+		err = repo.Create(ctx, *auditLog)
+		ValidateErr(t, err, nil)
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	// ListByTarget
+	got, err := repo.ListByTarget(ctx, targetID, 10)
+	ValidateErr(t, err, nil)
+	want := []*model.AdminAuditLog{auditLogs[1], auditLogs[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want: %v, got: %v", want, got)
+	}
+}
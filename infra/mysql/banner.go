@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type bannerRepository struct {
+	db SQLExecutor
+}
+
+func NewBannerRepository(db *sql.DB) repository.BannerRepository {
+	return &bannerRepository{
+		db: db,
+	}
+}
+
+func (br *bannerRepository) Get(ctx context.Context, id string) (*model.Banner, error) {
+	executor := br.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT id, name, cost, currency, start_at, end_at, guarantee_draws, guarantee_min_rarity
+	FROM Banners
+	WHERE id = ?
+	LIMIT 1`
+
+	row := executor.QueryRowContext(ctx, query, id)
+
+	var banner model.Banner
+	if err := row.Scan(
+		&banner.ID,
+		&banner.Name,
+		&banner.Cost,
+		&banner.Currency,
+		&banner.StartAt,
+		&banner.EndAt,
+		&banner.Guarantee.Draws,
+		&banner.Guarantee.MinRarity,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
+		return nil, err
+	}
+
+	items, err := listBannerItems(ctx, executor, banner.ID)
+	if err != nil {
+		return nil, err
+	}
+	banner.Items = items
+	return &banner, nil
+}
+
+func (br *bannerRepository) ListActive(ctx context.Context, now time.Time) ([]*model.Banner, error) {
+	executor := br.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT id, name, cost, currency, start_at, end_at, guarantee_draws, guarantee_min_rarity
+	FROM Banners
+	WHERE start_at <= ? AND end_at > ?
+	ORDER BY end_at, id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, now, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banners []*model.Banner
+	for rows.Next() {
+		var banner model.Banner
+		if err = rows.Scan(
+			&banner.ID,
+			&banner.Name,
+			&banner.Cost,
+			&banner.Currency,
+			&banner.StartAt,
+			&banner.EndAt,
+			&banner.Guarantee.Draws,
+			&banner.Guarantee.MinRarity,
+		); err != nil {
+			return nil, err
+		}
+		banners = append(banners, &banner)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	// 開催中のバナーは少数のため、排出対象はバナーごとに取得する
+	for _, banner := range banners {
+		if banner.Items, err = listBannerItems(ctx, executor, banner.ID); err != nil {
+			return nil, err
+		}
+	}
+	return banners, nil
+}
+
+func listBannerItems(ctx context.Context, executor SQLExecutor, bannerID string) ([]model.BannerItem, error) {
+	query := `SELECT collection_id, weight, rate_up
+	FROM Banner_Items
+	WHERE banner_id = ?
+	ORDER BY collection_id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.BannerItem
+	for rows.Next() {
+		var item model.BannerItem
+		if err = rows.Scan(
+			&item.CollectionID,
+			&item.Weight,
+			&item.RateUp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Create バナーと排出対象を別々に挿入するため、トランザクション内で呼び出す
+func (br *bannerRepository) Create(ctx context.Context, banner model.Banner) error {
+	executor := br.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `INSERT INTO Banners (
+	id, name, cost, currency, start_at, end_at, guarantee_draws, guarantee_min_rarity
+	)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		banner.ID,
+		banner.Name,
+		banner.Cost,
+		banner.Currency,
+		banner.StartAt,
+		banner.EndAt,
+		banner.Guarantee.Draws,
+		banner.Guarantee.MinRarity,
+	); err != nil {
+		return err
+	}
+	if len(banner.Items) == 0 {
+		return nil
+	}
+
+	query = `INSERT INTO Banner_Items (banner_id, collection_id, weight, rate_up) VALUES `
+	values := make([]interface{}, 0, len(banner.Items)*4) //nolint:gomnd // 4 is the number of columns
+	for i, item := range banner.Items {
+		if i > 0 {
+			query += ", "
+		}
+		query += "(?, ?, ?, ?)"
+		values = append(values, banner.ID, item.CollectionID, item.Weight, item.RateUp)
+	}
+	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
+		return err
+	}
+	return nil
+}
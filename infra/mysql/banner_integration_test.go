@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_BannerRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBannerRepository(db)
+
+	collection1, _ := model.NewCollection("banner collection1", 1, 1)
+	collection2, _ := model.NewCollection("banner collection2", 5, 1)
+	err := NewCollectionRepository(db).BatchCreate(ctx, model.Collections{collection1, collection2})
+	ValidateErr(t, err, nil)
+
+	items := []model.BannerItem{
+		{CollectionID: collection1.ID, Weight: 90},
+		{CollectionID: collection2.ID, Weight: 10, RateUp: true},
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CollectionID < items[j].CollectionID })
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	active, err := model.NewBanner("active", 100, model.CurrencyGem, now.Add(-time.Hour), now.Add(time.Hour), items, model.MultiDrawRule{Draws: 10, MinRarity: 5})
+	ValidateErr(t, err, nil)
+	ended, err := model.NewBanner("ended", 100, model.CurrencyCoin, now.Add(-2*time.Hour), now.Add(-time.Hour), items, model.MultiDrawRule{})
+	ValidateErr(t, err, nil)
+
+	// Create
+	err = repo.Create(ctx, *active)
+	ValidateErr(t, err, nil)
+	err = repo.Create(ctx, *ended)
+	ValidateErr(t, err, nil)
+
+	// Get
+	got, err := repo.Get(ctx, active.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(active, got) {
+		t.Errorf("want: %v, got: %v", active, got)
+	}
+
+	_, err = repo.Get(ctx, uuid.New().String())
+	ValidateErr(t, err, config.ErrNotFound)
+
+	// ListActive
+	banners, err := repo.ListActive(ctx, now)
+	ValidateErr(t, err, nil)
+	if len(banners) != 1 || !reflect.DeepEqual(active, banners[0]) {
+		t.Errorf("want: %v, got: %v", []*model.Banner{active}, banners)
+	}
+
+	// 排出対象のアイテムは削除できない
+	err = NewCollectionRepository(db).Delete(ctx, collection1.ID)
+	ValidateErr(t, err, config.ErrReferenced)
+}
@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+// ledgerBalances 台帳から求めたユーザ・通貨ごとの残高
+const ledgerBalances = `SELECT user_id, currency, SUM(delta) AS ledger_balance
+	FROM Coin_Transactions
+	GROUP BY user_id, currency`
+
+type coinTransactionRepository struct {
+	db SQLExecutor
+}
+
+func NewCoinTransactionRepository(db *sql.DB) repository.CoinTransactionRepository {
+	return &coinTransactionRepository{
+		db: db,
+	}
+}
+
+func (ctr *coinTransactionRepository) Create(ctx context.Context, ct model.CoinTransaction) error {
+	executor := ctr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `INSERT INTO Coin_Transactions (
+	id, user_id, currency, reason, delta, balance_after, reference_id, created_at
+	)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		ct.ID,
+		ct.UserID,
+		ct.Currency,
+		ct.Reason,
+		ct.Delta,
+		ct.BalanceAfter,
+		ct.ReferenceID,
+		ct.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ctr *coinTransactionRepository) List(
+	ctx context.Context,
+	userID string,
+	cursor *model.CoinTransactionCursor,
+	limit int,
+) ([]*model.CoinTransaction, error) {
+	executor := ctr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT id, user_id, currency, reason, delta, balance_after, reference_id, created_at
+	FROM Coin_Transactions
+	WHERE user_id = ?
+	`
+	args := []interface{}{userID}
+	if cursor != nil {
+		query += `AND (created_at < ? OR (created_at = ? AND id < ?))
+	`
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+	query += `ORDER BY created_at DESC, id DESC
+	LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cts []*model.CoinTransaction
+	for rows.Next() {
+		var ct model.CoinTransaction
+		if err = rows.Scan(
+			&ct.ID,
+			&ct.UserID,
+			&ct.Currency,
+			&ct.Reason,
+			&ct.Delta,
+			&ct.BalanceAfter,
+			&ct.ReferenceID,
+			&ct.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		cts = append(cts, &ct)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return cts, nil
+}
+
+func (ctr *coinTransactionRepository) ListBalanceMismatches(ctx context.Context) ([]*model.CoinBalanceMismatch, error) {
+	executor := ctr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// 残高の記録がない通貨の台帳のエントリも検出できるよう、両方向から突き合わせる
+	query := `SELECT b.user_id, b.currency, b.balance, COALESCE(l.ledger_balance, 0)
+	FROM User_Balances b
+	LEFT JOIN (` + ledgerBalances + `) l ON l.user_id = b.user_id AND l.currency = b.currency
+	WHERE b.balance <> COALESCE(l.ledger_balance, 0)
+	UNION ALL
+	SELECT l.user_id, l.currency, 0, l.ledger_balance
+	FROM (` + ledgerBalances + `) l
+	LEFT JOIN User_Balances b ON b.user_id = l.user_id AND b.currency = l.currency
+	WHERE b.user_id IS NULL AND l.ledger_balance <> 0
+	`
+
+	rows, err := executor.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mismatches []*model.CoinBalanceMismatch
+	for rows.Next() {
+		var mismatch model.CoinBalanceMismatch
+		if err = rows.Scan(
+			&mismatch.UserID,
+			&mismatch.Currency,
+			&mismatch.Balance,
+			&mismatch.LedgerBalance,
+		); err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, &mismatch)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_CoinTransactionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCoinTransactionRepository(db)
+
+	user, err := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	ValidateErr(t, err, nil)
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+
+	// Create
+	var cts []*model.CoinTransaction
+	balance := 0
+	for i, delta := range []int{100, -30, 50} {
+		balance += delta
+		ct, err := model.NewCoinTransaction(user.ID, model.CurrencyCoin, model.CoinReasonGameReward, delta, balance, "ref") //nolint:govet // This is a valid code
+		ValidateErr(t, err, nil)
+		ct.CreatedAt = ct.CreatedAt.Add(time.Duration(i) * time.Second)
+		err = repo.Create(ctx, *ct)
+		ValidateErr(t, err, nil)
+		cts = append(cts, ct)
+	}
+
+	// List
+	got, err := repo.List(ctx, user.ID, nil, 2)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(got, []*model.CoinTransaction{cts[2], cts[1]}) {
+		t.Errorf("want: %v, got: %v", []*model.CoinTransaction{cts[2], cts[1]}, got)
+	}
+
+	got, err = repo.List(ctx, user.ID, &model.CoinTransactionCursor{CreatedAt: cts[1].CreatedAt, ID: cts[1].ID}, 2)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(got, []*model.CoinTransaction{cts[0]}) {
+		t.Errorf("want: %v, got: %v", []*model.CoinTransaction{cts[0]}, got)
+	}
+
+	// ListBalanceMismatches
+	// コインは残高の行がないまま台帳だけがあり、ジェムは台帳がないまま残高だけがある
+	err = NewWalletRepository(db).Credit(ctx, user.ID, model.CurrencyGem, 10)
+	ValidateErr(t, err, nil)
+
+	mismatches, err := repo.ListBalanceMismatches(ctx)
+	ValidateErr(t, err, nil)
+	var found []*model.CoinBalanceMismatch
+	for _, mismatch := range mismatches {
+		if mismatch.UserID == user.ID {
+			found = append(found, mismatch)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Currency < found[j].Currency })
+	want := []*model.CoinBalanceMismatch{
+		{UserID: user.ID, Currency: model.CurrencyCoin, Balance: 0, LedgerBalance: balance},
+		{UserID: user.ID, Currency: model.CurrencyGem, Balance: 10, LedgerBalance: 0},
+	}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("want: %v, got: %v", want, found)
+	}
+}
@@ -3,11 +3,18 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/domain/repository"
 )
 
+// errRowIsReferenced 外部キー制約により参照されている行を削除しようとした場合のエラー番号(ER_ROW_IS_REFERENCED_2)
+const errRowIsReferenced = 1451
+
 type collectionRepository struct {
 	db SQLExecutor
 }
@@ -38,6 +45,9 @@ func (cr *collectionRepository) Get(ctx context.Context, id string) (*model.Coll
 		&collection.Rarity,
 		&collection.Weight,
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
 		return nil, err
 	}
 	return &collection, nil
@@ -161,6 +171,11 @@ func (cr *collectionRepository) Delete(ctx context.Context, id string) error {
 	`
 
 	if _, err := executor.ExecContext(ctx, query, id); err != nil {
+		// ユーザが所持しているアイテムは外部キー制約により削除できない
+		var mysqlErr *mysqldriver.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errRowIsReferenced {
+			return config.ErrReferenced
+		}
 		return err
 	}
 	return nil
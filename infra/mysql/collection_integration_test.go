@@ -4,7 +4,11 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 )
 
@@ -71,7 +75,15 @@ func Test_CollectionRepository(t *testing.T) {
 	ValidateErr(t, err, nil)
 
 	_, err = repo.Get(ctx, collection1.ID)
-	if err == nil {
-		t.Errorf("want: %v, got: %v", nil, err)
-	}
+	ValidateErr(t, err, config.ErrNotFound)
+
+	// 所持しているユーザがいるアイテムは削除できない
+	user, _ := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+	userCollection, _ := model.NewUserCollection(user.ID, collection2.ID, 1, time.Now())
+	err = NewUserCollectionRepository(db).Create(ctx, *userCollection)
+	ValidateErr(t, err, nil)
+	err = repo.Delete(ctx, collection2.ID)
+	ValidateErr(t, err, config.ErrReferenced)
 }
@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+const gachaDrawColumns = `draw_id, slot, user_id, banner_id, collection_id, rarity, cost,
+	currency, pity_count, guaranteed, rng, pool_version, created_at`
+
+type gachaDrawRepository struct {
+	db SQLExecutor
+}
+
+func NewGachaDrawRepository(db *sql.DB) repository.GachaDrawRepository {
+	return &gachaDrawRepository{
+		db: db,
+	}
+}
+
+func (gdr *gachaDrawRepository) BatchCreate(ctx context.Context, records []*model.GachaDrawRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	executor := gdr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	placeholders := make([]string, 0, len(records))
+	values := make([]interface{}, 0, len(records)*13) //nolint:gomnd // 13 is the number of columns
+	for _, record := range records {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		values = append(
+			values,
+			record.DrawID,
+			record.Slot,
+			record.UserID,
+			record.BannerID,
+			record.CollectionID,
+			record.Rarity,
+			record.Cost,
+			record.Currency,
+			record.PityCount,
+			record.Guaranteed,
+			record.RNG,
+			record.PoolVersion,
+			record.CreatedAt,
+		)
+	}
+	query := `INSERT INTO Gacha_Draws (` + gachaDrawColumns + `) VALUES ` + strings.Join(placeholders, ", ")
+
+	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (gdr *gachaDrawRepository) List(
+	ctx context.Context,
+	userID string,
+	cursor *model.GachaDrawCursor,
+	limit int,
+) ([]*model.GachaDrawRecord, error) {
+	query := `SELECT ` + gachaDrawColumns + `
+	FROM Gacha_Draws
+	WHERE user_id = ?
+	`
+	return gdr.list(ctx, query, []interface{}{userID}, cursor, limit)
+}
+
+func (gdr *gachaDrawRepository) ListByPeriod(
+	ctx context.Context,
+	from, to time.Time,
+	cursor *model.GachaDrawCursor,
+	limit int,
+) ([]*model.GachaDrawRecord, error) {
+	query := `SELECT ` + gachaDrawColumns + `
+	FROM Gacha_Draws
+	WHERE created_at >= ? AND created_at < ?
+	`
+	return gdr.list(ctx, query, []interface{}{from.UTC(), to.UTC()}, cursor, limit)
+}
+
+// list queryの条件に、cursorより古い記録に絞る条件と並び順を加えて実行する
+func (gdr *gachaDrawRepository) list(
+	ctx context.Context,
+	query string,
+	args []interface{},
+	cursor *model.GachaDrawCursor,
+	limit int,
+) ([]*model.GachaDrawRecord, error) {
+	executor := gdr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	if cursor != nil {
+		query += `AND (created_at, draw_id, slot) < (?, ?, ?)
+	`
+		args = append(args, cursor.CreatedAt, cursor.DrawID, cursor.Slot)
+	}
+	query += `ORDER BY created_at DESC, draw_id DESC, slot DESC
+	LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*model.GachaDrawRecord
+	for rows.Next() {
+		var record model.GachaDrawRecord
+		if err = rows.Scan(
+			&record.DrawID,
+			&record.Slot,
+			&record.UserID,
+			&record.BannerID,
+			&record.CollectionID,
+			&record.Rarity,
+			&record.Cost,
+			&record.Currency,
+			&record.PityCount,
+			&record.Guaranteed,
+			&record.RNG,
+			&record.PoolVersion,
+			&record.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
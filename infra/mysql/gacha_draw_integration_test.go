@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_GachaDrawRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewGachaDrawRepository(db)
+
+	user, err := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	ValidateErr(t, err, nil)
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+
+	collection, err := model.NewCollection("draw", 5, 1)
+	ValidateErr(t, err, nil)
+	err = NewCollectionRepository(db).Create(ctx, *collection)
+	ValidateErr(t, err, nil)
+	now := time.Now()
+	banner, err := model.NewBanner("draw", 100, model.CurrencyEventToken, now.Add(-time.Hour), now.Add(time.Hour), []model.BannerItem{
+		{CollectionID: collection.ID, Weight: 1},
+	}, model.MultiDrawRule{})
+	ValidateErr(t, err, nil)
+	err = NewBannerRepository(db).Create(ctx, *banner)
+	ValidateErr(t, err, nil)
+
+	// BatchCreate
+	var records []*model.GachaDrawRecord
+	for i := 0; i < 2; i++ {
+		drawID := uuid.New().String()
+		createdAt := now.Add(time.Duration(i) * time.Second)
+		for slot := 0; slot < 2; slot++ {
+			item := &model.DrawnItem{Collection: collection, PityCount: 0, Guaranteed: slot == 1}
+			record, err := model.NewGachaDrawRecord(drawID, slot, user.ID, banner, item, "crypto/rand", "version", createdAt) //nolint:govet // This is a valid code
+			ValidateErr(t, err, nil)
+			records = append(records, record)
+		}
+	}
+	err = repo.BatchCreate(ctx, records)
+	ValidateErr(t, err, nil)
+
+	// List
+	got, err := repo.List(ctx, user.ID, nil, 3)
+	ValidateErr(t, err, nil)
+	want := []*model.GachaDrawRecord{records[3], records[2], records[1]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want: %v, got: %v", want, got)
+	}
+
+	got, err = repo.List(ctx, user.ID, &model.GachaDrawCursor{CreatedAt: records[1].CreatedAt, DrawID: records[1].DrawID, Slot: records[1].Slot}, 3)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(got, []*model.GachaDrawRecord{records[0]}) {
+		t.Errorf("want: %v, got: %v", []*model.GachaDrawRecord{records[0]}, got)
+	}
+
+	// ListByPeriod
+	got, err = repo.ListByPeriod(ctx, records[0].CreatedAt, records[2].CreatedAt, nil, 10)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(got, []*model.GachaDrawRecord{records[1], records[0]}) {
+		t.Errorf("want: %v, got: %v", []*model.GachaDrawRecord{records[1], records[0]}, got)
+	}
+}
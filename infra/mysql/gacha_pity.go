@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type gachaPityRepository struct {
+	db SQLExecutor
+}
+
+func NewGachaPityRepository(db *sql.DB) repository.GachaPityRepository {
+	return &gachaPityRepository{
+		db: db,
+	}
+}
+
+func (gpr *gachaPityRepository) GetForUpdate(ctx context.Context, userID, bannerID string) (*model.GachaPity, error) {
+	executor := gpr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT user_id, banner_id, pity_count
+	FROM Gacha_Pities
+	WHERE user_id = ? AND banner_id = ?
+	LIMIT 1
+	FOR UPDATE`
+
+	row := executor.QueryRowContext(ctx, query, userID, bannerID)
+
+	var pity model.GachaPity
+	if err := row.Scan(
+		&pity.UserID,
+		&pity.BannerID,
+		&pity.Count,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
+		return nil, err
+	}
+	return &pity, nil
+}
+
+func (gpr *gachaPityRepository) Upsert(ctx context.Context, pity model.GachaPity) error {
+	executor := gpr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `INSERT INTO Gacha_Pities (user_id, banner_id, pity_count)
+	VALUES (?, ?, ?)
+	ON DUPLICATE KEY UPDATE pity_count = VALUES(pity_count)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		pity.UserID,
+		pity.BannerID,
+		pity.Count,
+	); err != nil {
+		return err
+	}
+	return nil
+}
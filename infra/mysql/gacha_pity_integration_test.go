@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_GachaPityRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewGachaPityRepository(db)
+
+	user, err := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	ValidateErr(t, err, nil)
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+
+	collection, err := model.NewCollection("pity", 5, 1)
+	ValidateErr(t, err, nil)
+	err = NewCollectionRepository(db).Create(ctx, *collection)
+	ValidateErr(t, err, nil)
+	now := time.Now()
+	banner, err := model.NewBanner("pity", 100, model.CurrencyCoin, now.Add(-time.Hour), now.Add(time.Hour), []model.BannerItem{
+		{CollectionID: collection.ID, Weight: 1},
+	}, model.MultiDrawRule{})
+	ValidateErr(t, err, nil)
+	err = NewBannerRepository(db).Create(ctx, *banner)
+	ValidateErr(t, err, nil)
+
+	_, err = repo.GetForUpdate(ctx, user.ID, banner.ID)
+	ValidateErr(t, err, config.ErrNotFound)
+
+	pity, err := model.NewGachaPity(user.ID, banner.ID)
+	ValidateErr(t, err, nil)
+	pity.Count = 3
+
+	// Upsert (insert)
+	err = repo.Upsert(ctx, *pity)
+	ValidateErr(t, err, nil)
+
+	got, err := repo.GetForUpdate(ctx, user.ID, banner.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(pity, got) {
+		t.Errorf("want: %v, got: %v", pity, got)
+	}
+
+	// Upsert (update)
+	pity.Count = 0
+	err = repo.Upsert(ctx, *pity)
+	ValidateErr(t, err, nil)
+
+	got, err = repo.GetForUpdate(ctx, user.ID, banner.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(pity, got) {
+		t.Errorf("want: %v, got: %v", pity, got)
+	}
+}
@@ -0,0 +1,242 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+const concurrentRequests = 10
+
+func setupConcurrencyTest(t *testing.T, coins int) (context.Context, *model.User, *model.Collection) {
+	t.Helper()
+
+	ctx := context.Background()
+	user, err := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	ValidateErr(t, err, nil)
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+	if coins > 0 {
+		err = NewWalletRepository(db).Credit(ctx, user.ID, model.CurrencyCoin, coins)
+		ValidateErr(t, err, nil)
+	}
+
+	collection, err := model.NewCollection("concurrency", 1, 1)
+	ValidateErr(t, err, nil)
+	err = NewCollectionRepository(db).Create(ctx, *collection)
+	ValidateErr(t, err, nil)
+
+	return model.ContextWithPrincipal(ctx, model.Principal{UserID: user.ID, Role: model.RolePlayer}), user, collection
+}
+
+func createConcurrencyTestBanner(t *testing.T, collection *model.Collection, cost int) *model.Banner {
+	t.Helper()
+
+	now := time.Now()
+	banner, err := model.NewBanner("concurrency", cost, model.CurrencyCoin, now.Add(-time.Hour), now.Add(time.Hour), []model.BannerItem{
+		{CollectionID: collection.ID, Weight: 1},
+	}, model.MultiDrawRule{})
+	ValidateErr(t, err, nil)
+	err = NewBannerRepository(db).Create(context.Background(), *banner)
+	ValidateErr(t, err, nil)
+	return banner
+}
+
+func newConcurrencyTestGameUseCase(t *testing.T, collection *model.Collection) usecase.GameUseCase {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	ccr := mock.NewMockCollectionCacheRepository(ctrl)
+	ccr.EXPECT().Get(gomock.Any(), "collections").Return(model.Collections{collection}, nil).AnyTimes()
+	rr := mock.NewMockRankingRepository(ctrl)
+	rr.EXPECT().Create(gomock.Any(), model.ScoreBoardKey, gomock.Any()).Return(nil).AnyTimes()
+
+	return usecase.NewGameUseCase(
+		NewTransactionRepository(db),
+		NewUserRepository(db),
+		NewUserCollectionRepository(db),
+		NewScoreRepository(db),
+		rr,
+		NewCollectionRepository(db),
+		ccr,
+		mock.NewMockDropRatesCacheRepository(ctrl),
+		NewCoinTransactionRepository(db),
+		NewWalletRepository(db),
+		NewGameSessionRepository(db),
+		NewBannerRepository(db),
+		NewGachaPityRepository(db),
+		NewGachaDrawRepository(db),
+		usecase.GameSessionConfig{
+			Secret: []byte("secret"),
+			// 開始直後に終了するため、プレイ時間の下限は設けない
+			Rules: model.GameSessionRules{
+				MaxDuration:       time.Hour,
+				MaxScorePerSecond: 1 << 20,
+			},
+		},
+		model.PityRules{HardPity: 10},
+		model.NewSeededRandomSource(1),
+	)
+}
+
+func Test_DrawGacha_Concurrent(t *testing.T) {
+	// 排出されるアイテムは1種類なので、2回目以降は重複として所持数に加算される
+	// 500 -> 400 -> 300 -> 200 -> 100 -> 0 となり、5回だけ成功する
+	const wantSuccess = 5
+	const cost = 100
+	wantCoins := 5*cost - wantSuccess*cost
+
+	ctx, user, collection := setupConcurrencyTest(t, 5*cost)
+	banner := createConcurrencyTestBanner(t, collection, cost)
+	guc := newConcurrencyTestGameUseCase(t, collection)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var success, insufficient int
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := guc.DrawGacha(ctx, banner.ID, 1)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				success++
+			case errors.Is(err, model.ErrInsufficientBalance):
+				insufficient++
+			default:
+				t.Errorf("DrawGacha() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if success != wantSuccess || insufficient != concurrentRequests-wantSuccess {
+		t.Errorf("success = %v, insufficient = %v, want %v, %v", success, insufficient, wantSuccess, concurrentRequests-wantSuccess)
+	}
+	wallet, err := NewWalletRepository(db).Get(context.Background(), user.ID)
+	ValidateErr(t, err, nil)
+	if wallet[model.CurrencyCoin] != wantCoins {
+		t.Errorf("coins want: %v, got: %v", wantCoins, wallet[model.CurrencyCoin])
+	}
+	userCollections, err := NewUserCollectionRepository(db).List(context.Background(), user.ID)
+	ValidateErr(t, err, nil)
+	if len(userCollections) != 1 || userCollections[0].Quantity != wantSuccess {
+		t.Errorf("user collections want: 1 item with quantity %v, got: %v", wantSuccess, userCollections)
+	}
+
+	// 成功した抽選ごとに消費が台帳に記録され、最新の残高が一致する
+	cts, err := NewCoinTransactionRepository(db).List(context.Background(), user.ID, nil, concurrentRequests*2)
+	ValidateErr(t, err, nil)
+	if len(cts) != wantSuccess {
+		t.Errorf("coin transactions want: %v, got: %v", wantSuccess, len(cts))
+	}
+	if len(cts) > 0 && cts[0].BalanceAfter != wantCoins {
+		t.Errorf("balance after want: %v, got: %v", wantCoins, cts[0].BalanceAfter)
+	}
+
+	// 成功した抽選だけが記録される
+	draws, err := NewGachaDrawRepository(db).List(context.Background(), user.ID, nil, concurrentRequests)
+	ValidateErr(t, err, nil)
+	if len(draws) != wantSuccess {
+		t.Errorf("gacha draws want: %v, got: %v", wantSuccess, len(draws))
+	}
+}
+
+func Test_FinishGame_Concurrent(t *testing.T) {
+	const score = 100
+	var game model.Game
+	wantCoins := concurrentRequests * game.Reward(score)
+
+	ctx, user, collection := setupConcurrencyTest(t, 0)
+	guc := newConcurrencyTestGameUseCase(t, collection)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start, err := guc.StartGame(ctx)
+			if err != nil {
+				t.Errorf("StartGame() unexpected error = %v", err)
+				return
+			}
+			if _, err = guc.FinishGame(ctx, start.SessionToken, score); err != nil {
+				t.Errorf("FinishGame() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	wallet, err := NewWalletRepository(db).Get(context.Background(), user.ID)
+	ValidateErr(t, err, nil)
+	if wallet[model.CurrencyCoin] != wantCoins {
+		t.Errorf("coins want: %v, got: %v", wantCoins, wallet[model.CurrencyCoin])
+	}
+	gotUser, err := NewUserRepository(db).Get(context.Background(), user.ID)
+	ValidateErr(t, err, nil)
+	if gotUser.HighScore != score {
+		t.Errorf("high score want: %v, got: %v", score, gotUser.HighScore)
+	}
+
+	// 初期残高が0なので、台帳の合計はUser_Balancesの残高と一致する
+	mismatches, err := NewCoinTransactionRepository(db).ListBalanceMismatches(context.Background())
+	ValidateErr(t, err, nil)
+	for _, mismatch := range mismatches {
+		if mismatch.UserID == user.ID {
+			t.Errorf("%v ledger balance want: %v, got: %v", mismatch.Currency, mismatch.Balance, mismatch.LedgerBalance)
+		}
+	}
+}
+
+func Test_FinishGame_ConcurrentSameSession(t *testing.T) {
+	const score = 100
+	var game model.Game
+
+	ctx, user, collection := setupConcurrencyTest(t, 0)
+	guc := newConcurrencyTestGameUseCase(t, collection)
+	start, err := guc.StartGame(ctx)
+	ValidateErr(t, err, nil)
+
+	// 同じセッションでの終了は1回だけ成功し、残りは終了済みとして拒否される
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var success, finished int
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := guc.FinishGame(ctx, start.SessionToken, score)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				success++
+			case errors.Is(err, model.ErrGameSessionFinished):
+				finished++
+			default:
+				t.Errorf("FinishGame() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if success != 1 || finished != concurrentRequests-1 {
+		t.Errorf("success = %v, finished = %v, want %v, %v", success, finished, 1, concurrentRequests-1)
+	}
+	wallet, err := NewWalletRepository(db).Get(context.Background(), user.ID)
+	ValidateErr(t, err, nil)
+	if wallet[model.CurrencyCoin] != game.Reward(score) {
+		t.Errorf("coins want: %v, got: %v", game.Reward(score), wallet[model.CurrencyCoin])
+	}
+}
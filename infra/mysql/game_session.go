@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type gameSessionRepository struct {
+	db SQLExecutor
+}
+
+func NewGameSessionRepository(db *sql.DB) repository.GameSessionRepository {
+	return &gameSessionRepository{
+		db: db,
+	}
+}
+
+func (gsr *gameSessionRepository) Create(ctx context.Context, session model.GameSession) error {
+	executor := gsr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `INSERT INTO Game_Sessions (
+	id, user_id, seed, started_at, finished_at, score
+	)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.Seed,
+		session.StartedAt,
+		session.FinishedAt,
+		session.Score,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (gsr *gameSessionRepository) GetForUpdate(ctx context.Context, id string) (*model.GameSession, error) {
+	executor := gsr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT id, user_id, seed, started_at, finished_at, score
+	FROM Game_Sessions
+	WHERE id = ?
+	LIMIT 1
+	FOR UPDATE`
+
+	row := executor.QueryRowContext(ctx, query, id)
+
+	var session model.GameSession
+	var finishedAt sql.NullTime
+	var score sql.NullInt64
+	if err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Seed,
+		&session.StartedAt,
+		&finishedAt,
+		&score,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
+		return nil, err
+	}
+	if finishedAt.Valid {
+		session.FinishedAt = &finishedAt.Time
+	}
+	if score.Valid {
+		s := int(score.Int64)
+		session.Score = &s
+	}
+	return &session, nil
+}
+
+func (gsr *gameSessionRepository) Update(ctx context.Context, session model.GameSession) error {
+	executor := gsr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `UPDATE Game_Sessions
+	SET finished_at = ?, score = ?
+	WHERE id = ?
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		session.FinishedAt,
+		session.Score,
+		session.ID,
+	); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_GameSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewGameSessionRepository(db)
+
+	user, err := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	ValidateErr(t, err, nil)
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+
+	session, err := model.NewGameSession(user.ID, 42, time.Now().Add(-time.Minute))
+	ValidateErr(t, err, nil)
+
+	// Create
+	err = repo.Create(ctx, *session)
+	ValidateErr(t, err, nil)
+
+	// GetForUpdate
+	got, err := repo.GetForUpdate(ctx, session.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(session, got) {
+		t.Errorf("want: %v, got: %v", session, got)
+	}
+
+	// Update
+	err = session.Finish(user.ID, 100, time.Now(), model.GameSessionRules{MaxDuration: time.Hour, MaxScorePerSecond: 10})
+	ValidateErr(t, err, nil)
+	err = repo.Update(ctx, *session)
+	ValidateErr(t, err, nil)
+
+	got, err = repo.GetForUpdate(ctx, session.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(session, got) {
+		t.Errorf("want: %v, got: %v", session, got)
+	}
+
+	_, err = repo.GetForUpdate(ctx, uuid.New().String())
+	ValidateErr(t, err, config.ErrNotFound)
+}
@@ -33,3 +33,11 @@ INSERT INTO Collections (id, name, rarity, weight) VALUES
 (UUID(), 'アイテム28', 1, 7),
 (UUID(), 'アイテム29', 2, 8),
 (UUID(), 'アイテム30', 3, 3);
+
+-- 常設ガチャ: 全アイテムをマスタの重みのまま排出し、10連ではレアリティ2以上を1つ保証する
+-- コインで価格を付け、無償のコインが足りない分は有償のジェムで支払う
+INSERT INTO Banners (id, name, cost, currency, start_at, end_at, guarantee_draws, guarantee_min_rarity) VALUES
+('00000000-0000-0000-0000-000000000001', '常設ガチャ', 100, 'coin', '2024-01-01 00:00:00', '2099-12-31 23:59:59', 10, 2);
+
+INSERT INTO Banner_Items (banner_id, collection_id, weight, rate_up)
+SELECT '00000000-0000-0000-0000-000000000001', id, weight, FALSE FROM Collections;
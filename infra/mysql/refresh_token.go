@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+)
+
+type refreshTokenRepository struct {
+	db SQLExecutor
+}
+
+func NewRefreshTokenRepository(db *sql.DB) repository.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (rtr *refreshTokenRepository) GetForUpdate(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	executor := rtr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, created_at, rotated_at, revoked_at
+	FROM Refresh_Tokens
+	WHERE token_hash = ?
+	LIMIT 1
+	FOR UPDATE`
+
+	row := executor.QueryRowContext(ctx, query, tokenHash)
+
+	var token model.RefreshToken
+	var rotatedAt, revokedAt sql.NullTime
+	if err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&rotatedAt,
+		&revokedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
+		return nil, err
+	}
+	if rotatedAt.Valid {
+		token.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
+
+func (rtr *refreshTokenRepository) Create(ctx context.Context, token model.RefreshToken) error {
+	executor := rtr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `INSERT INTO Refresh_Tokens (
+	id, user_id, family_id, token_hash, expires_at, created_at
+	)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rtr *refreshTokenRepository) Update(ctx context.Context, token model.RefreshToken) error {
+	executor := rtr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `UPDATE Refresh_Tokens
+	SET rotated_at = ?, revoked_at = ?
+	WHERE id = ?
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		token.RotatedAt,
+		token.RevokedAt,
+		token.ID,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rtr *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	executor := rtr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `UPDATE Refresh_Tokens
+	SET revoked_at = ?
+	WHERE family_id = ? AND revoked_at IS NULL
+	`
+
+	if _, err := executor.ExecContext(ctx, query, revokedAt, familyID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rtr *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	executor := rtr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `UPDATE Refresh_Tokens
+	SET revoked_at = ?
+	WHERE user_id = ? AND revoked_at IS NULL
+	`
+
+	if _, err := executor.ExecContext(ctx, query, revokedAt, userID); err != nil {
+		return err
+	}
+	return nil
+}
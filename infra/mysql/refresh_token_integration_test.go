@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_RefreshTokenRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRefreshTokenRepository(db)
+
+	// setup
+	userRepo := NewUserRepository(db)
+	user := model.User{
+		ID:       uuid.New().String(),
+		Name:     "test",
+		Email:    "refresh@gmail.com",
+		Password: "password",
+	}
+	err := userRepo.Create(ctx, user)
+	ValidateErr(t, err, nil)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	token1, _ := model.NewRefreshToken(user.ID, "", "hash1", now, time.Hour)
+	token2, _ := model.NewRefreshToken(user.ID, token1.FamilyID, "hash2", now, time.Hour)
+
+	// Create
+	err = repo.Create(ctx, *token1)
+	ValidateErr(t, err, nil)
+	err = repo.Create(ctx, *token2)
+	ValidateErr(t, err, nil)
+
+	// GetForUpdate
+	got, err := repo.GetForUpdate(ctx, "hash1")
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(token1, got) {
+		t.Errorf("want: %v, got: %v", token1, got)
+	}
+
+	_, err = repo.GetForUpdate(ctx, "unknown")
+	if !errors.Is(err, config.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", config.ErrNotFound, err)
+	}
+
+	// Update
+	got.Rotate(now)
+	err = repo.Update(ctx, *got)
+	ValidateErr(t, err, nil)
+
+	rotated, err := repo.GetForUpdate(ctx, "hash1")
+	ValidateErr(t, err, nil)
+	if !rotated.IsRotated() {
+		t.Errorf("want rotated token, got: %v", rotated)
+	}
+
+	// RevokeFamily
+	err = repo.RevokeFamily(ctx, token1.FamilyID, now)
+	ValidateErr(t, err, nil)
+
+	revoked, err := repo.GetForUpdate(ctx, "hash2")
+	ValidateErr(t, err, nil)
+	if revoked.RevokedAt == nil || !revoked.RevokedAt.Equal(now) {
+		t.Errorf("want revoked at %v, got: %v", now, revoked.RevokedAt)
+	}
+
+	// RevokeAllForUser
+	token3, _ := model.NewRefreshToken(user.ID, "", "hash3", now, time.Hour)
+	err = repo.Create(ctx, *token3)
+	ValidateErr(t, err, nil)
+
+	err = repo.RevokeAllForUser(ctx, user.ID, now)
+	ValidateErr(t, err, nil)
+
+	revoked, err = repo.GetForUpdate(ctx, "hash3")
+	ValidateErr(t, err, nil)
+	if revoked.IsActive(now) {
+		t.Errorf("want revoked token, got: %v", revoked)
+	}
+}
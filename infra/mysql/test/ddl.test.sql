@@ -1,6 +1,15 @@
 CREATE DATABASE IF NOT EXISTS `goTechDojoTestDB` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
 USE `goTechDojoTestDB`;
 
+DROP TABLE IF EXISTS Refresh_Tokens CASCADE;
+DROP TABLE IF EXISTS Coin_Transactions CASCADE;
+DROP TABLE IF EXISTS User_Balances CASCADE;
+DROP TABLE IF EXISTS Admin_Audit_Logs CASCADE;
+DROP TABLE IF EXISTS Game_Sessions CASCADE;
+DROP TABLE IF EXISTS Gacha_Draws CASCADE;
+DROP TABLE IF EXISTS Gacha_Pities CASCADE;
+DROP TABLE IF EXISTS Banner_Items CASCADE;
+DROP TABLE IF EXISTS Banners CASCADE;
 DROP TABLE IF EXISTS Users CASCADE;
 DROP TABLE IF EXISTS Collections CASCADE;
 DROP TABLE IF EXISTS Scores CASCADE;
@@ -12,8 +21,10 @@ CREATE TABLE Users (
     name VARCHAR(255) NOT NULL,
     email VARCHAR(255) NOT NULL,
     password VARCHAR(255) NOT NULL,
-    coins INT DEFAULT 0,
-    high_score INT DEFAULT 0
+    high_score INT DEFAULT 0,
+    avatar_url VARCHAR(512) NOT NULL DEFAULT '',
+    locale VARCHAR(35) NOT NULL DEFAULT '',
+    role VARCHAR(16) NOT NULL DEFAULT 'player'
 );
 
 -- Collections Table
@@ -36,7 +47,129 @@ CREATE TABLE Scores (
 CREATE TABLE User_Collections (
     user_id CHAR(36),
     collection_id CHAR(36),
+    quantity INT NOT NULL DEFAULT 1,
+    first_obtained_at DATETIME(6) NOT NULL,
+    last_obtained_at DATETIME(6) NOT NULL,
     UNIQUE(user_id, collection_id),
     FOREIGN KEY (user_id) REFERENCES Users(id),
     FOREIGN KEY (collection_id) REFERENCES Collections(id)
-);
\ No newline at end of file
+);
+
+-- RefreshTokens Table
+CREATE TABLE Refresh_Tokens (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    family_id CHAR(36) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    rotated_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    UNIQUE(token_hash),
+    INDEX(family_id),
+    FOREIGN KEY (user_id) REFERENCES Users(id)
+);
+
+-- CoinTransactions Table
+CREATE TABLE Coin_Transactions (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    currency VARCHAR(16) NOT NULL DEFAULT 'coin',
+    reason VARCHAR(64) NOT NULL,
+    delta INT NOT NULL,
+    balance_after INT NOT NULL,
+    reference_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL,
+    INDEX(user_id, created_at, id),
+    FOREIGN KEY (user_id) REFERENCES Users(id)
+);
+
+-- UserBalances Table
+CREATE TABLE User_Balances (
+    user_id CHAR(36) NOT NULL,
+    currency VARCHAR(16) NOT NULL,
+    balance INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, currency),
+    FOREIGN KEY (user_id) REFERENCES Users(id)
+);
+
+-- AdminAuditLogs Table
+CREATE TABLE Admin_Audit_Logs (
+    id CHAR(36) PRIMARY KEY,
+    actor_id CHAR(36) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_id CHAR(36) NOT NULL,
+    changes JSON NOT NULL,
+    note VARCHAR(255) NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    INDEX(target_id, created_at),
+    INDEX(actor_id, created_at)
+);
+
+-- GameSessions Table
+CREATE TABLE Game_Sessions (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    seed BIGINT NOT NULL,
+    started_at DATETIME(6) NOT NULL,
+    finished_at DATETIME(6) NULL,
+    score INT NULL,
+    INDEX(user_id, started_at),
+    FOREIGN KEY (user_id) REFERENCES Users(id)
+);
+
+-- Banners Table
+CREATE TABLE Banners (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    cost INT NOT NULL,
+    currency VARCHAR(16) NOT NULL DEFAULT 'coin',
+    start_at DATETIME(6) NOT NULL,
+    end_at DATETIME(6) NOT NULL,
+    guarantee_draws INT NOT NULL DEFAULT 0,
+    guarantee_min_rarity INT NOT NULL DEFAULT 0,
+    INDEX(start_at, end_at)
+);
+
+-- BannerItems Table
+CREATE TABLE Banner_Items (
+    banner_id CHAR(36) NOT NULL,
+    collection_id CHAR(36) NOT NULL,
+    weight INT NOT NULL,
+    rate_up BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (banner_id, collection_id),
+    FOREIGN KEY (banner_id) REFERENCES Banners(id),
+    FOREIGN KEY (collection_id) REFERENCES Collections(id)
+);
+
+-- GachaPities Table
+CREATE TABLE Gacha_Pities (
+    user_id CHAR(36) NOT NULL,
+    banner_id CHAR(36) NOT NULL,
+    pity_count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, banner_id),
+    FOREIGN KEY (user_id) REFERENCES Users(id),
+    FOREIGN KEY (banner_id) REFERENCES Banners(id)
+);
+
+-- GachaDraws Table
+CREATE TABLE Gacha_Draws (
+    draw_id CHAR(36) NOT NULL,
+    slot INT NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    banner_id CHAR(36) NOT NULL,
+    collection_id CHAR(36) NOT NULL,
+    rarity INT NOT NULL,
+    cost INT NOT NULL,
+    currency VARCHAR(16) NOT NULL DEFAULT 'coin',
+    pity_count INT NOT NULL,
+    guaranteed BOOLEAN NOT NULL DEFAULT FALSE,
+    rng VARCHAR(64) NOT NULL,
+    pool_version VARCHAR(64) NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    PRIMARY KEY (draw_id, slot),
+    INDEX(user_id, created_at, draw_id, slot),
+    INDEX(created_at, draw_id, slot),
+    FOREIGN KEY (user_id) REFERENCES Users(id),
+    FOREIGN KEY (banner_id) REFERENCES Banners(id)
+);
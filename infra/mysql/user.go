@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/domain/repository"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
@@ -39,14 +40,81 @@ func (ur *userRepository) Get(ctx context.Context, id string) (*model.User, erro
 		&user.Name,
 		&user.Email,
 		&user.Password,
-		&user.Coins,
 		&user.HighScore,
+		&user.AvatarURL,
+		&user.Locale,
+		&user.Role,
 	); err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+func (ur *userRepository) GetForUpdate(ctx context.Context, id string) (*model.User, error) {
+	executor := ur.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT *
+	FROM Users
+	WHERE id = ?
+	LIMIT 1
+	FOR UPDATE`
+
+	row := executor.QueryRowContext(ctx, query, id)
+
+	var user model.User
+	if err := row.Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Password,
+		&user.HighScore,
+		&user.AvatarURL,
+		&user.Locale,
+		&user.Role,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	executor := ur.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT *
+	FROM Users
+	WHERE email = ?
+	LIMIT 1`
+
+	row := executor.QueryRowContext(ctx, query, email)
+
+	var user model.User
+	if err := row.Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Password,
+		&user.HighScore,
+		&user.AvatarURL,
+		&user.Locale,
+		&user.Role,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, config.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (ur *userRepository) Create(ctx context.Context, user model.User) error {
 	executor := ur.db
 	if tx := TxFromCtx(ctx); tx != nil {
@@ -54,9 +122,9 @@ func (ur *userRepository) Create(ctx context.Context, user model.User) error {
 	}
 
 	query := `INSERT INTO Users (
-	id, name, email, password, coins, high_score
+	id, name, email, password, high_score, avatar_url, locale, role
 	)
-	VALUES (?, ?, ?, ?, ?, ?)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	if _, err := executor.ExecContext(
@@ -66,8 +134,10 @@ func (ur *userRepository) Create(ctx context.Context, user model.User) error {
 		user.Name,
 		user.Email,
 		user.Password,
-		user.Coins,
 		user.HighScore,
+		user.AvatarURL,
+		user.Locale,
+		user.Role,
 	); err != nil {
 		return err
 	}
@@ -81,7 +151,7 @@ func (ur *userRepository) Update(ctx context.Context, user model.User) error {
 	}
 
 	query := `UPDATE Users
-	SET name = ?, email = ?, password = ?, coins = ?, high_score = ?
+	SET name = ?, email = ?, password = ?, high_score = ?, avatar_url = ?, locale = ?, role = ?
 	WHERE id = ?
 	`
 
@@ -91,8 +161,10 @@ func (ur *userRepository) Update(ctx context.Context, user model.User) error {
 		user.Name,
 		user.Email,
 		user.Password,
-		user.Coins,
 		user.HighScore,
+		user.AvatarURL,
+		user.Locale,
+		user.Role,
 		user.ID,
 	); err != nil {
 		return err
@@ -100,6 +172,28 @@ func (ur *userRepository) Update(ctx context.Context, user model.User) error {
 	return nil
 }
 
+func (ur *userRepository) UpdatePassword(ctx context.Context, id string, currentHash string, newHash string) (bool, error) {
+	executor := ur.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `UPDATE Users
+	SET password = ?
+	WHERE id = ? AND password = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, newHash, id, currentHash)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
 func (ur *userRepository) Delete(ctx context.Context, id string) error {
 	executor := ur.db
 	if tx := TxFromCtx(ctx); tx != nil {
@@ -6,8 +6,11 @@ import (
 
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 )
 
+const userCollectionColumns = `user_id, collection_id, quantity, first_obtained_at, last_obtained_at`
+
 type userCollectionRepository struct {
 	db SQLExecutor
 }
@@ -24,7 +27,7 @@ func (uc *userCollectionRepository) List(ctx context.Context, userID string) ([]
 		executor = tx
 	}
 
-	query := `SELECT *
+	query := `SELECT ` + userCollectionColumns + `
 	FROM User_Collections
 	WHERE user_id = ?
 	`
@@ -41,6 +44,9 @@ func (uc *userCollectionRepository) List(ctx context.Context, userID string) ([]
 		if err = rows.Scan(
 			&userCollection.UserID,
 			&userCollection.CollectionID,
+			&userCollection.Quantity,
+			&userCollection.FirstObtainedAt,
+			&userCollection.LastObtainedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -58,7 +64,7 @@ func (uc *userCollectionRepository) Get(ctx context.Context, userID, collectionI
 		executor = tx
 	}
 
-	query := `SELECT *
+	query := `SELECT ` + userCollectionColumns + `
 	FROM User_Collections
 	WHERE user_id = ? AND collection_id = ?
 	LIMIT 1
@@ -70,6 +76,9 @@ func (uc *userCollectionRepository) Get(ctx context.Context, userID, collectionI
 	if err := row.Scan(
 		&userCollection.UserID,
 		&userCollection.CollectionID,
+		&userCollection.Quantity,
+		&userCollection.FirstObtainedAt,
+		&userCollection.LastObtainedAt,
 	); err != nil {
 		return nil, err
 	}
@@ -83,9 +92,9 @@ func (uc *userCollectionRepository) Create(ctx context.Context, userCollection m
 	}
 
 	query := `INSERT INTO User_Collections (
-	user_id, collection_id
+	` + userCollectionColumns + `
 	)
-	VALUES (?, ?)
+	VALUES (?, ?, ?, ?, ?)
 	`
 
 	if _, err := executor.ExecContext(
@@ -93,28 +102,47 @@ func (uc *userCollectionRepository) Create(ctx context.Context, userCollection m
 		query,
 		userCollection.UserID,
 		userCollection.CollectionID,
+		userCollection.Quantity,
+		userCollection.FirstObtainedAt,
+		userCollection.LastObtainedAt,
 	); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (uc *userCollectionRepository) BatchCreate(ctx context.Context, userCollections []*model.UserCollection) error {
+// BatchIncrement 既に所持しているアイテムは所持数を加算し、初めて入手した日時は変更しない
+func (uc *userCollectionRepository) BatchIncrement(ctx context.Context, userCollections []*model.UserCollection) error {
+	if len(userCollections) == 0 {
+		return nil
+	}
+
 	executor := uc.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
 	}
 
-	query := `INSERT INTO User_Collections (user_id, collection_id) VALUES `
-	values := make([]interface{}, 0, len(userCollections)*2) //nolint:gomnd // 2 is the number of columns
+	query := `INSERT INTO User_Collections (` + userCollectionColumns + `) VALUES `
+	values := make([]interface{}, 0, len(userCollections)*5) //nolint:gomnd // 5 is the number of columns
 
 	for i, userCollection := range userCollections {
 		if i > 0 {
 			query += ", "
 		}
-		query += "(?, ?)"
-		values = append(values, userCollection.UserID, userCollection.CollectionID)
+		query += "(?, ?, ?, ?, ?)"
+		values = append(
+			values,
+			userCollection.UserID,
+			userCollection.CollectionID,
+			userCollection.Quantity,
+			userCollection.FirstObtainedAt,
+			userCollection.LastObtainedAt,
+		)
 	}
+	query += `
+	ON DUPLICATE KEY UPDATE
+	quantity = quantity + VALUES(quantity),
+	last_obtained_at = GREATEST(last_obtained_at, VALUES(last_obtained_at))`
 
 	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
 		return err
@@ -122,6 +150,38 @@ func (uc *userCollectionRepository) BatchCreate(ctx context.Context, userCollect
 	return nil
 }
 
+func (uc *userCollectionRepository) Decrement(ctx context.Context, userID, collectionID string, quantity int) error {
+	executor := uc.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// 所持数の確認と減算を1つの文で行い、同時実行されても所持数が0以下にならないようにする
+	query := `UPDATE User_Collections
+	SET quantity = quantity - ?
+	WHERE user_id = ? AND collection_id = ? AND quantity > ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, quantity, userID, collectionID, quantity)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		log.Info(
+			"Insufficient items",
+			log.Fstring("user_id", userID),
+			log.Fstring("collection_id", collectionID),
+			log.Fint("quantity", quantity),
+		)
+		return model.ErrInsufficientItems
+	}
+	return nil
+}
+
 func (uc *userCollectionRepository) Delete(ctx context.Context, userID, collectionID string) error {
 	executor := uc.db
 	if tx := TxFromCtx(ctx); tx != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -19,17 +20,24 @@ func Test_UserCollectionRepository(t *testing.T) {
 	collection2ID := uuid.New().String()
 	collection3ID := uuid.New().String()
 
+	obtainedAt := time.Now()
 	userCollection1, _ := model.NewUserCollection(
 		userID,
 		collection1ID,
+		1,
+		obtainedAt,
 	)
 	userCollection2, _ := model.NewUserCollection(
 		userID,
 		collection2ID,
+		1,
+		obtainedAt,
 	)
 	userCollection3, _ := model.NewUserCollection(
 		userID,
 		collection3ID,
+		2,
+		obtainedAt,
 	)
 
 	// serup
@@ -70,15 +78,37 @@ func Test_UserCollectionRepository(t *testing.T) {
 	err = repo.Create(ctx, *userCollection1)
 	ValidateErr(t, err, nil)
 
-	// BatchCreate
-	err = repo.BatchCreate(ctx, []*model.UserCollection{userCollection2, userCollection3})
+	// BatchIncrement
+	err = repo.BatchIncrement(ctx, []*model.UserCollection{userCollection2, userCollection3})
+	ValidateErr(t, err, nil)
+
+	// BatchIncrement with owned items
+	later, _ := model.NewUserCollection(userID, collection1ID, 3, obtainedAt.Add(time.Hour))
+	err = repo.BatchIncrement(ctx, []*model.UserCollection{later})
+	ValidateErr(t, err, nil)
+
+	// BatchIncrement with no items
+	err = repo.BatchIncrement(ctx, nil)
 	ValidateErr(t, err, nil)
 
 	// Get
 	getUserCollection, err := repo.Get(ctx, userID, collection1ID)
 	ValidateErr(t, err, nil)
-	if !reflect.DeepEqual(userCollection1, getUserCollection) {
-		t.Errorf("want: %v, got: %v", userCollection1, getUserCollection)
+	want := &model.UserCollection{
+		UserID:          userID,
+		CollectionID:    collection1ID,
+		Quantity:        4,
+		FirstObtainedAt: userCollection1.FirstObtainedAt,
+		LastObtainedAt:  later.LastObtainedAt,
+	}
+	if !reflect.DeepEqual(want, getUserCollection) {
+		t.Errorf("want: %v, got: %v", want, getUserCollection)
+	}
+
+	getUserCollection, err = repo.Get(ctx, userID, collection3ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(userCollection3, getUserCollection) {
+		t.Errorf("want: %v, got: %v", userCollection3, getUserCollection)
 	}
 
 	// List
@@ -88,6 +118,20 @@ func Test_UserCollectionRepository(t *testing.T) {
 		t.Errorf("want: %v, got: %v", 3, len(listUserCollections))
 	}
 
+	// Decrement
+	err = repo.Decrement(ctx, userID, collection3ID, 1)
+	ValidateErr(t, err, nil)
+	err = repo.Decrement(ctx, userID, collection3ID, 1)
+	ValidateErr(t, err, model.ErrInsufficientItems)
+	err = repo.Decrement(ctx, userID, uuid.New().String(), 1)
+	ValidateErr(t, err, model.ErrInsufficientItems)
+
+	getUserCollection, err = repo.Get(ctx, userID, collection3ID)
+	ValidateErr(t, err, nil)
+	if getUserCollection.Quantity != 1 {
+		t.Errorf("want: %v, got: %v", 1, getUserCollection.Quantity)
+	}
+
 	// Delete
 	err = repo.Delete(ctx, userID, collection1ID)
 	ValidateErr(t, err, nil)
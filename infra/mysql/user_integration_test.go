@@ -29,6 +29,13 @@ func Test_UserRepository(t *testing.T) {
 		t.Errorf("want: %v, got: %v", user, gotUser)
 	}
 
+	// GetByEmail
+	gotUser, err = repo.GetByEmail(ctx, "test@gmail.com")
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(user, gotUser) {
+		t.Errorf("want: %v, got: %v", user, gotUser)
+	}
+
 	// Test LockUserByEmail
 	exists, err := repo.LockUserByEmail(ctx, "test@gmail.com")
 	ValidateErr(t, err, nil)
@@ -38,6 +45,8 @@ func Test_UserRepository(t *testing.T) {
 
 	// Update
 	gotUser.Name = "updatedName"
+	gotUser.AvatarURL = "https://example.com/avatar.png"
+	gotUser.Locale = "ja"
 	err = repo.Update(ctx, *gotUser)
 	ValidateErr(t, err, nil)
 
@@ -47,6 +56,23 @@ func Test_UserRepository(t *testing.T) {
 		t.Errorf("want: %v, got: %v", gotUser, updatedUser)
 	}
 
+	// UpdatePassword: 読み込んだ時のハッシュのままの場合だけ置き換える
+	ok, err := repo.UpdatePassword(ctx, user.ID, updatedUser.Password, "rehashed")
+	ValidateErr(t, err, nil)
+	if !ok {
+		t.Errorf("want: %v, got: %v", true, ok)
+	}
+	ok, err = repo.UpdatePassword(ctx, user.ID, updatedUser.Password, "stale")
+	ValidateErr(t, err, nil)
+	if ok {
+		t.Errorf("want: %v, got: %v", false, ok)
+	}
+	rehashedUser, err := repo.Get(ctx, user.ID)
+	ValidateErr(t, err, nil)
+	if rehashedUser.Password != "rehashed" || rehashedUser.Name != updatedUser.Name {
+		t.Errorf("want: %v, got: %v", "rehashed", rehashedUser.Password)
+	}
+
 	// Delete
 	err = repo.Delete(ctx, user.ID)
 	ValidateErr(t, err, nil)
@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+type walletRepository struct {
+	db SQLExecutor
+}
+
+func NewWalletRepository(db *sql.DB) repository.WalletRepository {
+	return &walletRepository{
+		db: db,
+	}
+}
+
+func (wr *walletRepository) Get(ctx context.Context, userID string) (model.Wallet, error) {
+	executor := wr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `SELECT currency, balance
+	FROM User_Balances
+	WHERE user_id = ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wallet := model.NewWallet()
+	for rows.Next() {
+		var currency model.Currency
+		var balance int
+		if err = rows.Scan(&currency, &balance); err != nil {
+			return nil, err
+		}
+		wallet[currency] = balance
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+func (wr *walletRepository) Debit(ctx context.Context, userID string, currency model.Currency, amount int) error {
+	executor := wr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// 残高の確認と減算を1つの文で行い、同時実行されてもマイナスにならないようにする
+	query := `UPDATE User_Balances
+	SET balance = balance - ?
+	WHERE user_id = ? AND currency = ? AND balance >= ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, amount, userID, currency, amount)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		log.Info(
+			"Insufficient balance",
+			log.Fstring("user_id", userID),
+			log.Fstring("currency", string(currency)),
+			log.Fint("amount", amount),
+		)
+		return model.ErrInsufficientBalance
+	}
+	return nil
+}
+
+func (wr *walletRepository) Credit(ctx context.Context, userID string, currency model.Currency, amount int) error {
+	executor := wr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `INSERT INTO User_Balances (user_id, currency, balance)
+	VALUES (?, ?, ?)
+	ON DUPLICATE KEY UPDATE balance = balance + VALUES(balance)
+	`
+
+	if _, err := executor.ExecContext(ctx, query, userID, currency, amount); err != nil {
+		return err
+	}
+	return nil
+}
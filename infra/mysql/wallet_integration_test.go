@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_WalletRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWalletRepository(db)
+
+	user, err := model.NewUser(uuid.New().String()+"@gmail.com", "password")
+	ValidateErr(t, err, nil)
+	err = NewUserRepository(db).Create(ctx, *user)
+	ValidateErr(t, err, nil)
+
+	// Get: 残高の行がない通貨は0
+	wallet, err := repo.Get(ctx, user.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(wallet, model.NewWallet()) {
+		t.Errorf("want: %v, got: %v", model.NewWallet(), wallet)
+	}
+
+	// Credit
+	err = repo.Credit(ctx, user.ID, model.CurrencyCoin, 100)
+	ValidateErr(t, err, nil)
+	err = repo.Credit(ctx, user.ID, model.CurrencyCoin, 25)
+	ValidateErr(t, err, nil)
+	err = repo.Credit(ctx, user.ID, model.CurrencyShard, 30)
+	ValidateErr(t, err, nil)
+
+	// Debit
+	err = repo.Debit(ctx, user.ID, model.CurrencyCoin, 60)
+	ValidateErr(t, err, nil)
+	err = repo.Debit(ctx, user.ID, model.CurrencyCoin, 70)
+	ValidateErr(t, err, model.ErrInsufficientBalance)
+	// 残高の行がない通貨からは引き出せない
+	err = repo.Debit(ctx, user.ID, model.CurrencyGem, 1)
+	ValidateErr(t, err, model.ErrInsufficientBalance)
+
+	wallet, err = repo.Get(ctx, user.ID)
+	ValidateErr(t, err, nil)
+	want := model.Wallet{model.CurrencyCoin: 65, model.CurrencyGem: 0, model.CurrencyShard: 30, model.CurrencyEventToken: 0}
+	if !reflect.DeepEqual(wallet, want) {
+		t.Errorf("want: %v, got: %v", want, wallet)
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 )
 
+// collectionVersionKeySuffix キャッシュのVersionを保存するキーの接尾辞
+const collectionVersionKeySuffix = ":version"
+
 type collectionRepository struct {
 	client *redis.Client
 }
@@ -55,8 +58,13 @@ func (c *collectionRepository) Create(ctx context.Context, key string, collectio
 	return nil
 }
 
+// Delete 破棄とVersionの更新は同時に行い、古いVersionのまま新しい内容が読まれないようにする
 func (c *collectionRepository) Delete(ctx context.Context, key string) error {
-	if err := c.client.Del(ctx, key).Err(); err != nil {
+	if _, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.Incr(ctx, key+collectionVersionKeySuffix)
+		return nil
+	}); err != nil {
 		log.Error("Failed to delete cache", log.Ferror(err))
 		return err
 	}
@@ -64,6 +72,17 @@ func (c *collectionRepository) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (c *collectionRepository) Version(ctx context.Context, key string) (int64, error) {
+	version, err := c.client.Get(ctx, key+collectionVersionKeySuffix).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		log.Error("Failed to get cache version", log.Ferror(err))
+		return 0, err
+	}
+	return version, nil
+}
+
 func (c *collectionRepository) serialize(collections model.Collections) (string, error) {
 	data, err := json.Marshal(collections)
 	if err != nil {
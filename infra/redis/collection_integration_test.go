@@ -41,10 +41,19 @@ func Test_CollectionRepository(t *testing.T) {
 	}
 
 	// Delete
+	version, err := repo.Version(ctx, "collections")
+	ValidateErr(t, err, nil)
 	err = repo.Delete(ctx, "collections")
 	ValidateErr(t, err, nil)
 	_, err = repo.Get(ctx, "collections")
 	if err == nil {
 		t.Errorf("want: %v, got: %v", nil, err)
 	}
+
+	// Version
+	got, err := repo.Version(ctx, "collections")
+	ValidateErr(t, err, nil)
+	if got != version+1 {
+		t.Errorf("want: %v, got: %v", version+1, got)
+	}
 }
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// dropRatesRepository バナーIDをフィールドとするハッシュに保存し、キーの削除で全てのバナーの排出確率を破棄する
+type dropRatesRepository struct {
+	client *redis.Client
+}
+
+func NewDropRatesRepository(client *redis.Client) repository.DropRatesCacheRepository {
+	return &dropRatesRepository{
+		client: client,
+	}
+}
+
+func (d *dropRatesRepository) Get(ctx context.Context, key, bannerID string) (*model.DropRates, error) {
+	val, err := d.client.HGet(ctx, key, bannerID).Result()
+	if errors.Is(err, redis.Nil) {
+		log.Warn("Cache miss", log.Fstring("key", key), log.Fstring("bannerID", bannerID))
+		return nil, config.ErrCacheMiss
+	} else if err != nil {
+		log.Error("Failed to get cache", log.Ferror(err))
+		return nil, err
+	}
+	var rates model.DropRates
+	if err = json.Unmarshal([]byte(val), &rates); err != nil {
+		log.Error("Failed to deserialize drop rates", log.Ferror(err))
+		return nil, err
+	}
+	log.Info("Cache hit", log.Fstring("key", key), log.Fstring("bannerID", bannerID))
+	return &rates, nil
+}
+
+func (d *dropRatesRepository) Create(ctx context.Context, key string, rates model.DropRates) error {
+	data, err := json.Marshal(rates)
+	if err != nil {
+		log.Error("Failed to serialize drop rates", log.Ferror(err))
+		return err
+	}
+	if err = d.client.HSet(ctx, key, rates.BannerID, data).Err(); err != nil {
+		log.Error("Failed to set cache", log.Ferror(err))
+		return err
+	}
+	log.Info("Cache set successfully", log.Fstring("key", key), log.Fstring("bannerID", rates.BannerID))
+	return nil
+}
+
+func (d *dropRatesRepository) Delete(ctx context.Context, key string) error {
+	if err := d.client.Del(ctx, key).Err(); err != nil {
+		log.Error("Failed to delete cache", log.Ferror(err))
+		return err
+	}
+	log.Info("Cache deleted successfully", log.Fstring("key", key))
+	return nil
+}
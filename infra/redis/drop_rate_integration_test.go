@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_DropRatesRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewDropRatesRepository(client)
+
+	pool := model.Collections{
+		{ID: uuid.New().String(), Name: "collection1", Rarity: 1, Weight: 3},
+		{ID: uuid.New().String(), Name: "collection2", Rarity: 2, Weight: 1},
+	}
+	rates1 := model.NewDropRates(uuid.New().String(), pool)
+	rates2 := model.NewDropRates(uuid.New().String(), pool[:1])
+
+	// Create
+	err := repo.Create(ctx, model.DropRatesCacheKey, *rates1)
+	ValidateErr(t, err, nil)
+	err = repo.Create(ctx, model.DropRatesCacheKey, *rates2)
+	ValidateErr(t, err, nil)
+
+	// Get
+	got, err := repo.Get(ctx, model.DropRatesCacheKey, rates1.BannerID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(rates1, got) {
+		t.Errorf("want: %v, got: %v", rates1, got)
+	}
+
+	// Delete 全てのバナーの排出確率が破棄される
+	err = repo.Delete(ctx, model.DropRatesCacheKey)
+	ValidateErr(t, err, nil)
+	_, err = repo.Get(ctx, model.DropRatesCacheKey, rates1.BannerID)
+	ValidateErr(t, err, config.ErrCacheMiss)
+	_, err = repo.Get(ctx, model.DropRatesCacheKey, rates2.BannerID)
+	ValidateErr(t, err, config.ErrCacheMiss)
+}
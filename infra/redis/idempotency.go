@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+type idempotencyRepository struct {
+	client *redis.Client
+}
+
+func NewIdempotencyRepository(client *redis.Client) repository.IdempotencyRepository {
+	return &idempotencyRepository{
+		client: client,
+	}
+}
+
+func (ir *idempotencyRepository) Reserve(ctx context.Context, key string, record model.IdempotencyRecord, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Error("Failed to serialize idempotency record", log.Ferror(err))
+		return false, err
+	}
+	// SETNXで最初のリクエストだけが予約できるようにする
+	ok, err := ir.client.SetNX(ctx, idempotencyKeyPrefix+key, data, ttl).Result()
+	if err != nil {
+		log.Error("Failed to reserve idempotency key", log.Ferror(err))
+		return false, err
+	}
+	return ok, nil
+}
+
+func (ir *idempotencyRepository) Get(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	val, err := ir.client.Get(ctx, idempotencyKeyPrefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, config.ErrCacheMiss
+	} else if err != nil {
+		log.Error("Failed to get idempotency record", log.Ferror(err))
+		return nil, err
+	}
+	var record model.IdempotencyRecord
+	if err = json.Unmarshal([]byte(val), &record); err != nil {
+		log.Error("Failed to deserialize idempotency record", log.Ferror(err))
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (ir *idempotencyRepository) Update(ctx context.Context, key string, record model.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Error("Failed to serialize idempotency record", log.Ferror(err))
+		return err
+	}
+	if err = ir.client.Set(ctx, idempotencyKeyPrefix+key, data, ttl).Err(); err != nil {
+		log.Error("Failed to update idempotency record", log.Ferror(err))
+		return err
+	}
+	return nil
+}
+
+func (ir *idempotencyRepository) Delete(ctx context.Context, key string) error {
+	if err := ir.client.Del(ctx, idempotencyKeyPrefix+key).Err(); err != nil {
+		log.Error("Failed to delete idempotency record", log.Ferror(err))
+		return err
+	}
+	return nil
+}
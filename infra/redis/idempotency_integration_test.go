@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func Test_IdempotencyRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(client)
+
+	key := model.IdempotencyStorageKey(uuid.New().String(), uuid.New().String())
+	record := model.NewIdempotencyRecord(http.MethodPost, "/api/gacha/draw", []byte(`{"times":1}`))
+
+	// Reserve
+	ok, err := repo.Reserve(ctx, key, *record, time.Minute)
+	ValidateErr(t, err, nil)
+	if !ok {
+		t.Errorf("first reserve want: %v, got: %v", true, ok)
+	}
+	ok, err = repo.Reserve(ctx, key, *record, time.Minute)
+	ValidateErr(t, err, nil)
+	if ok {
+		t.Errorf("second reserve want: %v, got: %v", false, ok)
+	}
+
+	// Update
+	record.Complete(http.StatusOK, "application/json", []byte(`{"coin":1}`))
+	err = repo.Update(ctx, key, *record, time.Minute)
+	ValidateErr(t, err, nil)
+	got, err := repo.Get(ctx, key)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(got, record) {
+		t.Errorf("want: %v, got: %v", record, got)
+	}
+
+	// Delete
+	err = repo.Delete(ctx, key)
+	ValidateErr(t, err, nil)
+	if _, err = repo.Get(ctx, key); !errors.Is(err, config.ErrCacheMiss) {
+		t.Errorf("want: %v, got: %v", config.ErrCacheMiss, err)
+	}
+}
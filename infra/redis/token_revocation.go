@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const (
+	revokedTokenKeyPrefix = "revoked_token:"
+	revokedUserKeyPrefix  = "revoked_user:"
+)
+
+type tokenRevocationRepository struct {
+	client *redis.Client
+}
+
+func NewTokenRevocationRepository(client *redis.Client) repository.TokenRevocationRepository {
+	return &tokenRevocationRepository{
+		client: client,
+	}
+}
+
+func (trr *tokenRevocationRepository) Revoke(ctx context.Context, jti string, ttl time.Duration) error {
+	if err := trr.client.Set(ctx, revokedTokenKeyPrefix+jti, "1", ttl).Err(); err != nil {
+		log.Error("Failed to revoke token", log.Ferror(err))
+		return err
+	}
+	log.Info("Token revoked", log.Fstring("jti", jti))
+	return nil
+}
+
+// RevokeAllForUser revokedAt以前に発行されたユーザのトークンを全て失効させる
+// 同じ秒に再発行されたトークンを失効させないよう、ミリ秒単位で保存する
+func (trr *tokenRevocationRepository) RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time, ttl time.Duration) error {
+	if err := trr.client.Set(ctx, revokedUserKeyPrefix+userID, revokedAt.UnixMilli(), ttl).Err(); err != nil {
+		log.Error("Failed to revoke user tokens", log.Ferror(err))
+		return err
+	}
+	log.Info("All tokens revoked", log.Fstring("user_id", userID))
+	return nil
+}
+
+func (trr *tokenRevocationRepository) IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	vals, err := trr.client.MGet(ctx, revokedTokenKeyPrefix+jti, revokedUserKeyPrefix+userID).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Error("Failed to get token revocation", log.Ferror(err))
+		return false, err
+	}
+	if vals[0] != nil {
+		return true, nil
+	}
+	if vals[1] == nil {
+		return false, nil
+	}
+	revokedAtStr, ok := vals[1].(string)
+	if !ok {
+		return false, errors.New("unexpected revocation value")
+	}
+	revokedAt, err := strconv.ParseInt(revokedAtStr, 10, 64)
+	if err != nil {
+		log.Error("Failed to parse revocation time", log.Ferror(err))
+		return false, err
+	}
+	return issuedAt.UnixMilli() <= revokedAt, nil
+}
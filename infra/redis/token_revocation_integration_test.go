@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_TokenRevocationRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTokenRevocationRepository(client)
+
+	jti := uuid.New().String()
+	userID := uuid.New().String()
+	issuedAt := time.Now()
+
+	revoked, err := repo.IsRevoked(ctx, jti, userID, issuedAt)
+	ValidateErr(t, err, nil)
+	if revoked {
+		t.Errorf("want: %v, got: %v", false, revoked)
+	}
+
+	// Revoke
+	err = repo.Revoke(ctx, jti, time.Minute)
+	ValidateErr(t, err, nil)
+	revoked, err = repo.IsRevoked(ctx, jti, userID, issuedAt)
+	ValidateErr(t, err, nil)
+	if !revoked {
+		t.Errorf("want: %v, got: %v", true, revoked)
+	}
+
+	// RevokeAllForUser
+	otherJTI := uuid.New().String()
+	err = repo.RevokeAllForUser(ctx, userID, issuedAt, time.Minute)
+	ValidateErr(t, err, nil)
+	revoked, err = repo.IsRevoked(ctx, otherJTI, userID, issuedAt)
+	ValidateErr(t, err, nil)
+	if !revoked {
+		t.Errorf("want: %v, got: %v", true, revoked)
+	}
+	revoked, err = repo.IsRevoked(ctx, otherJTI, userID, issuedAt.Add(time.Second))
+	ValidateErr(t, err, nil)
+	if revoked {
+		t.Errorf("token issued after revocation: want: %v, got: %v", false, revoked)
+	}
+
+	// 同じ秒でも失効より後に発行されたトークンは有効
+	sameSecondUserID := uuid.New().String()
+	revokedAt := issuedAt.Truncate(time.Second)
+	err = repo.RevokeAllForUser(ctx, sameSecondUserID, revokedAt, time.Minute)
+	ValidateErr(t, err, nil)
+	revoked, err = repo.IsRevoked(ctx, otherJTI, sameSecondUserID, revokedAt.Add(500*time.Millisecond))
+	ValidateErr(t, err, nil)
+	if revoked {
+		t.Errorf("token issued in the same second after revocation: want: %v, got: %v", false, revoked)
+	}
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+const maxAuditNoteLength = 255
+
+type AdminHandler interface {
+	UpdateUserStats(w http.ResponseWriter, r *http.Request)
+	UpdateUserRole(w http.ResponseWriter, r *http.Request)
+	ListAuditLogs(w http.ResponseWriter, r *http.Request)
+	ListGachaDraws(w http.ResponseWriter, r *http.Request)
+}
+
+type adminHandler struct {
+	auc usecase.AdminUseCase
+}
+
+func NewAdminHandler(auc usecase.AdminUseCase) AdminHandler {
+	return &adminHandler{
+		auc: auc,
+	}
+}
+
+// UpdateUserStatsRequest 省略した項目は変更しない
+// CoinsはBalancesのcoinと同じ意味で、両方を指定することはできない
+type UpdateUserStatsRequest struct {
+	Coins     *int                   `json:"coins,omitempty"`
+	Balances  map[model.Currency]int `json:"balances,omitempty"`
+	HighScore *int                   `json:"high_score,omitempty"`
+	Note      string                 `json:"note"`
+}
+
+func (ah *adminHandler) UpdateUserStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := chi.URLParam(r, "user_id")
+	var requestBody UpdateUserStatsRequest
+	defer r.Body.Close()
+	if userID == "" || !ah.isValidUpdateUserStatsRequest(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ah.auc.UpdateUserStats(ctx, userID, usecase.UserStatsUpdate{
+		Balances:  requestBody.Balances,
+		HighScore: requestBody.HighScore,
+		Note:      requestBody.Note,
+	})
+	if errors.Is(err, config.ErrNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(UpdateUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Coins:     user.Wallet[model.CurrencyCoin],
+		HighScore: user.HighScore,
+		AvatarURL: user.AvatarURL,
+		Locale:    user.Locale,
+		Wallet:    user.Wallet,
+	}); err != nil {
+		log.Error("Failed to encode user to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (ah *adminHandler) isValidUpdateUserStatsRequest(body io.ReadCloser, requestBody *UpdateUserStatsRequest) bool {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(requestBody); err != nil {
+		log.Warn("Failed to decode request body", log.Ferror(err))
+		return false
+	}
+	if requestBody.Coins != nil {
+		if _, ok := requestBody.Balances[model.CurrencyCoin]; ok {
+			log.Warn("Invalid request body: both coins and balances.coin are specified")
+			return false
+		}
+		if requestBody.Balances == nil {
+			requestBody.Balances = map[model.Currency]int{}
+		}
+		requestBody.Balances[model.CurrencyCoin] = *requestBody.Coins
+	}
+	if len(requestBody.Balances) == 0 && requestBody.HighScore == nil {
+		log.Warn("Invalid request body: no fields to update")
+		return false
+	}
+	for currency, balance := range requestBody.Balances {
+		if !currency.IsValid() || balance < 0 {
+			log.Warn("Invalid request body: unknown currency or negative balance", log.Fstring("currency", string(currency)))
+			return false
+		}
+	}
+	if requestBody.HighScore != nil && *requestBody.HighScore < 0 {
+		log.Warn("Invalid request body: high_score is negative")
+		return false
+	}
+	// 監査のため変更理由は必須とする
+	if strings.TrimSpace(requestBody.Note) == "" || len(requestBody.Note) > maxAuditNoteLength {
+		log.Warn("Invalid request body: note is empty or too long")
+		return false
+	}
+	return true
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+	Note string `json:"note"`
+}
+
+type UpdateUserRoleResponse struct {
+	ID   string     `json:"id"`
+	Role model.Role `json:"role"`
+}
+
+func (ah *adminHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := chi.URLParam(r, "user_id")
+	var requestBody UpdateUserRoleRequest
+	defer r.Body.Close()
+	if userID == "" || !ah.isValidUpdateUserRoleRequest(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := ah.auc.UpdateUserRole(ctx, userID, model.Role(requestBody.Role), requestBody.Note)
+	switch {
+	case errors.Is(err, model.ErrOwnRoleChange):
+		http.Error(w, "Cannot change own role", http.StatusConflict)
+		return
+	case errors.Is(err, config.ErrNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(UpdateUserRoleResponse{
+		ID:   user.ID,
+		Role: user.Role,
+	}); err != nil {
+		log.Error("Failed to encode user to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (ah *adminHandler) isValidUpdateUserRoleRequest(body io.ReadCloser, requestBody *UpdateUserRoleRequest) bool {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(requestBody); err != nil {
+		log.Warn("Failed to decode request body", log.Ferror(err))
+		return false
+	}
+	if _, err := model.ParseRole(requestBody.Role); requestBody.Role == "" || err != nil {
+		log.Warn("Invalid request body: unknown role", log.Fstring("role", requestBody.Role))
+		return false
+	}
+	if strings.TrimSpace(requestBody.Note) == "" || len(requestBody.Note) > maxAuditNoteLength {
+		log.Warn("Invalid request body: note is empty or too long")
+		return false
+	}
+	return true
+}
+
+type ListAuditLogsResponse struct {
+	AuditLogs []*model.AdminAuditLog `json:"audit_logs"`
+}
+
+func (ah *adminHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	targetID := chi.URLParam(r, "user_id")
+	if targetID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	auditLogs, err := ah.auc.ListAuditLogs(ctx, targetID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if auditLogs == nil {
+		auditLogs = []*model.AdminAuditLog{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(ListAuditLogsResponse{AuditLogs: auditLogs}); err != nil {
+		log.Error("Failed to encode audit logs to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListGachaDraws from,toはRFC3339形式で指定する
+func (ah *adminHandler) ListGachaDraws(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	from, to, ok := ah.isValidGachaDrawPeriod(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	cursor, limit, ok := parseGachaDrawPageQuery(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	records, nextCursor, err := ah.auc.ListGachaDraws(ctx, from, to, cursor, limit)
+	if errors.Is(err, model.ErrInvalidCursor) || errors.Is(err, model.ErrInvalidPeriod) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeGachaDraws(w, records, nextCursor)
+}
+
+func (ah *adminHandler) isValidGachaDrawPeriod(r *http.Request) (time.Time, time.Time, bool) {
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		log.Warn("Invalid 'from' parameter", log.Ferror(err))
+		return time.Time{}, time.Time{}, false
+	}
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		log.Warn("Invalid 'to' parameter", log.Ferror(err))
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+	"github.com/tusmasoma/go-tech-dojo/usecase/mock"
+)
+
+// withURLParam chiのルーティングを通さずにURLパラメータを設定する
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestAdminHandler_UpdateUserStats(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	coins := 500
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockAdminUseCase,
+		)
+		in         func() *http.Request
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockAdminUseCase) {
+				// coinsはbalances.coinとして扱う
+				m.EXPECT().UpdateUserStats(gomock.Any(), userID, usecase.UserStatsUpdate{
+					Balances: map[model.Currency]int{model.CurrencyCoin: coins},
+					Note:     "compensation",
+				}).Return(
+					&usecase.UserDetail{
+						User:   &model.User{ID: userID, Name: "test", Email: "test@gmail.com"},
+						Wallet: model.Wallet{model.CurrencyCoin: coins},
+					}, nil,
+				)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/stats", strings.NewReader(`{"coins": 500, "note": "compensation"}`))
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "success: balances of several currencies",
+			setup: func(m *mock.MockAdminUseCase) {
+				m.EXPECT().UpdateUserStats(gomock.Any(), userID, usecase.UserStatsUpdate{
+					Balances: map[model.Currency]int{model.CurrencyGem: 100, model.CurrencyEventToken: 0},
+					Note:     "refund",
+				}).Return(
+					&usecase.UserDetail{User: &model.User{ID: userID}, Wallet: model.Wallet{model.CurrencyGem: 100}}, nil,
+				)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(
+					http.MethodPut,
+					"/api/admin/users/"+userID+"/stats",
+					strings.NewReader(`{"balances": {"gem": 100, "event_token": 0}, "note": "refund"}`),
+				)
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Fail: user not found",
+			setup: func(m *mock.MockAdminUseCase) {
+				m.EXPECT().UpdateUserStats(gomock.Any(), userID, gomock.Any()).Return(nil, config.ErrNotFound)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/stats", strings.NewReader(`{"coins": 500, "note": "compensation"}`))
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Fail: note is required",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/stats", strings.NewReader(`{"coins": 500}`))
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: negative coins",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/stats", strings.NewReader(`{"coins": -1, "note": "compensation"}`))
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: unknown currency",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/stats", strings.NewReader(`{"balances": {"diamond": 1}, "note": "compensation"}`))
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: both coins and balances.coin",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(
+					http.MethodPut,
+					"/api/admin/users/"+userID+"/stats",
+					strings.NewReader(`{"coins": 500, "balances": {"coin": 400}, "note": "compensation"}`),
+				)
+				return withURLParam(req, "user_id", userID)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			auc := mock.NewMockAdminUseCase(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(auc)
+			}
+
+			handler := NewAdminHandler(auc)
+			recorder := httptest.NewRecorder()
+			handler.UpdateUserStats(recorder, tt.in())
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_UpdateUserRole(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockAdminUseCase)
+		body       string
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockAdminUseCase) {
+				m.EXPECT().UpdateUserRole(gomock.Any(), userID, model.RoleOperator, "support team").Return(
+					&model.User{ID: userID, Role: model.RoleOperator}, nil,
+				)
+			},
+			body:       `{"role": "operator", "note": "support team"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Fail: own role",
+			setup: func(m *mock.MockAdminUseCase) {
+				m.EXPECT().UpdateUserRole(gomock.Any(), userID, model.RolePlayer, "step down").Return(nil, model.ErrOwnRoleChange)
+			},
+			body:       `{"role": "player", "note": "step down"}`,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Fail: unknown role",
+			body:       `{"role": "root", "note": "support team"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Fail: role is required",
+			body:       `{"note": "support team"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			auc := mock.NewMockAdminUseCase(ctrl)
+			if tt.setup != nil {
+				tt.setup(auc)
+			}
+
+			handler := NewAdminHandler(auc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+userID+"/role", strings.NewReader(tt.body))
+			handler.UpdateUserRole(recorder, withURLParam(req, "user_id", userID))
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ListGachaDraws(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockAdminUseCase)
+		query      string
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockAdminUseCase) {
+				m.EXPECT().ListGachaDraws(gomock.Any(), from, to, "cursor", 50).Return(
+					[]*model.GachaDrawRecord{{DrawID: "draw", UserID: "user", CreatedAt: from}},
+					"",
+					nil,
+				)
+			},
+			query:      "from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z&cursor=cursor&limit=50",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Fail: from is required",
+			query:      "to=2024-01-02T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Fail: invalid to",
+			query:      "from=2024-01-01T00:00:00Z&to=tomorrow",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: invalid period",
+			setup: func(m *mock.MockAdminUseCase) {
+				m.EXPECT().ListGachaDraws(gomock.Any(), to, from, "", 0).Return(nil, "", model.ErrInvalidPeriod)
+			},
+			query:      "from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			auc := mock.NewMockAdminUseCase(ctrl)
+			if tt.setup != nil {
+				tt.setup(auc)
+			}
+
+			handler := NewAdminHandler(auc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/admin/gacha/draws?"+tt.query, nil)
+			handler.ListGachaDraws(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+// jwksMaxAgeSeconds 鍵のローテーション時に新しい鍵が行き渡るまでの時間の目安
+const jwksMaxAgeSeconds = 300
+
+type AuthHandler interface {
+	Refresh(w http.ResponseWriter, r *http.Request)
+	Logout(w http.ResponseWriter, r *http.Request)
+	LogoutAll(w http.ResponseWriter, r *http.Request)
+	JWKS(w http.ResponseWriter, r *http.Request)
+}
+
+type authHandler struct {
+	auc usecase.AuthUseCase
+}
+
+func NewAuthHandler(auc usecase.AuthUseCase) AuthHandler {
+	return &authHandler{
+		auc: auc,
+	}
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (ah *authHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody RefreshRequest
+	defer r.Body.Close()
+	if !ah.isValidRefreshRequest(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	pair, err := ah.auc.Refresh(ctx, requestBody.RefreshToken)
+	if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		log.Error("Failed to refresh token", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeTokenResponse(w, pair)
+}
+
+func (ah *authHandler) isValidRefreshRequest(body io.ReadCloser, requestBody *RefreshRequest) bool {
+	if err := json.NewDecoder(body).Decode(requestBody); err != nil {
+		log.Error("Failed to decode request body: %v", err)
+		return false
+	}
+	if requestBody.RefreshToken == "" {
+		log.Warn("Invalid request body: refresh_token is empty")
+		return false
+	}
+	return true
+}
+
+func (ah *authHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// リフレッシュトークンの指定は任意
+	var requestBody LogoutRequest
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil && !errors.Is(err, io.EOF) {
+		log.Error("Failed to decode request body: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := ah.auc.Logout(ctx, requestBody.RefreshToken); err != nil {
+		log.Error("Failed to logout", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ah *authHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := ah.auc.LogoutAll(ctx); err != nil {
+		log.Error("Failed to logout all sessions", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// JWKS 他のサービスがアクセストークンを検証するための公開鍵を返す
+func (ah *authHandler) JWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAgeSeconds))
+	if err := json.NewEncoder(w).Encode(auth.CurrentKeySet().JWKS()); err != nil {
+		log.Error("Failed to encode JWKS to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeTokenResponse アクセストークンは従来通りAuthorizationヘッダにも設定する
+func writeTokenResponse(w http.ResponseWriter, pair *usecase.TokenPair) {
+	w.Header().Set("Authorization", "Bearer "+pair.AccessToken)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(TokenResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+	}); err != nil {
+		log.Error("Failed to encode response to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+	"github.com/tusmasoma/go-tech-dojo/usecase/mock"
+)
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockAuthUseCase,
+		)
+		in         func() *http.Request
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockAuthUseCase) {
+				m.EXPECT().Refresh(gomock.Any(), "refresh-token").Return(
+					&usecase.TokenPair{
+						AccessToken:  "access-token",
+						RefreshToken: "new-refresh-token",
+						ExpiresIn:    900,
+					},
+					nil,
+				)
+			},
+			in: func() *http.Request {
+				reqBody, _ := json.Marshal(RefreshRequest{RefreshToken: "refresh-token"})
+				req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBuffer(reqBody))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Fail: reused refresh token",
+			setup: func(m *mock.MockAuthUseCase) {
+				m.EXPECT().Refresh(gomock.Any(), "refresh-token").Return(nil, usecase.ErrRefreshTokenReused)
+			},
+			in: func() *http.Request {
+				reqBody, _ := json.Marshal(RefreshRequest{RefreshToken: "refresh-token"})
+				req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBuffer(reqBody))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Fail: invalid request",
+			in: func() *http.Request {
+				reqBody, _ := json.Marshal(RefreshRequest{})
+				req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewBuffer(reqBody))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			auc := mock.NewMockAuthUseCase(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(auc)
+			}
+
+			handler := NewAuthHandler(auc)
+			recorder := httptest.NewRecorder()
+			handler.Refresh(recorder, tt.in())
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				var response TokenResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || response.RefreshToken != "new-refresh-token" {
+					t.Fatalf("unexpected response body: %v, %v", response, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockAuthUseCase,
+		)
+		in         func() *http.Request
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockAuthUseCase) {
+				m.EXPECT().Logout(gomock.Any(), "refresh-token").Return(nil)
+			},
+			in: func() *http.Request {
+				reqBody, _ := json.Marshal(LogoutRequest{RefreshToken: "refresh-token"})
+				req, _ := http.NewRequest(http.MethodPost, "/api/auth/logout", bytes.NewBuffer(reqBody))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "success: without body",
+			setup: func(m *mock.MockAuthUseCase) {
+				m.EXPECT().Logout(gomock.Any(), "").Return(nil)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, "/api/auth/logout", http.NoBody)
+				return req
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Fail: invalid request",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, "/api/auth/logout", bytes.NewBufferString("{"))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			auc := mock.NewMockAuthUseCase(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(auc)
+			}
+
+			handler := NewAuthHandler(auc)
+			recorder := httptest.NewRecorder()
+			handler.Logout(recorder, tt.in())
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	auc := mock.NewMockAuthUseCase(ctrl)
+	auc.EXPECT().LogoutAll(gomock.Any()).Return(nil)
+
+	handler := NewAuthHandler(auc)
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/auth/logout/all", http.NoBody)
+	handler.LogoutAll(recorder, req)
+
+	if status := recorder.Code; status != http.StatusNoContent {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusNoContent)
+	}
+}
+
+func TestAuthHandler_JWKS(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	auc := mock.NewMockAuthUseCase(ctrl)
+
+	handler := NewAuthHandler(auc)
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", http.NoBody)
+	handler.JWKS(recorder, req)
+
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var response auth.JWKS
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	activeKeyID := auth.CurrentKeySet().ActiveKeyID()
+	found := false
+	for _, key := range response.Keys {
+		if key.Kid == activeKeyID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("JWKS does not contain the active key %v: %v", activeKeyID, response.Keys)
+	}
+}
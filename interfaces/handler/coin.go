@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+type CoinHandler interface {
+	ListCoinHistory(w http.ResponseWriter, r *http.Request)
+}
+
+type coinHandler struct {
+	cuc usecase.CoinUseCase
+}
+
+func NewCoinHandler(cuc usecase.CoinUseCase) CoinHandler {
+	return &coinHandler{
+		cuc: cuc,
+	}
+}
+
+type CoinTransactionResponse struct {
+	ID           string    `json:"id"`
+	Reason       string    `json:"reason"`
+	Delta        int       `json:"delta"`
+	BalanceAfter int       `json:"balance_after"`
+	ReferenceID  string    `json:"reference_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ListCoinHistoryResponse struct {
+	Transactions []CoinTransactionResponse `json:"transactions"`
+	NextCursor   string                    `json:"next_cursor"`
+}
+
+func (ch *coinHandler) ListCoinHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cursor, limit, ok := ch.isValidListCoinHistoryRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cts, nextCursor, err := ch.cuc.ListCoinHistory(ctx, cursor, limit)
+	if errors.Is(err, model.ErrInvalidCursor) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
+		log.Error("Failed to list coin history", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := ListCoinHistoryResponse{
+		Transactions: make([]CoinTransactionResponse, 0, len(cts)),
+		NextCursor:   nextCursor,
+	}
+	for _, ct := range cts {
+		response.Transactions = append(response.Transactions, CoinTransactionResponse{
+			ID:           ct.ID,
+			Reason:       string(ct.Reason),
+			Delta:        ct.Delta,
+			BalanceAfter: ct.BalanceAfter,
+			ReferenceID:  ct.ReferenceID,
+			CreatedAt:    ct.CreatedAt,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		log.Error("Failed to encode coin history to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (ch *coinHandler) isValidListCoinHistoryRequest(r *http.Request) (string, int, bool) {
+	cursor := r.URL.Query().Get("cursor")
+
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return cursor, 0, true
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		log.Warn("Invalid 'limit' parameter", log.Ferror(err))
+		return "", 0, false
+	}
+	if limit < 1 || limit > usecase.MaxCoinHistoryLimit {
+		log.Warn("Invalid 'limit' parameter", log.Fint("limit", limit))
+		return "", 0, false
+	}
+	return cursor, limit, true
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/usecase/mock"
+)
+
+func TestCoinHandler_ListCoinHistory(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCoinUseCase,
+		)
+		in             func() *http.Request
+		wantStatus     int
+		wantNextCursor string
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockCoinUseCase) {
+				m.EXPECT().ListCoinHistory(
+					gomock.Any(),
+					"cursor",
+					10,
+				).Return(
+					[]*model.CoinTransaction{
+						{
+							ID:           "id",
+							UserID:       "user",
+							Reason:       model.CoinReasonGameReward,
+							Delta:        100,
+							BalanceAfter: 100,
+							ReferenceID:  "score",
+							CreatedAt:    time.Now(),
+						},
+					},
+					"next",
+					nil,
+				)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/user/coins/history?cursor=cursor&limit=10", nil)
+				return req
+			},
+			wantStatus:     http.StatusOK,
+			wantNextCursor: "next",
+		},
+		{
+			name: "success: default limit",
+			setup: func(m *mock.MockCoinUseCase) {
+				m.EXPECT().ListCoinHistory(gomock.Any(), "", 0).Return(nil, "", nil)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/user/coins/history", nil)
+				return req
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Fail: invalid cursor",
+			setup: func(m *mock.MockCoinUseCase) {
+				m.EXPECT().ListCoinHistory(gomock.Any(), "invalid", 0).Return(nil, "", model.ErrInvalidCursor)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/user/coins/history?cursor=invalid", nil)
+				return req
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: invalid limit",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/user/coins/history?limit=1000", nil)
+				return req
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cuc := mock.NewMockCoinUseCase(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cuc)
+			}
+
+			handler := NewCoinHandler(cuc)
+			recorder := httptest.NewRecorder()
+			handler.ListCoinHistory(recorder, tt.in())
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var response ListCoinHistoryResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.NextCursor != tt.wantNextCursor {
+				t.Errorf("next_cursor = %v, want %v", response.NextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+// CollectionHandler 管理者がコレクションアイテムのマスタデータを管理する
+type CollectionHandler interface {
+	ListCollections(w http.ResponseWriter, r *http.Request)
+	GetCollection(w http.ResponseWriter, r *http.Request)
+	CreateCollection(w http.ResponseWriter, r *http.Request)
+	UpdateCollection(w http.ResponseWriter, r *http.Request)
+	DeleteCollection(w http.ResponseWriter, r *http.Request)
+	ImportCollections(w http.ResponseWriter, r *http.Request)
+}
+
+type collectionHandler struct {
+	cuc usecase.CollectionUseCase
+}
+
+func NewCollectionHandler(cuc usecase.CollectionUseCase) CollectionHandler {
+	return &collectionHandler{
+		cuc: cuc,
+	}
+}
+
+type CollectionRequest struct {
+	Name   string `json:"name"`
+	Rarity int    `json:"rarity"`
+	Weight int    `json:"weight"`
+}
+
+func (req CollectionRequest) toInput() usecase.CollectionInput {
+	return usecase.CollectionInput{
+		Name:   req.Name,
+		Rarity: req.Rarity,
+		Weight: req.Weight,
+	}
+}
+
+type ImportCollectionsRequest struct {
+	Collections []CollectionRequest `json:"collections"`
+}
+
+type ListCollectionsResponse struct {
+	Collections model.Collections `json:"collections"`
+}
+
+func (ch *collectionHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	collections, err := ch.cuc.ListCollections(ctx)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if collections == nil {
+		collections = model.Collections{}
+	}
+	writeJSON(w, http.StatusOK, ListCollectionsResponse{Collections: collections})
+}
+
+func (ch *collectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "collection_id")
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	collection, err := ch.cuc.GetCollection(ctx, id)
+	if errors.Is(err, config.ErrNotFound) {
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, collection)
+}
+
+func (ch *collectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody CollectionRequest
+	defer r.Body.Close()
+	if !decodeStrict(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	collection, err := ch.cuc.CreateCollection(ctx, requestBody.toInput())
+	var verr *model.ValidationError
+	if errors.As(err, &verr) {
+		writeValidationError(w, verr)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, collection)
+}
+
+func (ch *collectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "collection_id")
+	var requestBody CollectionRequest
+	defer r.Body.Close()
+	if id == "" || !decodeStrict(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	collection, err := ch.cuc.UpdateCollection(ctx, id, requestBody.toInput())
+	var verr *model.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		writeValidationError(w, verr)
+		return
+	case errors.Is(err, config.ErrNotFound):
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, collection)
+}
+
+func (ch *collectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := chi.URLParam(r, "collection_id")
+	if id == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := ch.cuc.DeleteCollection(ctx, id)
+	switch {
+	case errors.Is(err, config.ErrNotFound):
+		http.Error(w, "Collection not found", http.StatusNotFound)
+		return
+	case errors.Is(err, config.ErrReferenced):
+		http.Error(w, "Collection is owned by users or included in banners", http.StatusConflict)
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ch *collectionHandler) ImportCollections(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody ImportCollectionsRequest
+	defer r.Body.Close()
+	if !decodeStrict(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	inputs := make([]usecase.CollectionInput, 0, len(requestBody.Collections))
+	for _, req := range requestBody.Collections {
+		inputs = append(inputs, req.toInput())
+	}
+	collections, err := ch.cuc.ImportCollections(ctx, inputs)
+	var verr *model.ValidationError
+	if errors.As(err, &verr) {
+		writeValidationError(w, verr)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusCreated, ListCollectionsResponse{Collections: collections})
+}
+
+// decodeStrict 未知の項目を含むリクエストは受け付けない
+func decodeStrict(body io.Reader, v interface{}) bool {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		log.Warn("Failed to decode request body", log.Ferror(err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Failed to encode response to JSON", log.Ferror(err))
+	}
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+	"github.com/tusmasoma/go-tech-dojo/usecase/mock"
+)
+
+func TestCollectionHandler_CreateCollection(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockCollectionUseCase)
+		body       string
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockCollectionUseCase) {
+				m.EXPECT().CreateCollection(gomock.Any(), usecase.CollectionInput{Name: "collection", Rarity: 3, Weight: 10}).
+					Return(&model.Collection{ID: uuid.New().String(), Name: "collection", Rarity: 3, Weight: 10}, nil)
+			},
+			body:       `{"name":"collection","rarity":3,"weight":10}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Fail: validation error",
+			setup: func(m *mock.MockCollectionUseCase) {
+				m.EXPECT().CreateCollection(gomock.Any(), gomock.Any()).
+					Return(nil, &model.ValidationError{Fields: []model.FieldError{{Field: "collection", Message: "rarity is invalid"}}})
+			},
+			body:       `{"name":"collection","rarity":6,"weight":10}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Fail: unknown field",
+			body:       `{"name":"collection","rarity":3,"weight":10,"id":"x"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cuc := mock.NewMockCollectionUseCase(ctrl)
+			if tt.setup != nil {
+				tt.setup(cuc)
+			}
+
+			handler := NewCollectionHandler(cuc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/admin/collections", strings.NewReader(tt.body))
+			handler.CreateCollection(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCollectionHandler_UpdateCollection(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New().String()
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockCollectionUseCase)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockCollectionUseCase) {
+				m.EXPECT().UpdateCollection(gomock.Any(), id, usecase.CollectionInput{Name: "collection", Rarity: 3, Weight: 10}).
+					Return(&model.Collection{ID: id, Name: "collection", Rarity: 3, Weight: 10}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Fail: not found",
+			setup: func(m *mock.MockCollectionUseCase) {
+				m.EXPECT().UpdateCollection(gomock.Any(), id, gomock.Any()).Return(nil, config.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cuc := mock.NewMockCollectionUseCase(ctrl)
+			tt.setup(cuc)
+
+			handler := NewCollectionHandler(cuc)
+			recorder := httptest.NewRecorder()
+			reqBody, _ := json.Marshal(CollectionRequest{Name: "collection", Rarity: 3, Weight: 10})
+			req, _ := http.NewRequest(http.MethodPut, "/api/admin/collections/"+id, bytes.NewBuffer(reqBody))
+			handler.UpdateCollection(recorder, withURLParam(req, "collection_id", id))
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCollectionHandler_DeleteCollection(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New().String()
+
+	patterns := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "success", err: nil, wantStatus: http.StatusNoContent},
+		{name: "Fail: not found", err: config.ErrNotFound, wantStatus: http.StatusNotFound},
+		{name: "Fail: owned by users", err: config.ErrReferenced, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cuc := mock.NewMockCollectionUseCase(ctrl)
+			cuc.EXPECT().DeleteCollection(gomock.Any(), id).Return(tt.err)
+
+			handler := NewCollectionHandler(cuc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, "/api/admin/collections/"+id, nil)
+			handler.DeleteCollection(recorder, withURLParam(req, "collection_id", id))
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCollectionHandler_ImportCollections(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cuc := mock.NewMockCollectionUseCase(ctrl)
+	cuc.EXPECT().ImportCollections(gomock.Any(), []usecase.CollectionInput{
+		{Name: "collection1", Rarity: 1, Weight: 10},
+		{Name: "collection2", Rarity: 2, Weight: 5},
+	}).Return(model.Collections{
+		{ID: uuid.New().String(), Name: "collection1", Rarity: 1, Weight: 10},
+		{ID: uuid.New().String(), Name: "collection2", Rarity: 2, Weight: 5},
+	}, nil)
+
+	handler := NewCollectionHandler(cuc)
+	recorder := httptest.NewRecorder()
+	body := `{"collections":[{"name":"collection1","rarity":1,"weight":10},{"name":"collection2","rarity":2,"weight":5}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/collections/import", strings.NewReader(body))
+	handler.ImportCollections(recorder, req)
+
+	if status := recorder.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	var got ListCollectionsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if len(got.Collections) != 2 {
+		t.Errorf("handler returned %v collections, want %v", len(got.Collections), 2)
+	}
+}
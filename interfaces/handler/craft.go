@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+// CraftHandler 重複したアイテムの分解と、欠片を使ったアイテムの作成
+type CraftHandler interface {
+	Dismantle(w http.ResponseWriter, r *http.Request)
+	Craft(w http.ResponseWriter, r *http.Request)
+}
+
+type craftHandler struct {
+	cuc usecase.CraftUseCase
+}
+
+func NewCraftHandler(cuc usecase.CraftUseCase) CraftHandler {
+	return &craftHandler{
+		cuc: cuc,
+	}
+}
+
+type DismantleRequest struct {
+	CollectionID string `json:"collection_id"`
+	Quantity     int    `json:"quantity"`
+}
+
+type CraftRequest struct {
+	CollectionID string `json:"collection_id"`
+}
+
+type CraftResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Rarity int    `json:"rarity"`
+	// Quantity 分解・作成後の所持数
+	Quantity int `json:"quantity"`
+	// Shards 分解・作成後の欠片の残高
+	Shards int `json:"shards"`
+}
+
+func (ch *craftHandler) Dismantle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody DismantleRequest
+	defer r.Body.Close()
+	if !decodeStrict(r.Body, &requestBody) || requestBody.CollectionID == "" || requestBody.Quantity < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := ch.cuc.Dismantle(ctx, requestBody.CollectionID, requestBody.Quantity)
+	if !ch.handleError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, convertToCraftResponse(result))
+}
+
+func (ch *craftHandler) Craft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody CraftRequest
+	defer r.Body.Close()
+	if !decodeStrict(r.Body, &requestBody) || requestBody.CollectionID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := ch.cuc.Craft(ctx, requestBody.CollectionID)
+	if !ch.handleError(w, err) {
+		return
+	}
+	writeJSON(w, http.StatusOK, convertToCraftResponse(result))
+}
+
+// handleError エラーに応じたステータスを書き込み、処理を続けてよい場合はtrueを返す
+func (ch *craftHandler) handleError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, model.ErrInvalidQuantity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, config.ErrNotFound):
+		http.Error(w, "Collection not found", http.StatusNotFound)
+	case errors.Is(err, model.ErrInsufficientItems):
+		http.Error(w, "Insufficient items", http.StatusConflict)
+	case errors.Is(err, model.ErrNotCraftable):
+		http.Error(w, "Collection is not craftable", http.StatusConflict)
+	case errors.Is(err, model.ErrInsufficientBalance):
+		http.Error(w, "Insufficient shards", http.StatusConflict)
+	default:
+		log.Error("Failed to craft", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	return false
+}
+
+func convertToCraftResponse(result *usecase.CraftResult) CraftResponse {
+	return CraftResponse{
+		ID:       result.Collection.ID,
+		Name:     result.Collection.Name,
+		Rarity:   result.Collection.Rarity,
+		Quantity: result.Quantity,
+		Shards:   result.Shards,
+	}
+}
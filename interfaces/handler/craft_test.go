@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/usecase"
+	"github.com/tusmasoma/go-tech-dojo/usecase/mock"
+)
+
+func TestCraftHandler_Dismantle(t *testing.T) {
+	t.Parallel()
+
+	collection := &model.Collection{ID: uuid.New().String(), Name: "collection", Rarity: 3, Weight: 10}
+	patterns := []struct {
+		name         string
+		setup        func(m *mock.MockCraftUseCase)
+		body         string
+		wantStatus   int
+		wantResponse *CraftResponse
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockCraftUseCase) {
+				m.EXPECT().Dismantle(gomock.Any(), collection.ID, 2).Return(
+					&usecase.CraftResult{Collection: collection, Quantity: 1, Shards: 60},
+					nil,
+				)
+			},
+			body:       `{"collection_id":"` + collection.ID + `","quantity":2}`,
+			wantStatus: http.StatusOK,
+			wantResponse: &CraftResponse{
+				ID:       collection.ID,
+				Name:     "collection",
+				Rarity:   3,
+				Quantity: 1,
+				Shards:   60,
+			},
+		},
+		{
+			name:       "Fail: quantity is less than 1",
+			body:       `{"collection_id":"` + collection.ID + `","quantity":0}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: collection not found",
+			setup: func(m *mock.MockCraftUseCase) {
+				m.EXPECT().Dismantle(gomock.Any(), collection.ID, 1).Return(nil, config.ErrNotFound)
+			},
+			body:       `{"collection_id":"` + collection.ID + `","quantity":1}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Fail: insufficient items",
+			setup: func(m *mock.MockCraftUseCase) {
+				m.EXPECT().Dismantle(gomock.Any(), collection.ID, 1).Return(nil, model.ErrInsufficientItems)
+			},
+			body:       `{"collection_id":"` + collection.ID + `","quantity":1}`,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cuc := mock.NewMockCraftUseCase(ctrl)
+			if tt.setup != nil {
+				tt.setup(cuc)
+			}
+
+			handler := NewCraftHandler(cuc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/collection/dismantle", strings.NewReader(tt.body))
+			handler.Dismantle(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantResponse != nil {
+				var response CraftResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response != *tt.wantResponse {
+					t.Errorf("handler returned unexpected body: got %+v want %+v", response, *tt.wantResponse)
+				}
+			}
+		})
+	}
+}
+
+func TestCraftHandler_Craft(t *testing.T) {
+	t.Parallel()
+
+	collection := &model.Collection{ID: uuid.New().String(), Name: "collection", Rarity: 2, Weight: 10}
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockCraftUseCase)
+		body       string
+		wantStatus int
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockCraftUseCase) {
+				m.EXPECT().Craft(gomock.Any(), collection.ID).Return(
+					&usecase.CraftResult{Collection: collection, Quantity: 1, Shards: 0},
+					nil,
+				)
+			},
+			body:       `{"collection_id":"` + collection.ID + `"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Fail: collection_id is required",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: not craftable",
+			setup: func(m *mock.MockCraftUseCase) {
+				m.EXPECT().Craft(gomock.Any(), collection.ID).Return(nil, model.ErrNotCraftable)
+			},
+			body:       `{"collection_id":"` + collection.ID + `"}`,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "Fail: insufficient balance",
+			setup: func(m *mock.MockCraftUseCase) {
+				m.EXPECT().Craft(gomock.Any(), collection.ID).Return(nil, model.ErrInsufficientBalance)
+			},
+			body:       `{"collection_id":"` + collection.ID + `"}`,
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cuc := mock.NewMockCraftUseCase(ctrl)
+			if tt.setup != nil {
+				tt.setup(cuc)
+			}
+
+			handler := NewCraftHandler(cuc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/collection/craft", strings.NewReader(tt.body))
+			handler.Craft(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
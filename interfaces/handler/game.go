@@ -2,16 +2,25 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 	"github.com/tusmasoma/go-tech-dojo/usecase"
 )
 
 type GameHandler interface {
+	StartGame(w http.ResponseWriter, r *http.Request)
 	FinishGame(w http.ResponseWriter, r *http.Request)
+	ListBanners(w http.ResponseWriter, r *http.Request)
+	GetDropRates(w http.ResponseWriter, r *http.Request)
 	DrawGacha(w http.ResponseWriter, r *http.Request)
+	ListGachaHistory(w http.ResponseWriter, r *http.Request)
 }
 
 type gameHandler struct {
@@ -24,8 +33,38 @@ func NewGameHandler(guc usecase.GameUseCase) GameHandler {
 	}
 }
 
+type StartGameResponse struct {
+	SessionID string    `json:"session_id"`
+	Seed      int64     `json:"seed"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+func (gh *gameHandler) StartGame(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	start, err := gh.guc.StartGame(ctx)
+	if err != nil {
+		log.Error("Failed to start game", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(StartGameResponse{
+		SessionID: start.SessionToken,
+		Seed:      start.Seed,
+		StartedAt: start.StartedAt,
+	}); err != nil {
+		log.Error("Failed to encode response to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
 type FinishGameRequest struct {
-	Score int `json:"score"`
+	// SessionID StartGameで発行されたセッションのトークン
+	SessionID string `json:"session_id"`
+	Score     int    `json:"score"`
 }
 
 type FinishGameResponse struct {
@@ -42,8 +81,20 @@ func (gh *gameHandler) FinishGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	coin, err := gh.guc.FinishGame(ctx, requestBody.Score)
-	if err != nil {
+	coin, err := gh.guc.FinishGame(ctx, requestBody.SessionID, requestBody.Score)
+	switch {
+	case errors.Is(err, model.ErrGameSessionInvalid):
+		http.Error(w, "Invalid game session", http.StatusBadRequest)
+		return
+	case errors.Is(err, model.ErrGameSessionFinished):
+		http.Error(w, "Game session already finished", http.StatusConflict)
+		return
+	case errors.Is(err, model.ErrGameTooShort),
+		errors.Is(err, model.ErrGameSessionExpired),
+		errors.Is(err, model.ErrImplausibleScore):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
 		log.Error("Failed to finish game", log.Ferror(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -64,15 +115,153 @@ func (gh *gameHandler) isValidFinishGameRequest(body io.ReadCloser, requestBody
 		log.Error("Failed to decode request body: %v", err)
 		return false
 	}
-	if requestBody.Score < 0 {
+	if requestBody.SessionID == "" || requestBody.Score < 0 {
 		log.Warn("Invalid request body: %v", requestBody)
 		return false
 	}
 	return true
 }
 
+type BannerItemResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Rarity int    `json:"rarity"`
+	RateUp bool   `json:"rate_up"`
+}
+
+// BannerGuaranteeResponse Drawsが0の場合は保証なし
+type BannerGuaranteeResponse struct {
+	Draws     int `json:"draws"`
+	MinRarity int `json:"min_rarity"`
+}
+
+type BannerResponse struct {
+	ID        string                  `json:"id"`
+	Name      string                  `json:"name"`
+	Cost      int                     `json:"cost"`
+	Currency  model.Currency          `json:"currency"`
+	StartAt   time.Time               `json:"start_at"`
+	EndAt     time.Time               `json:"end_at"`
+	Items     []BannerItemResponse    `json:"items"`
+	Guarantee BannerGuaranteeResponse `json:"guarantee"`
+}
+
+type ListBannersResponse struct {
+	Banners []BannerResponse `json:"banners"`
+}
+
+func (gh *gameHandler) ListBanners(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	banners, err := gh.guc.ListBanners(ctx)
+	if err != nil {
+		log.Error("Failed to list banners", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := ListBannersResponse{Banners: make([]BannerResponse, 0, len(banners))}
+	for _, banner := range banners {
+		items := make([]BannerItemResponse, 0, len(banner.Pool))
+		for _, item := range banner.Pool {
+			items = append(items, BannerItemResponse{
+				ID:     item.ID,
+				Name:   item.Name,
+				Rarity: item.Rarity,
+				RateUp: banner.IsRateUp(item.ID),
+			})
+		}
+		response.Banners = append(response.Banners, BannerResponse{
+			ID:       banner.ID,
+			Name:     banner.Name,
+			Cost:     banner.Cost,
+			Currency: banner.Currency,
+			StartAt:  banner.StartAt,
+			EndAt:    banner.EndAt,
+			Items:    items,
+			Guarantee: BannerGuaranteeResponse{
+				Draws:     banner.Guarantee.Draws,
+				MinRarity: banner.Guarantee.MinRarity,
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		log.Error("Failed to encode response to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type ItemDropRateResponse struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Rarity int     `json:"rarity"`
+	Rate   float64 `json:"rate"`
+}
+
+type RarityDropRateResponse struct {
+	Rarity int     `json:"rarity"`
+	Rate   float64 `json:"rate"`
+}
+
+type GetDropRatesResponse struct {
+	BannerID string                   `json:"banner_id"`
+	Items    []ItemDropRateResponse   `json:"items"`
+	Rarities []RarityDropRateResponse `json:"rarities"`
+}
+
+func (gh *gameHandler) GetDropRates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bannerID := r.URL.Query().Get("banner_id")
+	if bannerID == "" {
+		http.Error(w, "banner_id is required", http.StatusBadRequest)
+		return
+	}
+
+	rates, err := gh.guc.GetDropRates(ctx, bannerID)
+	if errors.Is(err, config.ErrNotFound) {
+		http.Error(w, "Banner not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Error("Failed to get drop rates", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	response := GetDropRatesResponse{
+		BannerID: rates.BannerID,
+		Items:    make([]ItemDropRateResponse, 0, len(rates.Items)),
+		Rarities: make([]RarityDropRateResponse, 0, len(rates.Rarities)),
+	}
+	for _, item := range rates.Items {
+		response.Items = append(response.Items, ItemDropRateResponse{
+			ID:     item.CollectionID,
+			Name:   item.Name,
+			Rarity: item.Rarity,
+			Rate:   item.Rate,
+		})
+	}
+	for _, rarity := range rates.Rarities {
+		response.Rarities = append(response.Rarities, RarityDropRateResponse{
+			Rarity: rarity.Rarity,
+			Rate:   rarity.Rate,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		log.Error("Failed to encode response to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
 type DrawGachaRequest struct {
-	Times int `json:"times"`
+	BannerID string `json:"banner_id"`
+	Times    int    `json:"times"`
 }
 
 type DrawGachaResponse struct {
@@ -82,6 +271,10 @@ type DrawGachaResponse struct {
 		Rarity int    `json:"rarity"`
 		IsNew  bool   `json:"is_new"`
 	} `json:"results"`
+	// PityCount 最後に最高レアリティが排出されてからの抽選回数
+	PityCount int `json:"pity_count"`
+	// Payments 支払いに使った通貨ごとの額。消費した順に並ぶ
+	Payments []model.Payment `json:"payments"`
 }
 
 func (gh *gameHandler) DrawGacha(w http.ResponseWriter, r *http.Request) {
@@ -94,14 +287,23 @@ func (gh *gameHandler) DrawGacha(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gachaResults, err := gh.guc.DrawGacha(ctx, requestBody.Times)
-	if err != nil {
+	gachaDraw, err := gh.guc.DrawGacha(ctx, requestBody.BannerID, requestBody.Times)
+	if errors.Is(err, config.ErrNotFound) {
+		http.Error(w, "Banner not found", http.StatusNotFound)
+		return
+	} else if errors.Is(err, model.ErrBannerInactive) {
+		http.Error(w, "Banner is not active", http.StatusConflict)
+		return
+	} else if errors.Is(err, model.ErrInsufficientBalance) {
+		http.Error(w, "Insufficient balance", http.StatusConflict)
+		return
+	} else if err != nil {
 		log.Error("Failed to draw gacha", log.Ferror(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	response := gh.convertToDrawGachaResponse(gachaResults)
+	response := gh.convertToDrawGachaResponse(gachaDraw)
 	w.Header().Set("Content-Type", "application/json")
 	if err = json.NewEncoder(w).Encode(response); err != nil {
 		log.Error("Failed to encode response to JSON", log.Ferror(err))
@@ -115,21 +317,21 @@ func (gh *gameHandler) isValidDrawGachaRequest(body io.ReadCloser, requestBody *
 		log.Error("Failed to decode request body: %v", err)
 		return false
 	}
-	if requestBody.Times < 0 || requestBody.Times > 10 {
+	if requestBody.BannerID == "" || requestBody.Times < 0 || requestBody.Times > 10 {
 		log.Warn("Invalid request body: %v", requestBody)
 		return false
 	}
 	return true
 }
 
-func (gh *gameHandler) convertToDrawGachaResponse(gachaResults []*usecase.GachaResult) DrawGachaResponse {
+func (gh *gameHandler) convertToDrawGachaResponse(gachaDraw *usecase.GachaDraw) DrawGachaResponse {
 	var results []struct {
 		ID     string `json:"id"`
 		Name   string `json:"name"`
 		Rarity int    `json:"rarity"`
 		IsNew  bool   `json:"is_new"`
 	}
-	for _, item := range gachaResults {
+	for _, item := range gachaDraw.Results {
 		results = append(results, struct {
 			ID     string `json:"id"`
 			Name   string `json:"name"`
@@ -143,6 +345,66 @@ func (gh *gameHandler) convertToDrawGachaResponse(gachaResults []*usecase.GachaR
 		})
 	}
 	return DrawGachaResponse{
-		Results: results,
+		Results:   results,
+		PityCount: gachaDraw.PityCount,
+		Payments:  gachaDraw.Payments,
+	}
+}
+
+type ListGachaDrawsResponse struct {
+	Draws      []*model.GachaDrawRecord `json:"draws"`
+	NextCursor string                   `json:"next_cursor"`
+}
+
+func (gh *gameHandler) ListGachaHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cursor, limit, ok := parseGachaDrawPageQuery(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	records, nextCursor, err := gh.guc.ListGachaHistory(ctx, cursor, limit)
+	if errors.Is(err, model.ErrInvalidCursor) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
+		log.Error("Failed to list gacha history", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeGachaDraws(w, records, nextCursor)
+}
+
+// parseGachaDrawPageQuery クエリのcursorとlimitを取り出す。limitを省略した場合は0を返す
+func parseGachaDrawPageQuery(r *http.Request) (string, int, bool) {
+	cursor := r.URL.Query().Get("cursor")
+
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		return cursor, 0, true
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		log.Warn("Invalid 'limit' parameter", log.Ferror(err))
+		return "", 0, false
+	}
+	if limit < 1 || limit > usecase.MaxGachaHistoryLimit {
+		log.Warn("Invalid 'limit' parameter", log.Fint("limit", limit))
+		return "", 0, false
+	}
+	return cursor, limit, true
+}
+
+func writeGachaDraws(w http.ResponseWriter, records []*model.GachaDrawRecord, nextCursor string) {
+	if records == nil {
+		records = []*model.GachaDrawRecord{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ListGachaDrawsResponse{Draws: records, NextCursor: nextCursor}); err != nil {
+		log.Error("Failed to encode gacha draws to JSON", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 }
@@ -3,22 +3,98 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 
+	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/usecase"
 	"github.com/tusmasoma/go-tech-dojo/usecase/mock"
 )
 
+func TestGameHandler_StartGame(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	patterns := []struct {
+		name         string
+		setup        func(m *mock.MockGameUseCase)
+		wantStatus   int
+		wantResponse StartGameResponse
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().StartGame(gomock.Any()).Return(&usecase.GameStart{
+					SessionToken: "token",
+					Seed:         42,
+					StartedAt:    startedAt,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantResponse: StartGameResponse{
+				SessionID: "token",
+				Seed:      42,
+				StartedAt: startedAt,
+			},
+		},
+		{
+			name: "Fail: internal error",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().StartGame(gomock.Any()).Return(nil, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			guc := mock.NewMockGameUseCase(ctrl)
+			tt.setup(guc)
+
+			handler := NewGameHandler(guc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/api/game/start", nil)
+			handler.StartGame(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got StartGameResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			if got.SessionID != tt.wantResponse.SessionID || got.Seed != tt.wantResponse.Seed || !got.StartedAt.Equal(tt.wantResponse.StartedAt) {
+				t.Errorf("handler returned unexpected body: got %v want %v", got, tt.wantResponse)
+			}
+		})
+	}
+}
+
 func TestGameHandler_FinishGame(t *testing.T) {
 	t.Parallel()
 
+	newRequest := func(body FinishGameRequest) *http.Request {
+		reqBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, "/api/game/finish", bytes.NewBuffer(reqBody))
+		return req
+	}
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -30,26 +106,57 @@ func TestGameHandler_FinishGame(t *testing.T) {
 		{
 			name: "success",
 			setup: func(m *mock.MockGameUseCase) {
-				m.EXPECT().FinishGame(gomock.Any(), 100).Return(300, nil)
+				m.EXPECT().FinishGame(gomock.Any(), "token", 100).Return(300, nil)
 			},
 			in: func() *http.Request {
-				gameFinishReq := FinishGameRequest{Score: 100}
-				reqBody, _ := json.Marshal(gameFinishReq)
-				req, _ := http.NewRequest(http.MethodPut, "/api/game/finish", bytes.NewBuffer(reqBody))
-				return req
+				return newRequest(FinishGameRequest{SessionID: "token", Score: 100})
 			},
 			wantStatus: http.StatusOK,
 		},
 		{
 			name: "Fail: Invalid request",
 			in: func() *http.Request {
-				gameFinishReq := FinishGameRequest{Score: -100}
-				reqBody, _ := json.Marshal(gameFinishReq)
-				req, _ := http.NewRequest(http.MethodPut, "/api/game/finish", bytes.NewBuffer(reqBody))
-				return req
+				return newRequest(FinishGameRequest{SessionID: "token", Score: -100})
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: session_id is required",
+			in: func() *http.Request {
+				return newRequest(FinishGameRequest{Score: 100})
 			},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name: "Fail: invalid session",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().FinishGame(gomock.Any(), "token", 100).Return(0, model.ErrGameSessionInvalid)
+			},
+			in: func() *http.Request {
+				return newRequest(FinishGameRequest{SessionID: "token", Score: 100})
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: session already finished",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().FinishGame(gomock.Any(), "token", 100).Return(0, model.ErrGameSessionFinished)
+			},
+			in: func() *http.Request {
+				return newRequest(FinishGameRequest{SessionID: "token", Score: 100})
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "Fail: implausible score",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().FinishGame(gomock.Any(), "token", 1000000).Return(0, model.ErrImplausibleScore)
+			},
+			in: func() *http.Request {
+				return newRequest(FinishGameRequest{SessionID: "token", Score: 1000000})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -79,6 +186,7 @@ func TestGameHandler_DrawGacha(t *testing.T) {
 	t.Parallel()
 
 	collectionID := uuid.New().String()
+	bannerID := uuid.New().String()
 
 	patterns := []struct {
 		name  string
@@ -94,25 +202,30 @@ func TestGameHandler_DrawGacha(t *testing.T) {
 			setup: func(m *mock.MockGameUseCase) {
 				m.EXPECT().DrawGacha(
 					gomock.Any(),
+					bannerID,
 					1,
 				).Return(
-					[]*usecase.GachaResult{
-						{
-							Collection: &model.Collection{
-								ID:     collectionID,
-								Name:   "collection1",
-								Rarity: 1,
-								Weight: 10,
+					&usecase.GachaDraw{
+						Results: []*usecase.GachaResult{
+							{
+								Collection: &model.Collection{
+									ID:     collectionID,
+									Name:   "collection1",
+									Rarity: 1,
+									Weight: 10,
+								},
+								Has: false,
 							},
-							Has: false,
 						},
+						PityCount: 3,
 					},
 					nil,
 				)
 			},
 			in: func() *http.Request {
 				drawGachaReq := DrawGachaRequest{
-					Times: 1,
+					BannerID: bannerID,
+					Times:    1,
 				}
 				reqBody, _ := json.Marshal(drawGachaReq)
 				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
@@ -133,13 +246,158 @@ func TestGameHandler_DrawGacha(t *testing.T) {
 						IsNew:  true,
 					},
 				},
+				PityCount: 3,
+			},
+		},
+		{
+			name: "success: duplicate item is converted to coins",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().DrawGacha(
+					gomock.Any(),
+					bannerID,
+					2,
+				).Return(
+					&usecase.GachaDraw{
+						Results: []*usecase.GachaResult{
+							{
+								Collection: &model.Collection{
+									ID:     collectionID,
+									Name:   "collection1",
+									Rarity: 1,
+									Weight: 10,
+								},
+								Has: false,
+							},
+							{
+								Collection: &model.Collection{
+									ID:     collectionID,
+									Name:   "collection1",
+									Rarity: 1,
+									Weight: 10,
+								},
+								Has: true,
+							},
+						},
+						PityCount: 3,
+					},
+					nil,
+				)
+			},
+			in: func() *http.Request {
+				drawGachaReq := DrawGachaRequest{
+					BannerID: bannerID,
+					Times:    2,
+				}
+				reqBody, _ := json.Marshal(drawGachaReq)
+				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
+				return req
+			},
+			wantStatus: http.StatusOK,
+			wantResponse: DrawGachaResponse{
+				Results: []struct {
+					ID     string `json:"id"`
+					Name   string `json:"name"`
+					Rarity int    `json:"rarity"`
+					IsNew  bool   `json:"is_new"`
+				}{
+					{
+						ID:     collectionID,
+						Name:   "collection1",
+						Rarity: 1,
+						IsNew:  true,
+					},
+					{
+						ID:     collectionID,
+						Name:   "collection1",
+						Rarity: 1,
+						IsNew:  false,
+					},
+				},
+				PityCount: 3,
+			},
+		},
+		{
+			name: "Fail: Insufficient balance",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().DrawGacha(
+					gomock.Any(),
+					bannerID,
+					10,
+				).Return(nil, model.ErrInsufficientBalance)
+			},
+			in: func() *http.Request {
+				drawGachaReq := DrawGachaRequest{
+					BannerID: bannerID,
+					Times:    10,
+				}
+				reqBody, _ := json.Marshal(drawGachaReq)
+				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
+				return req
+			},
+			wantStatus:   http.StatusConflict,
+			wantResponse: DrawGachaResponse{},
+		},
+		{
+			name: "Fail: Banner not found",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().DrawGacha(
+					gomock.Any(),
+					bannerID,
+					1,
+				).Return(nil, config.ErrNotFound)
+			},
+			in: func() *http.Request {
+				drawGachaReq := DrawGachaRequest{
+					BannerID: bannerID,
+					Times:    1,
+				}
+				reqBody, _ := json.Marshal(drawGachaReq)
+				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
+				return req
+			},
+			wantStatus:   http.StatusNotFound,
+			wantResponse: DrawGachaResponse{},
+		},
+		{
+			name: "Fail: Banner is not active",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().DrawGacha(
+					gomock.Any(),
+					bannerID,
+					1,
+				).Return(nil, model.ErrBannerInactive)
+			},
+			in: func() *http.Request {
+				drawGachaReq := DrawGachaRequest{
+					BannerID: bannerID,
+					Times:    1,
+				}
+				reqBody, _ := json.Marshal(drawGachaReq)
+				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
+				return req
 			},
+			wantStatus:   http.StatusConflict,
+			wantResponse: DrawGachaResponse{},
+		},
+		{
+			name: "Fail: Banner ID is required",
+			in: func() *http.Request {
+				drawGachaReq := DrawGachaRequest{
+					Times: 1,
+				}
+				reqBody, _ := json.Marshal(drawGachaReq)
+				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
+				return req
+			},
+			wantStatus:   http.StatusBadRequest,
+			wantResponse: DrawGachaResponse{},
 		},
 		{
 			name: "Fail: Invalid request",
 			in: func() *http.Request {
 				drawGachaReq := DrawGachaRequest{
-					Times: -1,
+					BannerID: bannerID,
+					Times:    -1,
 				}
 				reqBody, _ := json.Marshal(drawGachaReq)
 				req, _ := http.NewRequest(http.MethodPut, "/api/gacha/draw", bytes.NewBuffer(reqBody))
@@ -183,3 +441,255 @@ func TestGameHandler_DrawGacha(t *testing.T) {
 		})
 	}
 }
+
+func TestGameHandler_ListBanners(t *testing.T) {
+	t.Parallel()
+
+	startAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	banner := &model.Banner{
+		ID:      uuid.New().String(),
+		Name:    "banner",
+		Cost:    100,
+		StartAt: startAt,
+		EndAt:   startAt.Add(24 * time.Hour),
+		Items: []model.BannerItem{
+			{CollectionID: "collection1", Weight: 90},
+			{CollectionID: "collection2", Weight: 10, RateUp: true},
+		},
+		Guarantee: model.MultiDrawRule{Draws: 10, MinRarity: 5},
+	}
+
+	ctrl := gomock.NewController(t)
+	guc := mock.NewMockGameUseCase(ctrl)
+	guc.EXPECT().ListBanners(gomock.Any()).Return([]*usecase.BannerDetail{
+		{
+			Banner: banner,
+			Pool: model.Collections{
+				{ID: "collection1", Name: "collection1", Rarity: 1, Weight: 90},
+				{ID: "collection2", Name: "collection2", Rarity: 5, Weight: 10},
+			},
+		},
+	}, nil)
+
+	handler := NewGameHandler(guc)
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/gacha/banners", nil)
+	handler.ListBanners(recorder, req)
+
+	if status := recorder.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	var gotResponse ListBannersResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&gotResponse); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	wantResponse := ListBannersResponse{
+		Banners: []BannerResponse{
+			{
+				ID:      banner.ID,
+				Name:    "banner",
+				Cost:    100,
+				StartAt: banner.StartAt,
+				EndAt:   banner.EndAt,
+				Items: []BannerItemResponse{
+					{ID: "collection1", Name: "collection1", Rarity: 1, RateUp: false},
+					{ID: "collection2", Name: "collection2", Rarity: 5, RateUp: true},
+				},
+				Guarantee: BannerGuaranteeResponse{Draws: 10, MinRarity: 5},
+			},
+		},
+	}
+	if !reflect.DeepEqual(gotResponse, wantResponse) {
+		t.Errorf("handler returned unexpected body: got %v want %v", gotResponse, wantResponse)
+	}
+}
+
+func TestGameHandler_GetDropRates(t *testing.T) {
+	t.Parallel()
+
+	bannerID := uuid.New().String()
+
+	patterns := []struct {
+		name         string
+		setup        func(m *mock.MockGameUseCase)
+		query        string
+		wantStatus   int
+		wantResponse GetDropRatesResponse
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().GetDropRates(gomock.Any(), bannerID).Return(model.NewDropRates(bannerID, model.Collections{
+					{ID: "collection1", Name: "collection1", Rarity: 1, Weight: 3},
+					{ID: "collection2", Name: "collection2", Rarity: 2, Weight: 1},
+				}), nil)
+			},
+			query:      "?banner_id=" + bannerID,
+			wantStatus: http.StatusOK,
+			wantResponse: GetDropRatesResponse{
+				BannerID: bannerID,
+				Items: []ItemDropRateResponse{
+					{ID: "collection1", Name: "collection1", Rarity: 1, Rate: 0.75},
+					{ID: "collection2", Name: "collection2", Rarity: 2, Rate: 0.25},
+				},
+				Rarities: []RarityDropRateResponse{
+					{Rarity: 2, Rate: 0.25},
+					{Rarity: 1, Rate: 0.75},
+				},
+			},
+		},
+		{
+			name: "Fail: Banner not found",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().GetDropRates(gomock.Any(), bannerID).Return(nil, config.ErrNotFound)
+			},
+			query:      "?banner_id=" + bannerID,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Fail: Banner ID is required",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			guc := mock.NewMockGameUseCase(ctrl)
+			if tt.setup != nil {
+				tt.setup(guc)
+			}
+
+			handler := NewGameHandler(guc)
+			recorder := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/gacha/rates"+tt.query, nil)
+			handler.GetDropRates(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				var gotResponse GetDropRatesResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&gotResponse); err != nil {
+					t.Fatalf("failed to decode response body: %v", err)
+				}
+				if !reflect.DeepEqual(gotResponse, tt.wantResponse) {
+					t.Errorf("handler returned unexpected body: got %v want %v", gotResponse, tt.wantResponse)
+				}
+			}
+		})
+	}
+}
+
+func TestGameHandler_ListGachaHistory(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name           string
+		setup          func(m *mock.MockGameUseCase)
+		in             func() *http.Request
+		wantStatus     int
+		wantLen        int
+		wantNextCursor string
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().ListGachaHistory(gomock.Any(), "cursor", 10).Return(
+					[]*model.GachaDrawRecord{
+						{
+							DrawID:       "draw",
+							Slot:         0,
+							UserID:       "user",
+							BannerID:     "banner",
+							CollectionID: "collection",
+							Rarity:       5,
+							Cost:         100,
+							PityCount:    0,
+							RNG:          "crypto/rand",
+							PoolVersion:  "version",
+							CreatedAt:    time.Now(),
+						},
+					},
+					"next",
+					nil,
+				)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/gacha/history?cursor=cursor&limit=10", nil)
+				return req
+			},
+			wantStatus:     http.StatusOK,
+			wantLen:        1,
+			wantNextCursor: "next",
+		},
+		{
+			name: "success: no history",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().ListGachaHistory(gomock.Any(), "", 0).Return(nil, "", nil)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/gacha/history", nil)
+				return req
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Fail: invalid cursor",
+			setup: func(m *mock.MockGameUseCase) {
+				m.EXPECT().ListGachaHistory(gomock.Any(), "invalid", 0).Return(nil, "", model.ErrInvalidCursor)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/gacha/history?cursor=invalid", nil)
+				return req
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Fail: invalid limit",
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/api/gacha/history?limit=0", nil)
+				return req
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			guc := mock.NewMockGameUseCase(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(guc)
+			}
+
+			handler := NewGameHandler(guc)
+			recorder := httptest.NewRecorder()
+			handler.ListGachaHistory(recorder, tt.in())
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var response ListGachaDrawsResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Draws == nil || len(response.Draws) != tt.wantLen {
+				t.Errorf("draws = %v, want %v items", response.Draws, tt.wantLen)
+			}
+			if response.NextCursor != tt.wantNextCursor {
+				t.Errorf("next_cursor = %v, want %v", response.NextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
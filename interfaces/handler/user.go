@@ -2,9 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
 
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 	"github.com/tusmasoma/go-tech-dojo/usecase"
 )
@@ -13,6 +16,7 @@ type UserHandler interface {
 	GetUser(w http.ResponseWriter, r *http.Request)
 	ListUserCollections(w http.ResponseWriter, r *http.Request)
 	CreateUser(w http.ResponseWriter, r *http.Request)
+	Login(w http.ResponseWriter, r *http.Request)
 	UpdateUser(w http.ResponseWriter, r *http.Request)
 }
 
@@ -27,11 +31,15 @@ func NewUserHandler(uuc usecase.UserUseCase) UserHandler {
 }
 
 type GetUserResponse struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Coins     int    `json:"coins"`
-	HighScore int    `json:"high_score"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Coins wallet.coinと同じ値。通貨ごとの残高を扱う前のクライアントのために残す
+	Coins     int          `json:"coins"`
+	HighScore int          `json:"high_score"`
+	AvatarURL string       `json:"avatar_url"`
+	Locale    string       `json:"locale"`
+	Wallet    model.Wallet `json:"wallet"`
 }
 
 func (uh *userHandler) GetUser(w http.ResponseWriter, r *http.Request) {
@@ -48,8 +56,11 @@ func (uh *userHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		ID:        user.ID,
 		Name:      user.Name,
 		Email:     user.Email,
-		Coins:     user.Coins,
+		Coins:     user.Wallet[model.CurrencyCoin],
 		HighScore: user.HighScore,
+		AvatarURL: user.AvatarURL,
+		Locale:    user.Locale,
+		Wallet:    user.Wallet,
 	}); err != nil {
 		http.Error(w, "Failed to encode user to JSON", http.StatusInternalServerError)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -72,14 +83,13 @@ func (uh *userHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := uh.uuc.CreateUserAndToken(ctx, requestBody.Email, requestBody.Password)
+	pair, err := uh.uuc.CreateUserAndToken(ctx, requestBody.Email, requestBody.Password)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Authorization", "Bearer "+token)
-	w.WriteHeader(http.StatusOK)
+	writeTokenResponse(w, pair)
 }
 
 func (uh *userHandler) isValidCreateUserRequest(body io.ReadCloser, requestBody *CreateUserRequest) bool {
@@ -94,17 +104,63 @@ func (uh *userHandler) isValidCreateUserRequest(body io.ReadCloser, requestBody
 	return true
 }
 
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (uh *userHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody LoginRequest
+	defer r.Body.Close()
+	if !uh.isValidLoginRequest(r.Body, &requestBody) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	pair, err := uh.uuc.Login(ctx, requestBody.Email, requestBody.Password)
+	if errors.Is(err, usecase.ErrInvalidCredentials) {
+		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeTokenResponse(w, pair)
+}
+
+func (uh *userHandler) isValidLoginRequest(body io.ReadCloser, requestBody *LoginRequest) bool {
+	if err := json.NewDecoder(body).Decode(requestBody); err != nil {
+		log.Error("Failed to decode request body: %v", err)
+		return false
+	}
+	if requestBody.Email == "" || requestBody.Password == "" {
+		log.Warn("Invalid request body: email or password is empty")
+		return false
+	}
+	return true
+}
+
+// UpdateUserRequest 省略した項目は変更しない
+// コインとハイスコアは管理者APIでのみ変更できる
 type UpdateUserRequest struct {
-	Coins     int `json:"coins"`
-	HighScore int `json:"high_score"`
+	Name      *string `json:"name,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Locale    *string `json:"locale,omitempty"`
 }
 
 type UpdateUserResponse struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Coins     int    `json:"coins"`
-	HighScore int    `json:"high_score"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Coins wallet.coinと同じ値。通貨ごとの残高を扱う前のクライアントのために残す
+	Coins     int          `json:"coins"`
+	HighScore int          `json:"high_score"`
+	AvatarURL string       `json:"avatar_url"`
+	Locale    string       `json:"locale"`
+	Wallet    model.Wallet `json:"wallet"`
 }
 
 func (uh *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -117,8 +173,16 @@ func (uh *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := uh.uuc.UpdateUser(ctx, requestBody.Coins, requestBody.HighScore)
-	if err != nil {
+	user, err := uh.uuc.UpdateProfile(ctx, model.ProfileUpdate{
+		Name:      requestBody.Name,
+		AvatarURL: requestBody.AvatarURL,
+		Locale:    requestBody.Locale,
+	})
+	var verr *model.ValidationError
+	if errors.As(err, &verr) {
+		writeValidationError(w, verr)
+		return
+	} else if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -128,8 +192,11 @@ func (uh *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		ID:        user.ID,
 		Name:      user.Name,
 		Email:     user.Email,
-		Coins:     user.Coins,
+		Coins:     user.Wallet[model.CurrencyCoin],
 		HighScore: user.HighScore,
+		AvatarURL: user.AvatarURL,
+		Locale:    user.Locale,
+		Wallet:    user.Wallet,
 	}); err != nil {
 		http.Error(w, "Failed to encode user to JSON", http.StatusInternalServerError)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -137,13 +204,16 @@ func (uh *userHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// isValidUpdateUserRequest coinsなど変更できない項目を含むリクエストは拒否する
 func (uh *userHandler) isValidUpdateUserRequest(body io.ReadCloser, requestBody *UpdateUserRequest) bool {
-	if err := json.NewDecoder(body).Decode(requestBody); err != nil {
-		log.Error("Failed to decode request body: %v", err)
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(requestBody); err != nil {
+		log.Warn("Failed to decode request body", log.Ferror(err))
 		return false
 	}
-	if requestBody.Coins < 0 || requestBody.HighScore < 0 {
-		log.Warn("Invalid request body: %v", requestBody)
+	if requestBody.Name == nil && requestBody.AvatarURL == nil && requestBody.Locale == nil {
+		log.Warn("Invalid request body: no fields to update")
 		return false
 	}
 	return true
@@ -156,6 +226,10 @@ type ListUserCollectionsResponse struct {
 		Rarity int    `json:"rarity"`
 		Weight int    `json:"weight"`
 		Has    bool   `json:"has"`
+		// Quantity 所持数。未所持の場合は0
+		Quantity        int        `json:"quantity"`
+		FirstObtainedAt *time.Time `json:"first_obtained_at"`
+		LastObtainedAt  *time.Time `json:"last_obtained_at"`
 	} `json:"collections"`
 }
 
@@ -179,26 +253,35 @@ func (uh *userHandler) ListUserCollections(w http.ResponseWriter, r *http.Reques
 
 func (uh *userHandler) convertToResponseCollections(collections []*usecase.Collection) ListUserCollectionsResponse {
 	responseCollections := make([]struct {
-		ID     string `json:"id"`
-		Name   string `json:"name"`
-		Rarity int    `json:"rarity"`
-		Weight int    `json:"weight"`
-		Has    bool   `json:"has"`
+		ID              string     `json:"id"`
+		Name            string     `json:"name"`
+		Rarity          int        `json:"rarity"`
+		Weight          int        `json:"weight"`
+		Has             bool       `json:"has"`
+		Quantity        int        `json:"quantity"`
+		FirstObtainedAt *time.Time `json:"first_obtained_at"`
+		LastObtainedAt  *time.Time `json:"last_obtained_at"`
 	}, len(collections))
 
 	for i, collection := range collections {
 		responseCollections[i] = struct {
-			ID     string `json:"id"`
-			Name   string `json:"name"`
-			Rarity int    `json:"rarity"`
-			Weight int    `json:"weight"`
-			Has    bool   `json:"has"`
+			ID              string     `json:"id"`
+			Name            string     `json:"name"`
+			Rarity          int        `json:"rarity"`
+			Weight          int        `json:"weight"`
+			Has             bool       `json:"has"`
+			Quantity        int        `json:"quantity"`
+			FirstObtainedAt *time.Time `json:"first_obtained_at"`
+			LastObtainedAt  *time.Time `json:"last_obtained_at"`
 		}{
-			ID:     collection.ID,
-			Name:   collection.Name,
-			Rarity: collection.Rarity,
-			Weight: collection.Weight,
-			Has:    collection.Has,
+			ID:              collection.ID,
+			Name:            collection.Name,
+			Rarity:          collection.Rarity,
+			Weight:          collection.Weight,
+			Has:             collection.Has,
+			Quantity:        collection.Quantity,
+			FirstObtainedAt: collection.FirstObtainedAt,
+			LastObtainedAt:  collection.LastObtainedAt,
 		}
 	}
 
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+type ValidationErrorResponse struct {
+	Errors []model.FieldError `json:"errors"`
+}
+
+// writeValidationError 検証に失敗した項目とその理由を400で返す
+func writeValidationError(w http.ResponseWriter, verr *model.ValidationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(ValidationErrorResponse{Errors: verr.Fields}); err != nil {
+		log.Error("Failed to encode validation error to JSON", log.Ferror(err))
+	}
+}
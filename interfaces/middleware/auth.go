@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
 	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 )
@@ -18,10 +20,14 @@ type AuthMiddleware interface {
 	Authenticate(nextFunc http.Handler) http.Handler
 }
 
-type authMiddleware struct{}
+type authMiddleware struct {
+	trr repository.TokenRevocationRepository
+}
 
-func NewAuthMiddleware() AuthMiddleware {
-	return &authMiddleware{}
+func NewAuthMiddleware(trr repository.TokenRevocationRepository) AuthMiddleware {
+	return &authMiddleware{
+		trr: trr,
+	}
 }
 
 // Authenticate ユーザ認証を行ってContextへユーザID情報を保存する
@@ -47,26 +53,59 @@ func (am *authMiddleware) Authenticate(next http.Handler) http.Handler {
 		jwt := parts[1]
 
 		// アクセストークンの検証
-		err := auth.ValidateAccessToken(jwt)
+		claims, err := auth.Verify(jwt)
 		if err != nil {
 			log.Warn("Authentication failed: invalid access token", log.Ferror(err))
-			http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+			writeTokenError(w, err)
 			return
 		}
 
-		// JWTからペイロード取得
-		var payload auth.Payload
-		payload, err = auth.GetPayloadFromToken(jwt)
+		// ログアウト済みのトークンか確認
+		revoked, err := am.trr.IsRevoked(ctx, claims.JTI, claims.UserID, claims.IssuedAtTime())
 		if err != nil {
-			log.Warn("Authentication failed: invalid access token", log.Ferror(err))
-			http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+			log.Error("Authentication failed: could not check token revocation", log.Ferror(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if revoked {
+			log.Info("Authentication failed: token has been revoked", log.Fstring("userID", claims.UserID))
+			http.Error(w, "Authentication failed: token has been revoked", http.StatusUnauthorized)
+			return
+		}
+
+		// 役割のクレームがないトークンはplayerとして扱う
+		role, err := model.ParseRole(claims.Role)
+		if err != nil {
+			log.Warn("Authentication failed: unknown role", log.Fstring("role", claims.Role))
+			writeTokenError(w, err)
 			return
 		}
 
-		// コンテキストに userID を保存
-		ctx = context.WithValue(ctx, config.ContextUserIDKey, payload.UserID)
+		// コンテキストに認証されたユーザとクレームを保存
+		ctx = model.ContextWithPrincipal(ctx, model.Principal{UserID: claims.UserID, Role: role})
+		ctx = context.WithValue(ctx, config.ContextTokenKey, claims)
 
-		log.Info("Successfully Authentication", log.Fstring("userID", payload.UserID))
+		log.Info("Successfully Authentication", log.Fstring("userID", claims.UserID))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// writeTokenError 検証エラーの種類ごとに異なるメッセージで401を返す
+// 詳細な理由はログにのみ出力し、レスポンスには含めない
+func writeTokenError(w http.ResponseWriter, err error) {
+	var description string
+	switch {
+	case errors.Is(err, auth.ErrTokenExpired):
+		description = "token has expired"
+	case errors.Is(err, auth.ErrTokenNotYetValid):
+		description = "token is not valid yet"
+	case errors.Is(err, auth.ErrTokenSignatureInvalid), errors.Is(err, auth.ErrTokenAlgorithmNotAllowed):
+		description = "token signature is invalid"
+	case errors.Is(err, auth.ErrTokenIssuerInvalid), errors.Is(err, auth.ErrTokenAudienceInvalid):
+		description = "token was not issued for this service"
+	default:
+		description = "token is malformed"
+	}
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, description))
+	http.Error(w, "Authentication failed: "+description, http.StatusUnauthorized)
+}
@@ -1,20 +1,25 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
-	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/infra/memory"
 	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
 )
 
 func dummyTestHandler(w http.ResponseWriter, r *http.Request) {
-	userIDValue := r.Context().Value(config.ContextUserIDKey)
-	if userID, _ := userIDValue.(string); userID == "" {
+	if _, ok := model.PrincipalFromContext(r.Context()); !ok {
 		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
@@ -25,10 +30,11 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 	userID := uuid.MustParse("f6db2530-cd9b-4ac1-8dc1-38c795e6eec2")
 	email := "test@gmail.com"
 
-	jwt, _ := auth.GenerateToken(userID.String(), email)
+	jwt, jti := auth.GenerateToken(userID.String(), email, "player")
 
 	patterns := []struct {
 		name       string
+		setup      func(trr repository.TokenRevocationRepository)
 		in         func() *http.Request
 		wantStatus int
 	}{
@@ -41,6 +47,16 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name: "Fail: Unknown Role",
+			in: func() *http.Request {
+				unknownRoleJWT, _ := auth.GenerateToken(userID.String(), email, "root")
+				req, _ := http.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Authorization", "Bearer "+unknownRoleJWT)
+				return req
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
 		{
 			name: "Fail: No Auth Header",
 			in: func() *http.Request {
@@ -67,6 +83,43 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 			},
 			wantStatus: http.StatusUnauthorized,
 		},
+		{
+			name: "Fail: Revoked Token",
+			setup: func(trr repository.TokenRevocationRepository) {
+				_ = trr.Revoke(context.Background(), jti, auth.AccessTokenLifetime)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Authorization", "Bearer "+jwt)
+				return req
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Fail: All Sessions Of User Revoked",
+			setup: func(trr repository.TokenRevocationRepository) {
+				_ = trr.RevokeAllForUser(context.Background(), userID.String(), time.Now(), auth.AccessTokenLifetime)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Authorization", "Bearer "+jwt)
+				return req
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "success: token issued in the same second after all sessions revoked",
+			setup: func(trr repository.TokenRevocationRepository) {
+				claims, _ := auth.Verify(jwt)
+				_ = trr.RevokeAllForUser(context.Background(), userID.String(), claims.IssuedAtTime().Add(-time.Millisecond), auth.AccessTokenLifetime)
+			},
+			in: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Authorization", "Bearer "+jwt)
+				return req
+			},
+			wantStatus: http.StatusOK,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -74,7 +127,11 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			am := NewAuthMiddleware()
+			trr := memory.NewTokenRevocationRepository()
+			if tt.setup != nil {
+				tt.setup(trr)
+			}
+			am := NewAuthMiddleware(trr)
 
 			handler := am.Authenticate(http.HandlerFunc(dummyTestHandler))
 
@@ -88,3 +145,79 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func Test_writeTokenError(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name     string
+		err      error
+		wantBody string
+	}{
+		{
+			name:     "expired",
+			err:      fmt.Errorf("wrapped: %w", auth.ErrTokenExpired),
+			wantBody: "Authentication failed: token has expired\n",
+		},
+		{
+			name:     "bad signature",
+			err:      auth.ErrTokenSignatureInvalid,
+			wantBody: "Authentication failed: token signature is invalid\n",
+		},
+		{
+			name:     "alg not allowed",
+			err:      auth.ErrTokenAlgorithmNotAllowed,
+			wantBody: "Authentication failed: token signature is invalid\n",
+		},
+		{
+			name:     "wrong audience",
+			err:      auth.ErrTokenAudienceInvalid,
+			wantBody: "Authentication failed: token was not issued for this service\n",
+		},
+		{
+			name:     "malformed",
+			err:      auth.ErrTokenMalformed,
+			wantBody: "Authentication failed: token is malformed\n",
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			writeTokenError(recorder, tt.err)
+
+			if status := recorder.Code; status != http.StatusUnauthorized {
+				t.Errorf("wrong status code: got %v want %v", status, http.StatusUnauthorized)
+			}
+			if body := recorder.Body.String(); body != tt.wantBody {
+				t.Errorf("wrong body: got %q want %q", body, tt.wantBody)
+			}
+			if recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("WWW-Authenticate header is not set")
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Authenticate_Principal(t *testing.T) {
+	t.Parallel()
+
+	jwt, _ := auth.GenerateToken("operator", "operator@gmail.com", "operator")
+	am := NewAuthMiddleware(memory.NewTokenRevocationRepository())
+
+	var got model.Principal
+	handler := am.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = model.PrincipalFromContext(r.Context())
+	}))
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := model.Principal{UserID: "operator", Role: model.RoleOperator}
+	if got != want {
+		t.Errorf("principal = %v, want %v", got, want)
+	}
+}
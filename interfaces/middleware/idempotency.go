@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+type IdempotencyMiddleware interface {
+	Idempotent(next http.Handler) http.Handler
+}
+
+type idempotencyMiddleware struct {
+	ir            repository.IdempotencyRepository
+	ttl           time.Duration
+	inProgressTTL time.Duration
+}
+
+func NewIdempotencyMiddleware(ir repository.IdempotencyRepository, ttl, inProgressTTL time.Duration) IdempotencyMiddleware {
+	return &idempotencyMiddleware{
+		ir:            ir,
+		ttl:           ttl,
+		inProgressTTL: inProgressTTL,
+	}
+}
+
+// Idempotent Idempotency-Keyヘッダが指定されたリクエストの最初のレスポンスを保存し、再送時には同じレスポンスを返す
+// 同じキーで異なるリクエストが送られた場合や、最初のリクエストを処理中の場合は409を返す
+// 処理中の予約はinProgressTTLで失効し、完了したレスポンスだけをttlの間保存する
+// Authenticateの後に使う必要がある
+func (im *idempotencyMiddleware) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > model.IdempotencyKeyMaxLength {
+			http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+			return
+		}
+		principal, ok := model.PrincipalFromContext(ctx)
+		if !ok {
+			log.Warn("User ID not found in request context")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			log.Error("Failed to read request body", log.Ferror(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		storageKey := model.IdempotencyStorageKey(principal.UserID, key)
+		record := model.NewIdempotencyRecord(r.Method, r.URL.Path, body)
+		reserved, err := im.ir.Reserve(ctx, storageKey, *record, im.inProgressTTL)
+		if err != nil {
+			log.Error("Failed to reserve idempotency key", log.Ferror(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !reserved {
+			im.replay(w, r, storageKey, record.RequestHash)
+			return
+		}
+
+		// クライアントが切断しても処理結果は保存する必要があるため、リクエストのキャンセルを引き継がない
+		storeCtx := context.WithoutCancel(ctx)
+		release := func() {
+			if err := im.ir.Delete(storeCtx, storageKey); err != nil {
+				log.Error("Failed to release idempotency key", log.Fstring("key", key), log.Ferror(err))
+			}
+		}
+		defer func() {
+			// ハンドラがpanicした場合も同じキーで再実行できるようにしてから伝播させる
+			if rec := recover(); rec != nil {
+				release()
+				panic(rec)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// サーバ側のエラーは再送で成功する可能性があるため、保存せずに同じキーで再実行できるようにする
+		if recorder.statusCode >= http.StatusInternalServerError {
+			release()
+			return
+		}
+		record.Complete(recorder.statusCode, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		// 保存に失敗した場合は予約がinProgressTTLで失効し、同じキーで再実行できるようになる
+		if err = im.ir.Update(storeCtx, storageKey, *record, im.ttl); err != nil {
+			log.Error("Failed to save idempotent response", log.Fstring("key", key), log.Ferror(err))
+		}
+	})
+}
+
+func (im *idempotencyMiddleware) replay(w http.ResponseWriter, r *http.Request, storageKey, requestHash string) {
+	record, err := im.ir.Get(r.Context(), storageKey)
+	if errors.Is(err, config.ErrCacheMiss) {
+		// 最初のリクエストが失敗してキーが解放された直後
+		http.Error(w, "Request with this Idempotency-Key is being retried, try again", http.StatusConflict)
+		return
+	} else if err != nil {
+		log.Error("Failed to get idempotency record", log.Ferror(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case record.RequestHash != requestHash:
+		log.Warn("Idempotency-Key reused with a different request", log.Fstring("path", r.URL.Path))
+		http.Error(w, "Idempotency-Key has already been used for a different request", http.StatusConflict)
+	case !record.Completed:
+		http.Error(w, "Request with this Idempotency-Key is in progress", http.StatusConflict)
+	default:
+		if record.ContentType != "" {
+			w.Header().Set("Content-Type", record.ContentType)
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(record.StatusCode)
+		if _, err = w.Write(record.Body); err != nil {
+			log.Error("Failed to write replayed response", log.Ferror(err))
+		}
+	}
+}
+
+// responseRecorder クライアントに書き込みながらステータスとボディを記録する
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	if !rr.wroteHeader {
+		rr.statusCode = statusCode
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/infra/memory"
+)
+
+func TestIdempotencyMiddleware_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		key  string
+		body string
+	}
+
+	patterns := []struct {
+		name         string
+		requests     []request
+		handler      http.HandlerFunc
+		wantStatus   int
+		wantBody     string
+		wantReplayed bool
+		wantCalls    int
+	}{
+		{
+			name:       "success: without key",
+			requests:   []request{{body: `{"times":1}`}, {body: `{"times":1}`}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"count":2}`,
+			wantCalls:  2,
+		},
+		{
+			name:         "success: retry is replayed",
+			requests:     []request{{key: "key", body: `{"times":1}`}, {key: "key", body: `{"times":1}`}},
+			wantStatus:   http.StatusOK,
+			wantBody:     `{"count":1}`,
+			wantReplayed: true,
+			wantCalls:    1,
+		},
+		{
+			name:       "success: different keys are processed separately",
+			requests:   []request{{key: "key1", body: `{"times":1}`}, {key: "key2", body: `{"times":1}`}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"count":2}`,
+			wantCalls:  2,
+		},
+		{
+			name: "success: server error is not saved",
+			requests: []request{
+				{key: "key", body: `{"times":1}`},
+				{key: "key", body: `{"times":1}`},
+			},
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  2,
+		},
+		{
+			name:       "Fail: key reused with a different body",
+			requests:   []request{{key: "key", body: `{"times":1}`}, {key: "key", body: `{"times":10}`}},
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "Fail: key is too long",
+			requests:   []request{{key: strings.Repeat("a", model.IdempotencyKeyMaxLength+1), body: `{}`}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Fail: body is too large",
+			requests:   []request{{key: "key", body: strings.Repeat("a", maxIdempotentRequestBytes+1)}},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			handler := tt.handler
+			if handler == nil {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					// 再送時にもボディを読めることを確認する
+					if _, err := io.ReadAll(r.Body); err != nil {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = io.WriteString(w, `{"count":`+strconv.Itoa(calls)+`}`)
+				}
+			}
+			counted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				handler(w, r)
+			})
+
+			im := NewIdempotencyMiddleware(memory.NewIdempotencyRepository(), time.Minute, time.Minute)
+			h := im.Idempotent(counted)
+
+			ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "user", Role: model.RolePlayer})
+			var recorder *httptest.ResponseRecorder
+			for _, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodPost, "/api/gacha/draw", strings.NewReader(req.body)).WithContext(ctx)
+				if req.key != "" {
+					r.Header.Set(IdempotencyKeyHeader, req.key)
+				}
+				recorder = httptest.NewRecorder()
+				h.ServeHTTP(recorder, r)
+			}
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+			if tt.wantBody != "" && recorder.Body.String() != tt.wantBody {
+				t.Errorf("handler returned unexpected body: got %v want %v", recorder.Body.String(), tt.wantBody)
+			}
+			if replayed := recorder.Header().Get(IdempotentReplayedHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed header: got %v want %v", replayed, tt.wantReplayed)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls: got %v want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	t.Parallel()
+
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "user", Role: model.RolePlayer})
+	repo := memory.NewIdempotencyRepository()
+	record := model.NewIdempotencyRecord(http.MethodPost, "/api/game/finish", []byte(`{}`))
+	_, _ = repo.Reserve(ctx, model.IdempotencyStorageKey("user", "key"), *record, time.Minute)
+
+	im := NewIdempotencyMiddleware(repo, time.Minute, time.Minute)
+	h := im.Idempotent(http.HandlerFunc(dummyTestHandler))
+
+	r := httptest.NewRequest(http.MethodPost, "/api/game/finish", strings.NewReader(`{}`)).WithContext(ctx)
+	r.Header.Set(IdempotencyKeyHeader, "key")
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, r)
+
+	if status := recorder.Code; status != http.StatusConflict {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusConflict)
+	}
+}
+
+func TestIdempotencyMiddleware_ClientDisconnected(t *testing.T) {
+	t.Parallel()
+
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "user", Role: model.RolePlayer})
+	var calls int
+	im := NewIdempotencyMiddleware(memory.NewIdempotencyRepository(), time.Minute, time.Minute)
+	h := im.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// 処理中にクライアントが切断してもレスポンスは保存され、再送ではリプレイされる
+	disconnected, cancel := context.WithCancel(ctx)
+	r := httptest.NewRequest(http.MethodPost, "/api/game/finish", strings.NewReader(`{}`)).WithContext(disconnected)
+	r.Header.Set(IdempotencyKeyHeader, "key")
+	cancelOnWrite := &cancelRecorder{ResponseRecorder: httptest.NewRecorder(), cancel: cancel}
+	h.ServeHTTP(cancelOnWrite, r)
+
+	r = httptest.NewRequest(http.MethodPost, "/api/game/finish", strings.NewReader(`{}`)).WithContext(ctx)
+	r.Header.Set(IdempotencyKeyHeader, "key")
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, r)
+
+	if status := recorder.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if replayed := recorder.Header().Get(IdempotentReplayedHeader); replayed != "true" {
+		t.Errorf("replayed header: got %v want %v", replayed, "true")
+	}
+	if calls != 1 {
+		t.Errorf("handler calls: got %v want %v", calls, 1)
+	}
+}
+
+func TestIdempotencyMiddleware_Panic(t *testing.T) {
+	t.Parallel()
+
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "user", Role: model.RolePlayer})
+	var calls int
+	im := NewIdempotencyMiddleware(memory.NewIdempotencyRepository(), time.Minute, time.Minute)
+	h := im.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("unexpected")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() (recorder *httptest.ResponseRecorder, panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		r := httptest.NewRequest(http.MethodPost, "/api/game/finish", strings.NewReader(`{}`)).WithContext(ctx)
+		r.Header.Set(IdempotencyKeyHeader, "key")
+		recorder = httptest.NewRecorder()
+		h.ServeHTTP(recorder, r)
+		return recorder, false
+	}
+
+	if _, panicked := serve(); !panicked {
+		t.Fatalf("panic was not propagated")
+	}
+	// panicしたリクエストのキーは解放され、再送で処理される
+	recorder, _ := serve()
+	if status := recorder.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls: got %v want %v", calls, 2)
+	}
+}
+
+// cancelRecorder レスポンスの書き込み時にクライアントの切断を再現する
+type cancelRecorder struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (cr *cancelRecorder) WriteHeader(statusCode int) {
+	cr.cancel()
+	cr.ResponseRecorder.WriteHeader(statusCode)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// RequireRole Authenticateの後に使用し、rolesのいずれも持たないユーザのリクエストを403で拒否する
+func RequireRole(roles ...model.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := model.PrincipalFromContext(r.Context())
+			if !ok {
+				log.Warn("Authorization failed: user not found in request context")
+				http.Error(w, "Authentication failed: missing user", http.StatusUnauthorized)
+				return
+			}
+			if !principal.HasRole(roles...) {
+				log.Warn(
+					"Authorization failed: insufficient role",
+					log.Fstring("userID", principal.UserID),
+					log.Fstring("role", string(principal.Role)),
+				)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+func TestRequireRole(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name       string
+		ctx        context.Context
+		roles      []model.Role
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			ctx:        model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "admin", Role: model.RoleAdmin}),
+			roles:      []model.Role{model.RoleAdmin},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "success: one of the roles",
+			ctx:        model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "operator", Role: model.RoleOperator}),
+			roles:      []model.Role{model.RoleOperator, model.RoleAdmin},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Fail: insufficient role",
+			ctx:        model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "operator", Role: model.RoleOperator}),
+			roles:      []model.Role{model.RoleAdmin},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Fail: player",
+			ctx:        model.ContextWithPrincipal(context.Background(), model.Principal{UserID: "player", Role: model.RolePlayer}),
+			roles:      []model.Role{model.RoleOperator, model.RoleAdmin},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Fail: unauthenticated",
+			ctx:        context.Background(),
+			roles:      []model.Role{model.RoleAdmin},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := RequireRole(tt.roles...)(http.HandlerFunc(dummyTestHandler))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin", nil).WithContext(tt.ctx)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			if status := recorder.Code; status != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"strings"
+)
+
+// ErrGameSessionTokenInvalid ゲームセッションのトークンの形式または署名が不正
+var ErrGameSessionTokenInvalid = errors.New("game session token is invalid")
+
+// gameSessionContext 他の用途のHMACと署名を取り違えないよう、署名対象に含める
+const gameSessionContext = "game-session:"
+
+// SignGameSession セッションIDに署名し、クライアントに渡すトークンを返す
+// クライアントがセッションIDを推測・改ざんしてもサーバ側で検出できる
+func SignGameSession(secret []byte, sessionID string) string {
+	return sessionID + "." + base64UrlEncode(gameSessionMAC(secret, sessionID))
+}
+
+// VerifyGameSession トークンの署名を検証してセッションIDを返す
+func VerifyGameSession(secret []byte, token string) (string, error) {
+	sessionID, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok || sessionID == "" {
+		return "", ErrGameSessionTokenInvalid
+	}
+	mac, err := base64UrlDecode(encodedMAC)
+	if err != nil {
+		return "", ErrGameSessionTokenInvalid
+	}
+	if !hmac.Equal(mac, gameSessionMAC(secret, sessionID)) {
+		return "", ErrGameSessionTokenInvalid
+	}
+	return sessionID, nil
+}
+
+func gameSessionMAC(secret []byte, sessionID string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(gameSessionContext + sessionID))
+	return h.Sum(nil)
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_VerifyGameSession(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+	token := SignGameSession(secret, "session")
+
+	patterns := []struct {
+		name    string
+		secret  []byte
+		token   string
+		want    string
+		wantErr error
+	}{
+		{name: "success", secret: secret, token: token, want: "session"},
+		{name: "Fail: different secret", secret: []byte("other"), token: token, wantErr: ErrGameSessionTokenInvalid},
+		{name: "Fail: tampered session ID", secret: secret, token: "other" + token[len("session"):], wantErr: ErrGameSessionTokenInvalid},
+		{name: "Fail: missing signature", secret: secret, token: "session", wantErr: ErrGameSessionTokenInvalid},
+		{name: "Fail: malformed signature", secret: secret, token: "session.!!", wantErr: ErrGameSessionTokenInvalid},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := VerifyGameSession(tt.secret, tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyGameSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyGameSession() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,18 @@
 package auth
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	jwtgo "github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
@@ -13,12 +23,13 @@ func Test_JWTToken(t *testing.T) {
 	email := "test@gmail.com"
 
 	// GenerateToken test
-	jwt, jti := GenerateToken(userID.String(), email)
+	jwt, jti := GenerateToken(userID.String(), email, "admin")
 
 	// JWTのフォーマットが正しいことを確認
 	token, err := jwtgo.Parse(jwt, func(token *jwtgo.Token) (interface{}, error) {
 		// ここで公開キーを使って署名を検証する（公開キーは環境に依存する）
-		return loadPublicKey(rawPublicKey)
+		kid, _ := token.Header["kid"].(string)
+		return CurrentKeySet().verificationKey(kid)
 	})
 	if err != nil {
 		t.Errorf("Failed to parse JWT: %s", err)
@@ -34,23 +45,288 @@ func Test_JWTToken(t *testing.T) {
 		t.Errorf("Expected email %s, got %s", email, claims["Email"])
 	}
 
+	if claims["role"] != "admin" {
+		t.Errorf("Expected role %s, got %s", "admin", claims["role"])
+	}
+
 	if claims["jti"] != jti {
 		t.Errorf("Expected JTI %s, got %s", jti, claims["jti"])
 	}
 
-	// ValidateAccessToken test
-	err = ValidateAccessToken(jwt)
+	// Verify test
+	verified, err := Verify(jwt)
 	if err != nil {
-		t.Errorf("Failed to ValidateAccessToken: %s", err)
+		t.Errorf("Failed to Verify: %s", err)
+	}
+	wantClaims := Claims{
+		JTI:        jti,
+		UserID:     userID.String(),
+		Email:      email,
+		Role:       "admin",
+		Issuer:     DefaultIssuer,
+		Audience:   Audience{DefaultAudience},
+		IssuedAt:   verified.IssuedAt,
+		IssuedAtMs: verified.IssuedAtMs,
+		NotBefore:  verified.IssuedAt,
+		ExpiresAt:  verified.IssuedAt + int64(AccessTokenLifetime.Seconds()),
+	}
+	if verified.IssuedAtMs/1000 != verified.IssuedAt {
+		t.Errorf("iat_ms = %v, want within iat %v", verified.IssuedAtMs, verified.IssuedAt)
+	}
+	if !reflect.DeepEqual(verified, wantClaims) {
+		t.Errorf("Verify() \n got = %v,\n want = %v", verified, wantClaims)
+	}
+}
+
+func Test_validateTimeClaims(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	issuedAt := now.Unix()
+
+	patterns := []struct {
+		name    string
+		claims  Claims
+		now     time.Time
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			claims:  Claims{IssuedAt: issuedAt, NotBefore: issuedAt, ExpiresAt: now.Add(AccessTokenLifetime).Unix()},
+			now:     now,
+			wantErr: nil,
+		},
+		{
+			name:    "within leeway after expiry",
+			claims:  Claims{IssuedAt: issuedAt, NotBefore: issuedAt, ExpiresAt: now.Add(AccessTokenLifetime).Unix()},
+			now:     now.Add(AccessTokenLifetime + ClockSkewLeeway/2),
+			wantErr: nil,
+		},
+		{
+			name:    "Fail: expired",
+			claims:  Claims{IssuedAt: issuedAt, NotBefore: issuedAt, ExpiresAt: now.Add(AccessTokenLifetime).Unix()},
+			now:     now.Add(AccessTokenLifetime + ClockSkewLeeway + time.Second),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "Fail: missing exp",
+			claims:  Claims{IssuedAt: issuedAt, NotBefore: issuedAt},
+			now:     now,
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "Fail: not yet valid",
+			claims:  Claims{IssuedAt: issuedAt, NotBefore: now.Add(time.Hour).Unix(), ExpiresAt: now.Add(2 * time.Hour).Unix()},
+			now:     now,
+			wantErr: ErrTokenNotYetValid,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := validateTimeClaims(tt.claims, tt.now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateTimeClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
 	}
+}
 
-	// GetPayloadFromToken test
-	payload, err := GetPayloadFromToken(jwt)
+func signTestToken(t *testing.T, key crypto.Signer, h header, claims Claims) string {
+	t.Helper()
+
+	headerBytes, _ := json.Marshal(h)
+	claimsBytes, _ := json.Marshal(claims)
+	signingInput := base64UrlEncode(headerBytes) + "." + base64UrlEncode(claimsBytes)
+	signature, err := sign(h.Alg, key, []byte(signingInput))
 	if err != nil {
-		t.Errorf("Failed to GetPayloadFromToken: %s", err)
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64UrlEncode(signature)
+}
+
+func Test_Verify(t *testing.T) {
+	previous := CurrentKeySet()
+	t.Cleanup(func() { UseKeySet(previous) })
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, ephemeralKeyBits)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	keys := map[string]crypto.Signer{"rsa": rsaKey, "ec": ecKey, "ed": edKey}
+
+	now := time.Now()
+	validClaims := Claims{
+		JTI:       "jti",
+		UserID:    "user",
+		Issuer:    DefaultIssuer,
+		Audience:  Audience{DefaultAudience},
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(AccessTokenLifetime).Unix(),
+	}
+	withClaims := func(f func(c *Claims)) Claims {
+		c := validClaims
+		f(&c)
+		return c
+	}
+
+	patterns := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+	}{
+		{
+			name: "RS256",
+			token: func(t *testing.T) string {
+				return signTestToken(t, rsaKey, header{Typ: "JWT", Alg: AlgorithmRS256, Kid: "rsa"}, validClaims)
+			},
+		},
+		{
+			name: "ES256",
+			token: func(t *testing.T) string {
+				return signTestToken(t, ecKey, header{Typ: "JWT", Alg: AlgorithmES256, Kid: "ec"}, validClaims)
+			},
+		},
+		{
+			name: "EdDSA",
+			token: func(t *testing.T) string {
+				return signTestToken(t, edKey, header{Typ: "JWT", Alg: AlgorithmEdDSA, Kid: "ed"}, validClaims)
+			},
+		},
+		{
+			name: "Fail: malformed",
+			token: func(t *testing.T) string {
+				return "invalid token"
+			},
+			wantErr: ErrTokenMalformed,
+		},
+		{
+			name: "Fail: alg none",
+			token: func(t *testing.T) string {
+				headerBytes, _ := json.Marshal(header{Typ: "JWT", Alg: "none", Kid: "rsa"})
+				claimsBytes, _ := json.Marshal(validClaims)
+				return base64UrlEncode(headerBytes) + "." + base64UrlEncode(claimsBytes) + "."
+			},
+			wantErr: ErrTokenAlgorithmNotAllowed,
+		},
+		{
+			name: "Fail: alg does not match key",
+			token: func(t *testing.T) string {
+				return signTestToken(t, ecKey, header{Typ: "JWT", Alg: AlgorithmES256, Kid: "rsa"}, validClaims)
+			},
+			wantErr: ErrTokenAlgorithmNotAllowed,
+		},
+		{
+			name: "Fail: signed by unknown key",
+			token: func(t *testing.T) string {
+				return signTestToken(t, otherKey, header{Typ: "JWT", Alg: AlgorithmES256, Kid: "ec"}, validClaims)
+			},
+			wantErr: ErrTokenSignatureInvalid,
+		},
+		{
+			name: "Fail: unknown kid",
+			token: func(t *testing.T) string {
+				return signTestToken(t, otherKey, header{Typ: "JWT", Alg: AlgorithmES256, Kid: "other"}, validClaims)
+			},
+			wantErr: ErrTokenSignatureInvalid,
+		},
+		{
+			name: "Fail: tampered claims",
+			token: func(t *testing.T) string {
+				parts := strings.Split(signTestToken(t, rsaKey, header{Typ: "JWT", Alg: AlgorithmRS256, Kid: "rsa"}, validClaims), ".")
+				claimsBytes, _ := json.Marshal(withClaims(func(c *Claims) { c.UserID = "admin" }))
+				return parts[0] + "." + base64UrlEncode(claimsBytes) + "." + parts[2]
+			},
+			wantErr: ErrTokenSignatureInvalid,
+		},
+		{
+			name: "Fail: expired",
+			token: func(t *testing.T) string {
+				return signTestToken(t, rsaKey, header{Typ: "JWT", Alg: AlgorithmRS256, Kid: "rsa"}, withClaims(func(c *Claims) {
+					c.ExpiresAt = now.Add(-time.Hour).Unix()
+				}))
+			},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "Fail: wrong issuer",
+			token: func(t *testing.T) string {
+				return signTestToken(t, rsaKey, header{Typ: "JWT", Alg: AlgorithmRS256, Kid: "rsa"}, withClaims(func(c *Claims) {
+					c.Issuer = "other"
+				}))
+			},
+			wantErr: ErrTokenIssuerInvalid,
+		},
+		{
+			name: "Fail: wrong audience",
+			token: func(t *testing.T) string {
+				return signTestToken(t, rsaKey, header{Typ: "JWT", Alg: AlgorithmRS256, Kid: "rsa"}, withClaims(func(c *Claims) {
+					c.Audience = Audience{"other", "another"}
+				}))
+			},
+			wantErr: ErrTokenAudienceInvalid,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := NewKeySet("rsa", keys, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			UseKeySet(ks)
+
+			claims, err := Verify(tt.token(t))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && claims.UserID != validClaims.UserID {
+				t.Errorf("Verify() claims = %v, want %v", claims, validClaims)
+			}
+		})
+	}
+}
+
+func Test_Audience(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name string
+		json string
+		want Audience
+	}{
+		{
+			name: "string",
+			json: `"api"`,
+			want: Audience{"api"},
+		},
+		{
+			name: "array",
+			json: `["api","other"]`,
+			want: Audience{"api", "other"},
+		},
 	}
-	wantPayload := Payload{JTI: jti, UserID: userID.String()}
-	if !reflect.DeepEqual(payload, wantPayload) {
-		t.Errorf("GetPayloadFromToken() \n got = %v,\n want = %v", payload, wantPayload)
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got Audience
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+			b, _ := json.Marshal(got)
+			if string(b) != tt.json {
+				t.Errorf("Marshal() = %s, want %s", b, tt.json)
+			}
+		})
 	}
 }
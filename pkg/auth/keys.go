@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const ephemeralKeyBits = 2048
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// 署名アルゴリズム(RFC 7518)
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// KeySet JWTの署名・検証に使う鍵の集合
+//
+// 署名にはactiveKeyIDの秘密鍵のみを使い、検証にはkidヘッダで指定された鍵を使う。
+// 鍵をローテーションする場合は以下の手順で行うことで、発行済みのトークンを無効にせずに切り替えられる。
+//  1. 新しい鍵を検証用の公開鍵として追加し、JWKSに公開する
+//  2. activeKeyIDを新しい鍵に切り替える
+//  3. 古い鍵で発行されたトークンの有効期限が切れた後に古い鍵を削除する
+//
+// 鍵はRSA(RS256)、P-256(ES256)、Ed25519(EdDSA)に対応し、アルゴリズムは鍵の種類から決まる。
+type KeySet struct {
+	activeKeyID string
+	privateKeys map[string]crypto.Signer
+	publicKeys  map[string]crypto.PublicKey
+}
+
+// NewKeySet privateKeysの公開鍵は自動的に検証用の鍵にも追加される
+func NewKeySet(activeKeyID string, privateKeys map[string]crypto.Signer, publicKeys map[string]crypto.PublicKey) (*KeySet, error) {
+	ks := &KeySet{
+		activeKeyID: activeKeyID,
+		privateKeys: make(map[string]crypto.Signer, len(privateKeys)),
+		publicKeys:  make(map[string]crypto.PublicKey, len(privateKeys)+len(publicKeys)),
+	}
+	for kid, key := range publicKeys {
+		if _, err := keyAlgorithm(key); err != nil {
+			return nil, fmt.Errorf("public key %q: %w", kid, err)
+		}
+		ks.publicKeys[kid] = key
+	}
+	for kid, key := range privateKeys {
+		if _, err := keyAlgorithm(key.Public()); err != nil {
+			return nil, fmt.Errorf("private key %q: %w", kid, err)
+		}
+		ks.privateKeys[kid] = key
+		ks.publicKeys[kid] = key.Public()
+	}
+	if _, ok := ks.privateKeys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q has no private key", ErrKeyNotFound, activeKeyID)
+	}
+	return ks, nil
+}
+
+// LoadKeySet kidとPEMファイルのパスの対応から鍵を読み込む
+func LoadKeySet(activeKeyID string, privateKeyFiles, publicKeyFiles map[string]string) (*KeySet, error) {
+	privateKeys := make(map[string]crypto.Signer, len(privateKeyFiles))
+	for kid, path := range privateKeyFiles {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key %q: %w", kid, err)
+		}
+		key, err := loadPrivateKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load private key %q: %w", kid, err)
+		}
+		privateKeys[kid] = key
+	}
+	publicKeys := make(map[string]crypto.PublicKey, len(publicKeyFiles))
+	for kid, path := range publicKeyFiles {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key %q: %w", kid, err)
+		}
+		key, err := loadPublicKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load public key %q: %w", kid, err)
+		}
+		publicKeys[kid] = key
+	}
+	return NewKeySet(activeKeyID, privateKeys, publicKeys)
+}
+
+// NewEphemeralKeySet 起動ごとに生成される一時的な鍵
+// 再起動すると発行済みのトークンは全て無効になるため、開発・テスト用途でのみ使う
+func NewEphemeralKeySet() (*KeySet, error) {
+	key, err := rsa.GenerateKey(rand.Reader, ephemeralKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	kid := keyThumbprint(&key.PublicKey)
+	return NewKeySet(kid, map[string]crypto.Signer{kid: key}, nil)
+}
+
+// keyAlgorithm 鍵の種類に対応する署名アルゴリズムを返す
+func keyAlgorithm(key crypto.PublicKey) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRS256, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported curve: %s", k.Curve.Params().Name)
+		}
+		return AlgorithmES256, nil
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA, nil
+	}
+	return "", fmt.Errorf("unsupported key type: %T", key)
+}
+
+func (ks *KeySet) ActiveKeyID() string {
+	return ks.activeKeyID
+}
+
+func (ks *KeySet) signingKey() (string, crypto.Signer) {
+	return ks.activeKeyID, ks.privateKeys[ks.activeKeyID]
+}
+
+// verificationKey kidが空の場合はkid導入前に発行されたトークンとしてactiveな鍵で検証する
+func (ks *KeySet) verificationKey(kid string) (crypto.PublicKey, error) {
+	if kid == "" {
+		kid = ks.activeKeyID
+	}
+	key, ok := ks.publicKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, kid)
+	}
+	return key, nil
+}
+
+// JWK RFC 7517のJSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 検証に使える全ての公開鍵をkidの順に返す
+func (ks *KeySet) JWKS() JWKS {
+	kids := make([]string, 0, len(ks.publicKeys))
+	for kid := range ks.publicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwks.Keys = append(jwks.Keys, newJWK(kid, ks.publicKeys[kid]))
+	}
+	return jwks
+}
+
+func newJWK(kid string, key crypto.PublicKey) JWK {
+	jwk := JWK{Kid: kid, Use: "sig"}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.Alg = AlgorithmRS256
+		jwk.N = base64UrlEncode(k.N.Bytes())
+		jwk.E = base64UrlEncode(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		jwk.Kty = "EC"
+		jwk.Alg = AlgorithmES256
+		jwk.Crv = k.Curve.Params().Name
+		jwk.X = base64UrlEncode(k.X.FillBytes(make([]byte, es256CoordinateSize)))
+		jwk.Y = base64UrlEncode(k.Y.FillBytes(make([]byte, es256CoordinateSize)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Alg = AlgorithmEdDSA
+		jwk.Crv = "Ed25519"
+		jwk.X = base64UrlEncode(k)
+	}
+	return jwk
+}
+
+func keyThumbprint(key crypto.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(key)
+	sum := sha256.Sum256(der)
+	return base64UrlEncode(sum[:8])
+}
+
+var (
+	currentKeySet    atomic.Pointer[KeySet]
+	ephemeralKeyOnce sync.Once
+)
+
+// UseKeySet トークンの発行・検証に使う鍵を差し替える
+// 実行中に呼び出しても処理中のリクエストには影響しない
+func UseKeySet(ks *KeySet) {
+	currentKeySet.Store(ks)
+}
+
+// CurrentKeySet UseKeySetが呼ばれていない場合は一時的な鍵を生成して使う
+func CurrentKeySet() *KeySet {
+	if ks := currentKeySet.Load(); ks != nil {
+		return ks
+	}
+	ephemeralKeyOnce.Do(func() {
+		ks, err := NewEphemeralKeySet()
+		if err != nil {
+			panic(err)
+		}
+		log.Warn("No JWT signing key configured, using an ephemeral key", log.Fstring("kid", ks.ActiveKeyID()))
+		currentKeySet.CompareAndSwap(nil, ks)
+	})
+	return currentKeySet.Load()
+}
+
+func loadPrivateKey(keyBytes []byte) (crypto.Signer, error) {
+	// PEMエンコードされたデータからPEMブロックをデコード
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block containing the key")
+	}
+
+	// PEMブロックから秘密鍵をパース
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("failed to decode PEM block containing the key")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+	return signer, nil
+}
+
+func loadPublicKey(keyBytes []byte) (crypto.PublicKey, error) {
+	// PEMエンコードされたデータからPEMブロックをデコード
+	block, _ := pem.Decode(keyBytes)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("failed to decode PEM block containing the key")
+	}
+
+	// PEMブロックから公開鍵をパース
+	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return pubKey, nil
+}
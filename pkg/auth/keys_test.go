@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestKeyFiles(t *testing.T, dir, kid string) (string, string, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, ephemeralKeyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privPath := filepath.Join(dir, kid+".pem")
+	pubPath := filepath.Join(dir, kid+".pub.pem")
+	if err = os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privPath, pubPath, key
+}
+
+func Test_LoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	oldPriv, oldPub, _ := writeTestKeyFiles(t, dir, "old")
+	newPriv, _, _ := writeTestKeyFiles(t, dir, "new")
+
+	patterns := []struct {
+		name        string
+		activeKeyID string
+		private     map[string]string
+		public      map[string]string
+		wantKids    []string
+		wantErr     bool
+	}{
+		{
+			name:        "success",
+			activeKeyID: "old",
+			private:     map[string]string{"old": oldPriv},
+			wantKids:    []string{"old"},
+		},
+		{
+			name:        "success: retired key is kept for verification",
+			activeKeyID: "new",
+			private:     map[string]string{"new": newPriv},
+			public:      map[string]string{"old": oldPub},
+			wantKids:    []string{"new", "old"},
+		},
+		{
+			name:        "Fail: active key has no private key",
+			activeKeyID: "old",
+			private:     map[string]string{"new": newPriv},
+			public:      map[string]string{"old": oldPub},
+			wantErr:     true,
+		},
+		{
+			name:        "Fail: file not found",
+			activeKeyID: "old",
+			private:     map[string]string{"old": filepath.Join(dir, "missing.pem")},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := LoadKeySet(tt.activeKeyID, tt.private, tt.public)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadKeySet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			jwks := ks.JWKS()
+			if len(jwks.Keys) != len(tt.wantKids) {
+				t.Fatalf("JWKS() keys = %v, want %v", jwks.Keys, tt.wantKids)
+			}
+			for i, kid := range tt.wantKids {
+				if jwks.Keys[i].Kid != kid || jwks.Keys[i].Kty != "RSA" || jwks.Keys[i].Alg != "RS256" {
+					t.Errorf("JWKS() key[%d] = %v, want kid %v", i, jwks.Keys[i], kid)
+				}
+			}
+		})
+	}
+}
+
+func Test_KeyRotation(t *testing.T) {
+	previous := CurrentKeySet()
+	t.Cleanup(func() { UseKeySet(previous) })
+
+	dir := t.TempDir()
+	oldPriv, oldPub, _ := writeTestKeyFiles(t, dir, "old")
+	newPriv, _, _ := writeTestKeyFiles(t, dir, "new")
+
+	oldKeys, err := LoadKeySet("old", map[string]string{"old": oldPriv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	UseKeySet(oldKeys)
+	jwt, _ := GenerateToken("user", "test@gmail.com", "player")
+
+	// 新しい鍵に切り替えても古い鍵で発行されたトークンは検証できる
+	rotated, err := LoadKeySet("new", map[string]string{"new": newPriv}, map[string]string{"old": oldPub})
+	if err != nil {
+		t.Fatal(err)
+	}
+	UseKeySet(rotated)
+	if _, err = Verify(jwt); err != nil {
+		t.Errorf("Verify() after rotation error = %v", err)
+	}
+	newJWT, _ := GenerateToken("user", "test@gmail.com", "player")
+	if _, err = Verify(newJWT); err != nil {
+		t.Errorf("Verify() with new key error = %v", err)
+	}
+
+	// 古い鍵を削除すると古いトークンは検証できない
+	retired, err := LoadKeySet("new", map[string]string{"new": newPriv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	UseKeySet(retired)
+	if _, err = Verify(jwt); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Verify() after retirement error = %v, want %v", err, ErrKeyNotFound)
+	}
+}
+
+func Test_KeySet_JWKS(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, ephemeralKeyBits)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	ks, err := NewKeySet("ec", map[string]crypto.Signer{"ec": ecKey, "ed": edKey}, map[string]crypto.PublicKey{"rsa": &rsaKey.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []JWK{
+		{
+			Kty: "EC", Kid: "ec", Use: "sig", Alg: AlgorithmES256, Crv: "P-256",
+			X: base64UrlEncode(ecKey.X.FillBytes(make([]byte, es256CoordinateSize))),
+			Y: base64UrlEncode(ecKey.Y.FillBytes(make([]byte, es256CoordinateSize))),
+		},
+		{Kty: "OKP", Kid: "ed", Use: "sig", Alg: AlgorithmEdDSA, Crv: "Ed25519", X: base64UrlEncode(edPub)},
+		{
+			Kty: "RSA", Kid: "rsa", Use: "sig", Alg: AlgorithmRS256,
+			N: base64UrlEncode(rsaKey.N.Bytes()), E: "AQAB",
+		},
+	}
+	if got := ks.JWKS().Keys; !reflect.DeepEqual(got, want) {
+		t.Errorf("JWKS() = %v, want %v", got, want)
+	}
+
+	// P-256以外の曲線はES256で使えない
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if _, err = NewKeySet("ec", map[string]crypto.Signer{"ec": p384Key}, nil); err == nil {
+		t.Errorf("NewKeySet() with P-384 key should fail")
+	}
+}
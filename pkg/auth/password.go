@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PasswordHasher パスワードのハッシュ化と検証を行う
+//
+// Hashが返す値にはアルゴリズムとパラメータがエンコードされているため、
+// パラメータを変更した後も既存のハッシュを検証でき、NeedsRehashで再ハッシュが必要か判定できる。
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+type HashAlgorithm string
+
+const (
+	HashAlgorithmBcrypt   HashAlgorithm = "bcrypt"
+	HashAlgorithmArgon2id HashAlgorithm = "argon2id"
+	HashAlgorithmScrypt   HashAlgorithm = "scrypt"
+)
+
+const (
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+type PasswordHashParams struct {
+	Algorithm     HashAlgorithm
+	BcryptCost    int
+	Argon2Time    uint32
+	Argon2Memory  uint32 // KiB
+	Argon2Threads uint8
+	ScryptLogN    uint8
+	ScryptR       int
+	ScryptP       int
+}
+
+// DefaultPasswordHashParams OWASP Password Storage Cheat Sheetの推奨値
+func DefaultPasswordHashParams() PasswordHashParams {
+	return PasswordHashParams{
+		Algorithm:     HashAlgorithmArgon2id,
+		BcryptCost:    12,    //nolint:gomnd // recommended cost
+		Argon2Time:    2,     //nolint:gomnd // recommended iterations
+		Argon2Memory:  19456, //nolint:gomnd // 19 MiB
+		Argon2Threads: 1,
+		ScryptLogN:    17, //nolint:gomnd // N=2^17
+		ScryptR:       8,  //nolint:gomnd // recommended block size
+		ScryptP:       1,
+	}
+}
+
+// hashScheme アルゴリズムごとのエンコード形式を扱う
+type hashScheme interface {
+	hash(password []byte) (string, error)
+	verify(password []byte, encoded string) (bool, error)
+	// sameParams encodedが現在のパラメータでハッシュ化されていればtrue
+	sameParams(encoded string) bool
+}
+
+type passwordHasher struct {
+	algorithm HashAlgorithm
+	schemes   map[HashAlgorithm]hashScheme
+}
+
+func NewPasswordHasher(params PasswordHashParams) (PasswordHasher, error) {
+	schemes := map[HashAlgorithm]hashScheme{
+		HashAlgorithmBcrypt:   &bcryptScheme{cost: params.BcryptCost},
+		HashAlgorithmArgon2id: &argon2idScheme{time: params.Argon2Time, memory: params.Argon2Memory, threads: params.Argon2Threads},
+		HashAlgorithmScrypt:   &scryptScheme{logN: params.ScryptLogN, r: params.ScryptR, p: params.ScryptP},
+	}
+	if _, ok := schemes[params.Algorithm]; !ok {
+		return nil, fmt.Errorf("unsupported password hash algorithm: %q", params.Algorithm)
+	}
+	if err := validatePasswordHashParams(params); err != nil {
+		return nil, err
+	}
+	return &passwordHasher{
+		algorithm: params.Algorithm,
+		schemes:   schemes,
+	}, nil
+}
+
+func validatePasswordHashParams(params PasswordHashParams) error {
+	switch params.Algorithm {
+	case HashAlgorithmBcrypt:
+		if params.BcryptCost < bcryptMinCost || params.BcryptCost > bcryptMaxCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", bcryptMinCost, bcryptMaxCost)
+		}
+	case HashAlgorithmArgon2id:
+		if params.Argon2Time < 1 || params.Argon2Memory < 1 || params.Argon2Threads < 1 {
+			return fmt.Errorf("argon2id time, memory and threads must be positive")
+		}
+	case HashAlgorithmScrypt:
+		if params.ScryptLogN < 1 || params.ScryptLogN > scryptMaxLogN || params.ScryptR < 1 || params.ScryptP < 1 {
+			return fmt.Errorf("scrypt parameters are invalid")
+		}
+	}
+	return nil
+}
+
+func (ph *passwordHasher) Hash(password string) (string, error) {
+	return ph.schemes[ph.algorithm].hash([]byte(password))
+}
+
+func (ph *passwordHasher) Verify(password, encoded string) (bool, error) {
+	alg, ok := detectHashAlgorithm(encoded)
+	if !ok {
+		if strings.HasPrefix(encoded, "$") {
+			return false, ErrUnknownHashFormat
+		}
+		// ハッシュ化導入前に平文で保存された値
+		return subtle.ConstantTimeCompare([]byte(password), []byte(encoded)) == 1, nil
+	}
+	return ph.schemes[alg].verify([]byte(password), encoded)
+}
+
+func (ph *passwordHasher) NeedsRehash(encoded string) bool {
+	alg, ok := detectHashAlgorithm(encoded)
+	if !ok || alg != ph.algorithm {
+		return true
+	}
+	return !ph.schemes[alg].sameParams(encoded)
+}
+
+func detectHashAlgorithm(encoded string) (HashAlgorithm, bool) {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return HashAlgorithmBcrypt, true
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return HashAlgorithmArgon2id, true
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		return HashAlgorithmScrypt, true
+	}
+	return "", false
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+// splitPHC "$alg$...$salt$hash"形式の文字列を分割する
+func splitPHC(encoded string, parts int) ([]string, error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != parts+1 || fields[0] != "" {
+		return nil, ErrUnknownHashFormat
+	}
+	return fields[1:], nil
+}
+
+func decodeSaltAndKey(salt, key string) ([]byte, []byte, error) {
+	decodedSalt, err := base64.RawStdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding salt failed: %w", err)
+	}
+	decodedKey, err := base64.RawStdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding hash failed: %w", err)
+	}
+	return decodedSalt, decodedKey, nil
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	bcryptMinCost = bcrypt.MinCost
+	bcryptMaxCost = bcrypt.MaxCost
+	scryptMaxLogN = 30
+)
+
+type bcryptScheme struct {
+	cost int
+}
+
+func (s *bcryptScheme) hash(password []byte) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword(password, s.cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt hashing failed: %w", err)
+	}
+	return string(hashed), nil
+}
+
+func (s *bcryptScheme) verify(password []byte, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), password)
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("bcrypt verification failed: %w", err)
+	}
+	return true, nil
+}
+
+func (s *bcryptScheme) sameParams(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err == nil && cost == s.cost
+}
+
+// argon2idScheme $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>
+type argon2idScheme struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+const argon2idFields = 5
+
+func (s *argon2idScheme) hash(password []byte) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey(password, salt, s.time, s.memory, s.threads, passwordKeyLength)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, s.memory, s.time, s.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (s *argon2idScheme) decode(encoded string) (*argon2idScheme, []byte, []byte, error) {
+	fields, err := splitPHC(encoded, argon2idFields)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var version int
+	if _, err = fmt.Sscanf(fields[1], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	var params argon2idScheme
+	if _, err = fmt.Sscanf(fields[2], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	salt, key, err := decodeSaltAndKey(fields[3], fields[4])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &params, salt, key, nil
+}
+
+func (s *argon2idScheme) verify(password []byte, encoded string) (bool, error) {
+	params, salt, key, err := s.decode(encoded)
+	if err != nil {
+		return false, err
+	}
+	//nolint:gosec // key length is bounded by the stored hash
+	got := argon2.IDKey(password, salt, params.time, params.memory, params.threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(got, key) == 1, nil
+}
+
+func (s *argon2idScheme) sameParams(encoded string) bool {
+	params, _, key, err := s.decode(encoded)
+	if err != nil {
+		return false
+	}
+	return *params == *s && len(key) == passwordKeyLength
+}
+
+// scryptScheme $scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<hash>
+type scryptScheme struct {
+	logN uint8
+	r    int
+	p    int
+}
+
+const scryptFields = 4
+
+func (s *scryptScheme) hash(password []byte) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key(password, salt, 1<<s.logN, s.r, s.p, passwordKeyLength)
+	if err != nil {
+		return "", fmt.Errorf("scrypt hashing failed: %w", err)
+	}
+	return fmt.Sprintf(
+		"$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		s.logN, s.r, s.p,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (s *scryptScheme) decode(encoded string) (*scryptScheme, []byte, []byte, error) {
+	fields, err := splitPHC(encoded, scryptFields)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var params scryptScheme
+	if _, err = fmt.Sscanf(fields[1], "ln=%d,r=%d,p=%d", &params.logN, &params.r, &params.p); err != nil {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	if params.logN < 1 || params.logN > scryptMaxLogN {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	salt, key, err := decodeSaltAndKey(fields[2], fields[3])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return &params, salt, key, nil
+}
+
+func (s *scryptScheme) verify(password []byte, encoded string) (bool, error) {
+	params, salt, key, err := s.decode(encoded)
+	if err != nil {
+		return false, err
+	}
+	got, err := scrypt.Key(password, salt, 1<<params.logN, params.r, params.p, len(key))
+	if err != nil {
+		return false, fmt.Errorf("scrypt verification failed: %w", err)
+	}
+	return subtle.ConstantTimeCompare(got, key) == 1, nil
+}
+
+func (s *scryptScheme) sameParams(encoded string) bool {
+	params, _, key, err := s.decode(encoded)
+	if err != nil {
+		return false
+	}
+	return *params == *s && len(key) == passwordKeyLength
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func testPasswordHashParams(alg HashAlgorithm) PasswordHashParams {
+	return PasswordHashParams{
+		Algorithm:     alg,
+		BcryptCost:    bcryptMinCost,
+		Argon2Time:    1,
+		Argon2Memory:  64,
+		Argon2Threads: 1,
+		ScryptLogN:    4,
+		ScryptR:       8,
+		ScryptP:       1,
+	}
+}
+
+func TestPasswordHasher_HashAndVerify(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name       string
+		algorithm  HashAlgorithm
+		wantPrefix string
+	}{
+		{name: "bcrypt", algorithm: HashAlgorithmBcrypt, wantPrefix: "$2a$"},
+		{name: "argon2id", algorithm: HashAlgorithmArgon2id, wantPrefix: "$argon2id$v=19$m=64,t=1,p=1$"},
+		{name: "scrypt", algorithm: HashAlgorithmScrypt, wantPrefix: "$scrypt$ln=4,r=8,p=1$"},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ph, err := NewPasswordHasher(testPasswordHashParams(tt.algorithm))
+			if err != nil {
+				t.Fatalf("NewPasswordHasher() error = %v", err)
+			}
+
+			encoded, err := ph.Hash("password123")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.wantPrefix) {
+				t.Errorf("Hash() = %v, want prefix %v", encoded, tt.wantPrefix)
+			}
+
+			if ok, err := ph.Verify("password123", encoded); err != nil || !ok {
+				t.Errorf("Verify() with correct password = %v, %v", ok, err)
+			}
+			if ok, err := ph.Verify("wrong", encoded); err != nil || ok {
+				t.Errorf("Verify() with wrong password = %v, %v", ok, err)
+			}
+			if ph.NeedsRehash(encoded) {
+				t.Errorf("NeedsRehash() = true for a hash with current params")
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	t.Parallel()
+
+	current, err := NewPasswordHasher(testPasswordHashParams(HashAlgorithmArgon2id))
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+
+	oldParams := testPasswordHashParams(HashAlgorithmArgon2id)
+	oldParams.Argon2Memory = 32
+	old, _ := NewPasswordHasher(oldParams)
+	oldArgon2, _ := old.Hash("password123")
+
+	bcryptHasher, _ := NewPasswordHasher(testPasswordHashParams(HashAlgorithmBcrypt))
+	oldBcrypt, _ := bcryptHasher.Hash("password123")
+
+	patterns := []struct {
+		name    string
+		encoded string
+		want    bool
+	}{
+		{name: "legacy plaintext", encoded: "password123", want: true},
+		{name: "other algorithm", encoded: oldBcrypt, want: true},
+		{name: "old params", encoded: oldArgon2, want: true},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// パラメータ変更前のハッシュも検証できること
+			if ok, err := current.Verify("password123", tt.encoded); err != nil || !ok {
+				t.Errorf("Verify() = %v, %v", ok, err)
+			}
+			if got := current.NeedsRehash(tt.encoded); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_VerifyUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	ph, _ := NewPasswordHasher(testPasswordHashParams(HashAlgorithmBcrypt))
+	if _, err := ph.Verify("password123", "$md5$abc"); err == nil {
+		t.Errorf("Verify() error = nil, want %v", ErrUnknownHashFormat)
+	}
+}
+
+func TestNewPasswordHasher_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	params := testPasswordHashParams("md5")
+	if _, err := NewPasswordHasher(params); err == nil {
+		t.Errorf("NewPasswordHasher() error = nil for unsupported algorithm")
+	}
+
+	params = testPasswordHashParams(HashAlgorithmBcrypt)
+	params.BcryptCost = 1
+	if _, err := NewPasswordHasher(params); err == nil {
+		t.Errorf("NewPasswordHasher() error = nil for invalid bcrypt cost")
+	}
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// RefreshTokenLifetime リフレッシュトークンの有効期間
+	RefreshTokenLifetime = 30 * 24 * time.Hour
+	refreshTokenBytes    = 32
+)
+
+// GenerateRefreshToken クライアントに渡すリフレッシュトークンと、保存用のハッシュを生成する
+func GenerateRefreshToken() (string, string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token := base64UrlEncode(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken リフレッシュトークンは平文で保存せずSHA-256のハッシュで照合する
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// es256CoordinateSize P-256の座標と署名のr,sのバイト長
+const es256CoordinateSize = 32
+
+var errSignatureMismatch = errors.New("signature mismatch")
+
+// sign JWSの署名を作成する
+// ES256の署名はASN.1ではなくr||sの固定長で表す(RFC 7518 3.4)
+func sign(alg string, key crypto.Signer, signingInput []byte) ([]byte, error) {
+	switch alg {
+	case AlgorithmRS256:
+		hashed := sha256.Sum256(signingInput)
+		return key.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	case AlgorithmES256:
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("not ECDSA private key")
+		}
+		hashed := sha256.Sum256(signingInput)
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, hashed[:])
+		if err != nil {
+			return nil, err
+		}
+		signature := make([]byte, 2*es256CoordinateSize)
+		r.FillBytes(signature[:es256CoordinateSize])
+		s.FillBytes(signature[es256CoordinateSize:])
+		return signature, nil
+	case AlgorithmEdDSA:
+		return key.Sign(rand.Reader, signingInput, crypto.Hash(0))
+	}
+	return nil, fmt.Errorf("unsupported algorithm: %q", alg)
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput, signature []byte) error {
+	switch alg {
+	case AlgorithmRS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("not RSA public key")
+		}
+		hashed := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, hashed[:], signature)
+	case AlgorithmES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("not ECDSA public key")
+		}
+		if len(signature) != 2*es256CoordinateSize {
+			return errSignatureMismatch
+		}
+		r := new(big.Int).SetBytes(signature[:es256CoordinateSize])
+		s := new(big.Int).SetBytes(signature[es256CoordinateSize:])
+		hashed := sha256.Sum256(signingInput)
+		if !ecdsa.Verify(ecKey, hashed[:], r, s) {
+			return errSignatureMismatch
+		}
+		return nil
+	case AlgorithmEdDSA:
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("not Ed25519 public key")
+		}
+		if !ed25519.Verify(edKey, signingInput, signature) {
+			return errSignatureMismatch
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm: %q", alg)
+}
@@ -0,0 +1,245 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const AuditLogListLimit = 100
+
+// UserStatsUpdate 管理者が変更する通貨ごとの残高とハイスコア
+// nilの項目、Balancesに含まれない通貨は変更しない
+type UserStatsUpdate struct {
+	// Balances 変更後の残高。差分を台帳に記録する
+	Balances  map[model.Currency]int
+	HighScore *int
+	// Note 変更理由。監査ログに記録される
+	Note string
+}
+
+type AdminUseCase interface {
+	// UpdateUserStats 変更内容を操作した管理者とともに監査ログに記録する
+	UpdateUserStats(ctx context.Context, userID string, update UserStatsUpdate) (*UserDetail, error)
+	// UpdateUserRole 役割が変わった場合はユーザの全てのセッションを失効させ、再ログイン後のトークンから変更後の役割を反映する
+	UpdateUserRole(ctx context.Context, userID string, role model.Role, note string) (*model.User, error)
+	ListAuditLogs(ctx context.Context, targetID string) ([]*model.AdminAuditLog, error)
+	// ListGachaDraws 問い合わせ対応のため、全ユーザの[from, to)の抽選の記録を新しい順に返す
+	ListGachaDraws(ctx context.Context, from, to time.Time, cursor string, limit int) ([]*model.GachaDrawRecord, string, error)
+}
+
+type adminUseCase struct {
+	tr   repository.TransactionRepository
+	ur   repository.UserRepository
+	ctr  repository.CoinTransactionRepository
+	wr   repository.WalletRepository
+	aalr repository.AdminAuditLogRepository
+	gdr  repository.GachaDrawRepository
+	rtr  repository.RefreshTokenRepository
+	trr  repository.TokenRevocationRepository
+}
+
+func NewAdminUseCase(
+	tr repository.TransactionRepository,
+	ur repository.UserRepository,
+	ctr repository.CoinTransactionRepository,
+	wr repository.WalletRepository,
+	aalr repository.AdminAuditLogRepository,
+	gdr repository.GachaDrawRepository,
+	rtr repository.RefreshTokenRepository,
+	trr repository.TokenRevocationRepository,
+) AdminUseCase {
+	return &adminUseCase{
+		tr:   tr,
+		ur:   ur,
+		ctr:  ctr,
+		wr:   wr,
+		aalr: aalr,
+		gdr:  gdr,
+		rtr:  rtr,
+		trr:  trr,
+	}
+}
+
+func (auc *adminUseCase) UpdateUserStats(ctx context.Context, userID string, update UserStatsUpdate) (*UserDetail, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
+	}
+	actorID := principal.UserID
+
+	var user *model.User
+	var wallet model.Wallet
+	if err := auc.tr.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = auc.ur.GetForUpdate(ctx, userID)
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+		if wallet, err = auc.wr.Get(ctx, user.ID); err != nil {
+			log.Error("Error getting wallet", log.Fstring("user_id", userID))
+			return err
+		}
+
+		changes := map[string]model.AuditChange{}
+		deltas := make(map[model.Currency]int, len(update.Balances))
+		for _, currency := range model.Currencies {
+			balance, ok := update.Balances[currency]
+			if !ok || balance == wallet[currency] {
+				continue
+			}
+			changes["balances."+string(currency)] = model.AuditChange{Before: wallet[currency], After: balance}
+			deltas[currency] = balance - wallet[currency]
+		}
+		if update.HighScore != nil && *update.HighScore != user.HighScore {
+			changes["high_score"] = model.AuditChange{Before: user.HighScore, After: *update.HighScore}
+			user.HighScore = *update.HighScore
+		}
+
+		auditLog, err := model.NewAdminAuditLog(actorID, model.AuditActionUpdateUserStats, user.ID, changes, update.Note)
+		if err != nil {
+			log.Error("Failed to create audit log", log.Ferror(err))
+			return err
+		}
+		if err = auc.ur.Update(ctx, *user); err != nil {
+			log.Error("Error updating user", log.Fstring("user_id", userID))
+			return err
+		}
+		for _, currency := range model.Currencies {
+			if err = adjustWallet(
+				ctx, auc.wr, auc.ctr, wallet, user.ID, currency, model.CoinReasonAdminAdjustment, deltas[currency], auditLog.ID,
+			); err != nil {
+				return err
+			}
+		}
+		// 変更がなかった場合も、操作が行われたこと自体を記録する
+		if err = auc.aalr.Create(ctx, *auditLog); err != nil {
+			log.Error("Failed to create audit log", log.Ferror(err))
+			return err
+		}
+		log.Info(
+			"User stats updated by admin",
+			log.Fstring("actor_id", actorID),
+			log.Fstring("user_id", user.ID),
+			log.Fstring("audit_log_id", auditLog.ID),
+		)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &UserDetail{User: user, Wallet: wallet}, nil
+}
+
+func (auc *adminUseCase) UpdateUserRole(ctx context.Context, userID string, role model.Role, note string) (*model.User, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
+	}
+	actorID := principal.UserID
+	if actorID == userID {
+		log.Warn("Admin tried to change own role", log.Fstring("actor_id", actorID))
+		return nil, model.ErrOwnRoleChange
+	}
+
+	var user *model.User
+	if err := auc.tr.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = auc.ur.GetForUpdate(ctx, userID)
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+
+		changes := map[string]model.AuditChange{}
+		roleChanged := user.Role != role
+		if roleChanged {
+			changes["role"] = model.AuditChange{Before: user.Role, After: role}
+			user.Role = role
+		}
+		auditLog, err := model.NewAdminAuditLog(actorID, model.AuditActionUpdateUserRole, user.ID, changes, note)
+		if err != nil {
+			log.Error("Failed to create audit log", log.Ferror(err))
+			return err
+		}
+		if err = auc.ur.Update(ctx, *user); err != nil {
+			log.Error("Error updating user", log.Fstring("user_id", userID))
+			return err
+		}
+		if err = auc.aalr.Create(ctx, *auditLog); err != nil {
+			log.Error("Failed to create audit log", log.Ferror(err))
+			return err
+		}
+		if roleChanged {
+			// 変更前の役割を含むトークンを使えなくする。失効に失敗した場合は役割の変更もロールバックする
+			if err = auc.revokeSessions(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+		log.Info(
+			"User role updated by admin",
+			log.Fstring("actor_id", actorID),
+			log.Fstring("user_id", user.ID),
+			log.Fstring("role", string(role)),
+		)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// revokeSessions ユーザの発行済みのアクセストークンとリフレッシュトークンを全て失効させる
+func (auc *adminUseCase) revokeSessions(ctx context.Context, userID string) error {
+	now := time.Now()
+	if err := auc.rtr.RevokeAllForUser(ctx, userID, now); err != nil {
+		log.Error("Error revoking refresh tokens", log.Fstring("user_id", userID))
+		return err
+	}
+	if err := auc.trr.RevokeAllForUser(ctx, userID, now, auth.AccessTokenLifetime+auth.ClockSkewLeeway); err != nil {
+		log.Error("Error revoking access tokens", log.Fstring("user_id", userID))
+		return err
+	}
+	return nil
+}
+
+func (auc *adminUseCase) ListAuditLogs(ctx context.Context, targetID string) ([]*model.AdminAuditLog, error) {
+	auditLogs, err := auc.aalr.ListByTarget(ctx, targetID, AuditLogListLimit)
+	if err != nil {
+		log.Error("Failed to list audit logs", log.Fstring("target_id", targetID), log.Ferror(err))
+		return nil, err
+	}
+	return auditLogs, nil
+}
+
+func (auc *adminUseCase) ListGachaDraws(
+	ctx context.Context,
+	from, to time.Time,
+	cursor string,
+	limit int,
+) ([]*model.GachaDrawRecord, string, error) {
+	if !from.Before(to) {
+		log.Info("Invalid period", log.Fstring("from", from.String()), log.Fstring("to", to.String()))
+		return nil, "", model.ErrInvalidPeriod
+	}
+	after, limit, err := parseGachaDrawPage(cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	records, err := auc.gdr.ListByPeriod(ctx, from, to, after, limit+1)
+	if err != nil {
+		log.Error("Failed to list gacha draws", log.Ferror(err))
+		return nil, "", err
+	}
+	records, next := nextGachaDrawPage(records, limit)
+	return records, next, nil
+}
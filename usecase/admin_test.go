@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
+)
+
+func TestAdminUseCase_UpdateUserStats(t *testing.T) {
+	t.Parallel()
+
+	actorID := uuid.New().String()
+	userID := uuid.New().String()
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: actorID, Role: model.RoleAdmin})
+
+	user := model.User{
+		ID:        userID,
+		Name:      "test",
+		Email:     "test@gmail.com",
+		HighScore: 1000,
+	}
+	highScore := 1000
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockTransactionRepository,
+			m1 *mock.MockUserRepository,
+			m2 *mock.MockCoinTransactionRepository,
+			m3 *mock.MockWalletRepository,
+			m4 *mock.MockAdminAuditLogRepository,
+		)
+		arg struct {
+			ctx    context.Context
+			userID string
+			update UserStatsUpdate
+		}
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				aalr *mock.MockAdminAuditLogRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				current := user
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(&current, nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 100, model.CurrencyGem: 30}, nil)
+				ur.EXPECT().Update(gomock.Any(), user).Return(nil)
+				wr.EXPECT().Credit(gomock.Any(), userID, model.CurrencyCoin, 50).Return(nil)
+				ctr.EXPECT().Create(gomock.Any(), coinTransactionOf(model.CurrencyCoin, model.CoinReasonAdminAdjustment, 50, 150)).Return(nil)
+				wr.EXPECT().Debit(gomock.Any(), userID, model.CurrencyGem, 30).Return(nil)
+				ctr.EXPECT().Create(gomock.Any(), coinTransactionOf(model.CurrencyGem, model.CoinReasonAdminAdjustment, -30, 0)).Return(nil)
+				aalr.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, auditLog model.AdminAuditLog) error {
+					// 値が変わらなかったハイスコアと欠片は記録しない
+					want := map[string]model.AuditChange{
+						"balances.coin": {Before: 100, After: 150},
+						"balances.gem":  {Before: 30, After: 0},
+					}
+					if auditLog.ActorID != actorID || auditLog.TargetID != userID || !reflect.DeepEqual(auditLog.Changes, want) {
+						t.Errorf("audit log = %+v", auditLog)
+					}
+					return nil
+				})
+			},
+			arg: struct {
+				ctx    context.Context
+				userID string
+				update UserStatsUpdate
+			}{
+				ctx:    ctx,
+				userID: userID,
+				update: UserStatsUpdate{
+					Balances:  map[model.Currency]int{model.CurrencyCoin: 150, model.CurrencyGem: 0, model.CurrencyShard: 0},
+					HighScore: &highScore,
+					Note:      "compensation",
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: user not found",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				aalr *mock.MockAdminAuditLogRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(nil, config.ErrNotFound)
+			},
+			arg: struct {
+				ctx    context.Context
+				userID string
+				update UserStatsUpdate
+			}{
+				ctx:    ctx,
+				userID: userID,
+				update: UserStatsUpdate{Balances: map[model.Currency]int{model.CurrencyCoin: 150}, Note: "compensation"},
+			},
+			wantErr: config.ErrNotFound,
+		},
+		{
+			name: "Fail: User ID not found in request context",
+			arg: struct {
+				ctx    context.Context
+				userID string
+				update UserStatsUpdate
+			}{
+				ctx:    context.Background(),
+				userID: userID,
+				update: UserStatsUpdate{Balances: map[model.Currency]int{model.CurrencyCoin: 150}, Note: "compensation"},
+			},
+			wantErr: fmt.Errorf("user name not found in request context"),
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ur := mock.NewMockUserRepository(ctrl)
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
+			aalr := mock.NewMockAdminAuditLogRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(tr, ur, ctr, wr, aalr)
+			}
+
+			usecase := NewAdminUseCase(tr, ur, ctr, wr, aalr, mock.NewMockGachaDrawRepository(ctrl), nil, nil)
+			_, err := usecase.UpdateUserStats(tt.arg.ctx, tt.arg.userID, tt.arg.update)
+
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("UpdateUserStats() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
+				t.Errorf("UpdateUserStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdminUseCase_UpdateUserRole(t *testing.T) {
+	t.Parallel()
+
+	actorID := uuid.New().String()
+	userID := uuid.New().String()
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: actorID, Role: model.RoleAdmin})
+
+	user := model.User{ID: userID, Name: "test", Role: model.RolePlayer}
+	errRevoke := errors.New("redis down")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockTransactionRepository,
+			m1 *mock.MockUserRepository,
+			m2 *mock.MockAdminAuditLogRepository,
+			m3 *mock.MockRefreshTokenRepository,
+			m4 *mock.MockTokenRevocationRepository,
+		)
+		userID  string
+		role    model.Role
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				aalr *mock.MockAdminAuditLogRepository,
+				rtr *mock.MockRefreshTokenRepository,
+				trr *mock.MockTokenRevocationRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				current := user
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(&current, nil)
+				updated := user
+				updated.Role = model.RoleOperator
+				ur.EXPECT().Update(gomock.Any(), updated).Return(nil)
+				aalr.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, auditLog model.AdminAuditLog) error {
+					want := map[string]model.AuditChange{"role": {Before: model.RolePlayer, After: model.RoleOperator}}
+					if auditLog.Action != model.AuditActionUpdateUserRole || !reflect.DeepEqual(auditLog.Changes, want) {
+						t.Errorf("audit log = %+v", auditLog)
+					}
+					return nil
+				})
+				rtr.EXPECT().RevokeAllForUser(gomock.Any(), userID, gomock.Any()).Return(nil)
+				trr.EXPECT().RevokeAllForUser(gomock.Any(), userID, gomock.Any(), auth.AccessTokenLifetime+auth.ClockSkewLeeway).Return(nil)
+			},
+			userID: userID,
+			role:   model.RoleOperator,
+		},
+		{
+			name: "success: same role does not revoke sessions",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				aalr *mock.MockAdminAuditLogRepository,
+				_ *mock.MockRefreshTokenRepository,
+				_ *mock.MockTokenRevocationRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				current := user
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(&current, nil)
+				ur.EXPECT().Update(gomock.Any(), user).Return(nil)
+				aalr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			userID: userID,
+			role:   model.RolePlayer,
+		},
+		{
+			name: "Fail: revoking access tokens",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				aalr *mock.MockAdminAuditLogRepository,
+				rtr *mock.MockRefreshTokenRepository,
+				trr *mock.MockTokenRevocationRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				current := user
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(&current, nil)
+				ur.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				aalr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				rtr.EXPECT().RevokeAllForUser(gomock.Any(), userID, gomock.Any()).Return(nil)
+				trr.EXPECT().RevokeAllForUser(gomock.Any(), userID, gomock.Any(), gomock.Any()).Return(errRevoke)
+			},
+			userID:  userID,
+			role:    model.RoleOperator,
+			wantErr: errRevoke,
+		},
+		{
+			name: "Fail: user not found",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				_ *mock.MockAdminAuditLogRepository,
+				_ *mock.MockRefreshTokenRepository,
+				_ *mock.MockTokenRevocationRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(nil, config.ErrNotFound)
+			},
+			userID:  userID,
+			role:    model.RoleOperator,
+			wantErr: config.ErrNotFound,
+		},
+		{
+			name:    "Fail: own role",
+			userID:  actorID,
+			role:    model.RoleOperator,
+			wantErr: model.ErrOwnRoleChange,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ur := mock.NewMockUserRepository(ctrl)
+			aalr := mock.NewMockAdminAuditLogRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			trr := mock.NewMockTokenRevocationRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(tr, ur, aalr, rtr, trr)
+			}
+
+			auc := NewAdminUseCase(tr, ur, mock.NewMockCoinTransactionRepository(ctrl), mock.NewMockWalletRepository(ctrl), aalr, mock.NewMockGachaDrawRepository(ctrl), rtr, trr)
+			got, err := auc.UpdateUserRole(ctx, tt.userID, tt.role, "support team")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUserRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Role != tt.role {
+				t.Errorf("UpdateUserRole() role = %v, want %v", got.Role, tt.role)
+			}
+		})
+	}
+}
+
+func TestAdminUseCase_ListGachaDraws(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	records := []*model.GachaDrawRecord{
+		{DrawID: "2", Slot: 0, UserID: "user2", CreatedAt: from.Add(2 * time.Hour)},
+		{DrawID: "1", Slot: 0, UserID: "user1", CreatedAt: from.Add(time.Hour)},
+	}
+	cursor := model.GachaDrawCursor{CreatedAt: records[0].CreatedAt, DrawID: records[0].DrawID, Slot: records[0].Slot}
+
+	patterns := []struct {
+		name           string
+		setup          func(m *mock.MockGachaDrawRepository)
+		from, to       time.Time
+		limit          int
+		wantLen        int
+		wantNextCursor string
+		wantErr        error
+	}{
+		{
+			name: "success: has next page",
+			setup: func(m *mock.MockGachaDrawRepository) {
+				m.EXPECT().ListByPeriod(gomock.Any(), from, to, nil, 2).Return(records, nil)
+			},
+			from:           from,
+			to:             to,
+			limit:          1,
+			wantLen:        1,
+			wantNextCursor: cursor.Encode(),
+		},
+		{
+			name: "success: default limit",
+			setup: func(m *mock.MockGachaDrawRepository) {
+				m.EXPECT().ListByPeriod(gomock.Any(), from, to, nil, DefaultGachaHistoryLimit+1).Return(records, nil)
+			},
+			from:    from,
+			to:      to,
+			wantLen: 2,
+		},
+		{
+			name:    "Fail: from is not before to",
+			from:    to,
+			to:      from,
+			wantErr: model.ErrInvalidPeriod,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			gdr := mock.NewMockGachaDrawRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(gdr)
+			}
+
+			auc := NewAdminUseCase(nil, nil, nil, nil, nil, gdr, nil, nil)
+			got, nextCursor, err := auc.ListGachaDraws(context.Background(), tt.from, tt.to, "", tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListGachaDraws() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("ListGachaDraws() len = %v, want %v", len(got), tt.wantLen)
+			}
+			if nextCursor != tt.wantNextCursor {
+				t.Errorf("ListGachaDraws() nextCursor = %v, want %v", nextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
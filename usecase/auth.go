@@ -0,0 +1,194 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int
+}
+
+type AuthUseCase interface {
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context) error
+}
+
+type authUseCase struct {
+	tr  repository.TransactionRepository
+	ur  repository.UserRepository
+	rtr repository.RefreshTokenRepository
+	trr repository.TokenRevocationRepository
+}
+
+func NewAuthUseCase(
+	tr repository.TransactionRepository,
+	ur repository.UserRepository,
+	rtr repository.RefreshTokenRepository,
+	trr repository.TokenRevocationRepository,
+) AuthUseCase {
+	return &authUseCase{
+		tr:  tr,
+		ur:  ur,
+		rtr: rtr,
+		trr: trr,
+	}
+}
+
+func (auc *authUseCase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	var pair *TokenPair
+	var reused bool
+	if err := auc.tr.Transaction(ctx, func(ctx context.Context) error {
+		token, err := auc.rtr.GetForUpdate(ctx, auth.HashRefreshToken(refreshToken))
+		if errors.Is(err, config.ErrNotFound) {
+			log.Info("Refresh token not found")
+			return ErrInvalidRefreshToken
+		} else if err != nil {
+			log.Error("Error getting refresh token", log.Ferror(err))
+			return err
+		}
+
+		now := time.Now()
+		if token.IsRotated() {
+			// ローテーション済みのトークンが再度使われた場合は漏洩とみなしてファミリーごと失効させる
+			log.Warn("Refresh token reuse detected", log.Fstring("user_id", token.UserID), log.Fstring("family_id", token.FamilyID))
+			if err = auc.rtr.RevokeFamily(ctx, token.FamilyID, now); err != nil {
+				log.Error("Error revoking refresh token family", log.Ferror(err))
+				return err
+			}
+			reused = true
+			return nil
+		}
+		if !token.IsActive(now) {
+			log.Info("Refresh token is expired or revoked", log.Fstring("user_id", token.UserID))
+			return ErrInvalidRefreshToken
+		}
+
+		user, err := auc.ur.Get(ctx, token.UserID)
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", token.UserID))
+			return err
+		}
+
+		token.Rotate(now)
+		if err = auc.rtr.Update(ctx, *token); err != nil {
+			log.Error("Error rotating refresh token", log.Ferror(err))
+			return err
+		}
+
+		pair, err = issueTokenPair(ctx, auc.rtr, user, token.FamilyID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	return pair, nil
+}
+
+// Logout リクエストに使われたアクセストークンを失効させる
+// refreshTokenが指定された場合は同じログインから発行されたリフレッシュトークンも失効させる
+func (auc *authUseCase) Logout(ctx context.Context, refreshToken string) error {
+	claims, ok := ctx.Value(config.ContextTokenKey).(auth.Claims)
+	if !ok {
+		log.Error("Token claims not found in request context")
+		return fmt.Errorf("token claims not found in request context")
+	}
+
+	// 失効情報はアクセストークンの有効期限が切れるまで保持すれば十分
+	ttl := time.Until(time.Unix(claims.ExpiresAt, 0).Add(auth.ClockSkewLeeway))
+	if ttl > 0 {
+		if err := auc.trr.Revoke(ctx, claims.JTI, ttl); err != nil {
+			log.Error("Error revoking access token", log.Fstring("user_id", claims.UserID))
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	return auc.tr.Transaction(ctx, func(ctx context.Context) error {
+		token, err := auc.rtr.GetForUpdate(ctx, auth.HashRefreshToken(refreshToken))
+		if errors.Is(err, config.ErrNotFound) {
+			log.Info("Refresh token not found")
+			return nil
+		} else if err != nil {
+			log.Error("Error getting refresh token", log.Ferror(err))
+			return err
+		}
+		if token.UserID != claims.UserID {
+			log.Warn("Refresh token belongs to another user", log.Fstring("user_id", claims.UserID))
+			return nil
+		}
+		if err = auc.rtr.RevokeFamily(ctx, token.FamilyID, time.Now()); err != nil {
+			log.Error("Error revoking refresh token family", log.Ferror(err))
+			return err
+		}
+		return nil
+	})
+}
+
+// LogoutAll ユーザの全てのセッションを失効させる
+func (auc *authUseCase) LogoutAll(ctx context.Context) error {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	now := time.Now()
+	if err := auc.trr.RevokeAllForUser(ctx, userID, now, auth.AccessTokenLifetime+auth.ClockSkewLeeway); err != nil {
+		log.Error("Error revoking access tokens", log.Fstring("user_id", userID))
+		return err
+	}
+	if err := auc.rtr.RevokeAllForUser(ctx, userID, now); err != nil {
+		log.Error("Error revoking refresh tokens", log.Fstring("user_id", userID))
+		return err
+	}
+	return nil
+}
+
+// issueTokenPair アクセストークンとリフレッシュトークンを発行する
+// familyIDが空の場合は新しいログインとして新しいファミリーを作成する
+func issueTokenPair(ctx context.Context, rtr repository.RefreshTokenRepository, user *model.User, familyID string) (*TokenPair, error) {
+	rawToken, tokenHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		log.Error("Error generating refresh token", log.Ferror(err))
+		return nil, err
+	}
+	refreshToken, err := model.NewRefreshToken(user.ID, familyID, tokenHash, time.Now(), auth.RefreshTokenLifetime)
+	if err != nil {
+		log.Error("Error creating refresh token", log.Ferror(err))
+		return nil, err
+	}
+	if err = rtr.Create(ctx, *refreshToken); err != nil {
+		log.Error("Error saving refresh token", log.Fstring("user_id", user.ID))
+		return nil, err
+	}
+
+	accessToken, _ := auth.GenerateToken(user.ID, user.Email, string(user.Role))
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawToken,
+		ExpiresIn:    int(auth.AccessTokenLifetime.Seconds()),
+	}, nil
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
+)
+
+func TestAuthUseCase_Refresh(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	familyID := uuid.New().String()
+	user := model.User{
+		ID:    userID,
+		Name:  "test",
+		Email: "test@gmail.com",
+	}
+	rawToken := "refresh-token"
+	tokenHash := auth.HashRefreshToken(rawToken)
+
+	newToken := func() *model.RefreshToken {
+		token, _ := model.NewRefreshToken(userID, familyID, tokenHash, time.Now(), time.Hour)
+		return token
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockTransactionRepository,
+			m1 *mock.MockUserRepository,
+			m2 *mock.MockRefreshTokenRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(tr *mock.MockTransactionRepository, ur *mock.MockUserRepository, rtr *mock.MockRefreshTokenRepository) {
+				token := newToken()
+				rtr.EXPECT().GetForUpdate(gomock.Any(), tokenHash).Return(token, nil)
+				ur.EXPECT().Get(gomock.Any(), userID).Return(&user, nil)
+				rtr.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, rotated model.RefreshToken) error {
+					if !rotated.IsRotated() {
+						t.Errorf("Update() token is not rotated")
+					}
+					return nil
+				})
+				rtr.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, created model.RefreshToken) error {
+					if created.FamilyID != familyID {
+						t.Errorf("Create() family = %v, want %v", created.FamilyID, familyID)
+					}
+					return nil
+				})
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: token not found",
+			setup: func(tr *mock.MockTransactionRepository, ur *mock.MockUserRepository, rtr *mock.MockRefreshTokenRepository) {
+				rtr.EXPECT().GetForUpdate(gomock.Any(), tokenHash).Return(nil, config.ErrNotFound)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Fail: token expired",
+			setup: func(tr *mock.MockTransactionRepository, ur *mock.MockUserRepository, rtr *mock.MockRefreshTokenRepository) {
+				token := newToken()
+				token.ExpiresAt = time.Now().Add(-time.Minute)
+				rtr.EXPECT().GetForUpdate(gomock.Any(), tokenHash).Return(token, nil)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Fail: reuse of rotated token revokes the family",
+			setup: func(tr *mock.MockTransactionRepository, ur *mock.MockUserRepository, rtr *mock.MockRefreshTokenRepository) {
+				token := newToken()
+				token.Rotate(time.Now().Add(-time.Minute))
+				rtr.EXPECT().GetForUpdate(gomock.Any(), tokenHash).Return(token, nil)
+				rtr.EXPECT().RevokeFamily(gomock.Any(), familyID, gomock.Any()).Return(nil)
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+	}
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ur := mock.NewMockUserRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+			if tt.setup != nil {
+				tt.setup(tr, ur, rtr)
+			}
+
+			trr := mock.NewMockTokenRevocationRepository(ctrl)
+			usecase := NewAuthUseCase(tr, ur, rtr, trr)
+			pair, err := usecase.Refresh(context.Background(), rawToken)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Refresh() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (pair == nil || pair.AccessToken == "" || pair.RefreshToken == "" || pair.RefreshToken == rawToken) {
+				t.Errorf("Refresh() did not issue a new token pair: %v", pair)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_Logout(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	jti := uuid.New().String()
+	familyID := uuid.New().String()
+	rawToken := "refresh-token"
+	tokenHash := auth.HashRefreshToken(rawToken)
+	now := time.Now()
+	claims := auth.Claims{
+		JTI:       jti,
+		UserID:    userID,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(auth.AccessTokenLifetime).Unix(),
+	}
+
+	newToken := func(owner string) *model.RefreshToken {
+		token, _ := model.NewRefreshToken(owner, familyID, tokenHash, now, time.Hour)
+		return token
+	}
+
+	patterns := []struct {
+		name         string
+		refreshToken string
+		setup        func(
+			m *mock.MockTransactionRepository,
+			m1 *mock.MockRefreshTokenRepository,
+			m2 *mock.MockTokenRevocationRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success: access token only",
+			setup: func(tr *mock.MockTransactionRepository, rtr *mock.MockRefreshTokenRepository, trr *mock.MockTokenRevocationRepository) {
+				trr.EXPECT().Revoke(gomock.Any(), jti, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, ttl time.Duration) error {
+					if ttl <= 0 || ttl > auth.AccessTokenLifetime+auth.ClockSkewLeeway {
+						t.Errorf("Revoke() ttl = %v", ttl)
+					}
+					return nil
+				})
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "success: with refresh token",
+			refreshToken: rawToken,
+			setup: func(tr *mock.MockTransactionRepository, rtr *mock.MockRefreshTokenRepository, trr *mock.MockTokenRevocationRepository) {
+				trr.EXPECT().Revoke(gomock.Any(), jti, gomock.Any()).Return(nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				rtr.EXPECT().GetForUpdate(gomock.Any(), tokenHash).Return(newToken(userID), nil)
+				rtr.EXPECT().RevokeFamily(gomock.Any(), familyID, gomock.Any()).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:         "success: refresh token of another user is ignored",
+			refreshToken: rawToken,
+			setup: func(tr *mock.MockTransactionRepository, rtr *mock.MockRefreshTokenRepository, trr *mock.MockTokenRevocationRepository) {
+				trr.EXPECT().Revoke(gomock.Any(), jti, gomock.Any()).Return(nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				rtr.EXPECT().GetForUpdate(gomock.Any(), tokenHash).Return(newToken(uuid.New().String()), nil)
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ur := mock.NewMockUserRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			trr := mock.NewMockTokenRevocationRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(tr, rtr, trr)
+			}
+
+			ctx := context.WithValue(context.Background(), config.ContextTokenKey, claims)
+			usecase := NewAuthUseCase(tr, ur, rtr, trr)
+			err := usecase.Logout(ctx, tt.refreshToken)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Logout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthUseCase_LogoutAll(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+
+	ctrl := gomock.NewController(t)
+	tr := mock.NewMockTransactionRepository(ctrl)
+	ur := mock.NewMockUserRepository(ctrl)
+	rtr := mock.NewMockRefreshTokenRepository(ctrl)
+	trr := mock.NewMockTokenRevocationRepository(ctrl)
+
+	trr.EXPECT().RevokeAllForUser(gomock.Any(), userID, gomock.Any(), auth.AccessTokenLifetime+auth.ClockSkewLeeway).Return(nil)
+	rtr.EXPECT().RevokeAllForUser(gomock.Any(), userID, gomock.Any()).Return(nil)
+
+	usecase := NewAuthUseCase(tr, ur, rtr, trr)
+	if err := usecase.LogoutAll(ctx); err != nil {
+		t.Errorf("LogoutAll() error = %v", err)
+	}
+}
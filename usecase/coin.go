@@ -0,0 +1,152 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const (
+	DefaultCoinHistoryLimit = 20
+	MaxCoinHistoryLimit     = 100
+)
+
+type CoinUseCase interface {
+	// ListCoinHistory 新しい順に最大limit件の履歴と、続きを取得するためのカーソルを返す
+	// 続きがない場合のカーソルは空文字列
+	ListCoinHistory(ctx context.Context, cursor string, limit int) ([]*model.CoinTransaction, string, error)
+	// ReconcileCoins 台帳の合計とUser_Balancesの残高が一致しないユーザの通貨を返す
+	ReconcileCoins(ctx context.Context) ([]*model.CoinBalanceMismatch, error)
+}
+
+type coinUseCase struct {
+	ctr repository.CoinTransactionRepository
+}
+
+func NewCoinUseCase(ctr repository.CoinTransactionRepository) CoinUseCase {
+	return &coinUseCase{
+		ctr: ctr,
+	}
+}
+
+func (cuc *coinUseCase) ListCoinHistory(ctx context.Context, cursor string, limit int) ([]*model.CoinTransaction, string, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, "", fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	var after *model.CoinTransactionCursor
+	if cursor != "" {
+		var err error
+		if after, err = model.DecodeCoinTransactionCursor(cursor); err != nil {
+			log.Info("Invalid cursor", log.Fstring("cursor", cursor))
+			return nil, "", err
+		}
+	}
+	if limit <= 0 {
+		limit = DefaultCoinHistoryLimit
+	}
+	if limit > MaxCoinHistoryLimit {
+		limit = MaxCoinHistoryLimit
+	}
+
+	// 1件多く取得し、続きがあるかどうかを判定する
+	cts, err := cuc.ctr.List(ctx, userID, after, limit+1)
+	if err != nil {
+		log.Error("Failed to list coin transactions", log.Fstring("user_id", userID), log.Ferror(err))
+		return nil, "", err
+	}
+	if len(cts) <= limit {
+		return cts, "", nil
+	}
+	cts = cts[:limit]
+	last := cts[len(cts)-1]
+	next := model.CoinTransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	return cts, next.Encode(), nil
+}
+
+func (cuc *coinUseCase) ReconcileCoins(ctx context.Context) ([]*model.CoinBalanceMismatch, error) {
+	mismatches, err := cuc.ctr.ListBalanceMismatches(ctx)
+	if err != nil {
+		log.Error("Failed to reconcile coins", log.Ferror(err))
+		return nil, err
+	}
+	for _, mismatch := range mismatches {
+		log.Warn(
+			"Balance does not match the ledger",
+			log.Fstring("user_id", mismatch.UserID),
+			log.Fstring("currency", string(mismatch.Currency)),
+			log.Fint("balance", mismatch.Balance),
+			log.Fint("ledger_balance", mismatch.LedgerBalance),
+		)
+	}
+	return mismatches, nil
+}
+
+// recordCoinTransaction 残高を変更したのと同じトランザクション内で台帳に記録する
+// balanceAfterはユーザの行をGetForUpdateでロックした後に取得した残高を基準に計算した、変更後の残高
+func recordCoinTransaction(
+	ctx context.Context,
+	ctr repository.CoinTransactionRepository,
+	userID string,
+	currency model.Currency,
+	reason model.CoinTransactionReason,
+	delta, balanceAfter int,
+	referenceID string,
+) error {
+	if delta == 0 {
+		return nil
+	}
+	ct, err := model.NewCoinTransaction(userID, currency, reason, delta, balanceAfter, referenceID)
+	if err != nil {
+		log.Error("Failed to create coin transaction", log.Ferror(err))
+		return err
+	}
+	if err = ctr.Create(ctx, *ct); err != nil {
+		log.Error("Failed to record coin transaction", log.Fstring("user_id", userID), log.Ferror(err))
+		return err
+	}
+	return nil
+}
+
+// adjustWallet 残高をdeltaだけ増減し、同じトランザクション内で台帳に記録する
+// walletはユーザの行をGetForUpdateでロックした後に取得した残高で、変更後の残高に更新する
+func adjustWallet(
+	ctx context.Context,
+	wr repository.WalletRepository,
+	ctr repository.CoinTransactionRepository,
+	wallet model.Wallet,
+	userID string,
+	currency model.Currency,
+	reason model.CoinTransactionReason,
+	delta int,
+	referenceID string,
+) error {
+	var err error
+	switch {
+	case delta > 0:
+		err = wr.Credit(ctx, userID, currency, delta)
+	case delta < 0:
+		err = wr.Debit(ctx, userID, currency, -delta)
+	default:
+		return nil
+	}
+	if err != nil {
+		log.Error(
+			"Failed to update balance",
+			log.Fstring("user_id", userID),
+			log.Fstring("currency", string(currency)),
+			log.Fint("delta", delta),
+			log.Ferror(err),
+		)
+		return err
+	}
+	wallet[currency] += delta
+	return recordCoinTransaction(ctx, ctr, userID, currency, reason, delta, wallet[currency], referenceID)
+}
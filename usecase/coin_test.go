@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+)
+
+// coinTransactionMatcher IDと作成日時を除いて台帳のエントリを比較する
+type coinTransactionMatcher struct {
+	currency     model.Currency
+	reason       model.CoinTransactionReason
+	delta        int
+	balanceAfter int
+}
+
+func coinTransactionOf(currency model.Currency, reason model.CoinTransactionReason, delta, balanceAfter int) gomock.Matcher {
+	return coinTransactionMatcher{currency: currency, reason: reason, delta: delta, balanceAfter: balanceAfter}
+}
+
+func (m coinTransactionMatcher) Matches(x interface{}) bool {
+	ct, ok := x.(model.CoinTransaction)
+	if !ok {
+		return false
+	}
+	return ct.Currency == m.currency && ct.Reason == m.reason && ct.Delta == m.delta && ct.BalanceAfter == m.balanceAfter
+}
+
+func (m coinTransactionMatcher) String() string {
+	return fmt.Sprintf(
+		"coin transaction {currency: %s, reason: %s, delta: %d, balance_after: %d}",
+		m.currency, m.reason, m.delta, m.balanceAfter,
+	)
+}
+
+func TestCoinUseCase_ListCoinHistory(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cts := []*model.CoinTransaction{
+		{ID: "3", UserID: userID, Reason: model.CoinReasonGameReward, Delta: 10, BalanceAfter: 30, CreatedAt: now},
+		{ID: "2", UserID: userID, Reason: model.CoinReasonGameReward, Delta: 10, BalanceAfter: 20, CreatedAt: now.Add(-time.Second)},
+		{ID: "1", UserID: userID, Reason: model.CoinReasonGameReward, Delta: 10, BalanceAfter: 10, CreatedAt: now.Add(-2 * time.Second)},
+	}
+	cursor := model.CoinTransactionCursor{CreatedAt: cts[1].CreatedAt, ID: cts[1].ID}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCoinTransactionRepository,
+		)
+		arg struct {
+			ctx    context.Context
+			cursor string
+			limit  int
+		}
+		want struct {
+			len        int
+			nextCursor string
+			err        error
+		}
+	}{
+		{
+			name: "success: has next page",
+			setup: func(m *mock.MockCoinTransactionRepository) {
+				m.EXPECT().List(ctx, userID, nil, 3).Return(cts, nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: ctx, limit: 2},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{len: 2, nextCursor: cursor.Encode()},
+		},
+		{
+			name: "success: last page",
+			setup: func(m *mock.MockCoinTransactionRepository) {
+				m.EXPECT().List(ctx, userID, &cursor, DefaultCoinHistoryLimit+1).Return(cts[2:], nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: ctx, cursor: cursor.Encode()},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{len: 1},
+		},
+		{
+			name: "Fail: invalid cursor",
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: ctx, cursor: "invalid"},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{err: model.ErrInvalidCursor},
+		},
+		{
+			name: "Fail: User ID not found in request context",
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: context.Background()},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{err: fmt.Errorf("user name not found in request context")},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(ctr)
+			}
+
+			cuc := NewCoinUseCase(ctr)
+			got, nextCursor, err := cuc.ListCoinHistory(tt.arg.ctx, tt.arg.cursor, tt.arg.limit)
+
+			if (err != nil) != (tt.want.err != nil) {
+				t.Fatalf("ListCoinHistory() error = %v, wantErr %v", err, tt.want.err)
+			} else if err != nil && err.Error() != tt.want.err.Error() {
+				t.Errorf("ListCoinHistory() error = %v, wantErr %v", err, tt.want.err)
+			}
+			if len(got) != tt.want.len {
+				t.Errorf("ListCoinHistory() len = %v, want %v", len(got), tt.want.len)
+			}
+			if nextCursor != tt.want.nextCursor {
+				t.Errorf("ListCoinHistory() nextCursor = %v, want %v", nextCursor, tt.want.nextCursor)
+			}
+		})
+	}
+}
+
+func TestCoinUseCase_ReconcileCoins(t *testing.T) {
+	t.Parallel()
+
+	mismatches := []*model.CoinBalanceMismatch{
+		{UserID: uuid.New().String(), Currency: model.CurrencyCoin, Balance: 100, LedgerBalance: 90},
+	}
+
+	patterns := []struct {
+		name    string
+		setup   func(m *mock.MockCoinTransactionRepository)
+		want    []*model.CoinBalanceMismatch
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockCoinTransactionRepository) {
+				m.EXPECT().ListBalanceMismatches(gomock.Any()).Return(mismatches, nil)
+			},
+			want: mismatches,
+		},
+		{
+			name: "Fail: repository error",
+			setup: func(m *mock.MockCoinTransactionRepository) {
+				m.EXPECT().ListBalanceMismatches(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			tt.setup(ctr)
+
+			cuc := NewCoinUseCase(ctr)
+			got, err := cuc.ReconcileCoins(context.Background())
+
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("ReconcileCoins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("ReconcileCoins() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// MaxCollectionImportSize 一括登録で一度に受け付けるアイテム数の上限
+const MaxCollectionImportSize = 1000
+
+// CollectionInput 管理者が登録・更新するコレクションアイテムのマスタデータ
+type CollectionInput struct {
+	Name   string
+	Rarity int
+	Weight int
+}
+
+// CollectionUseCase コレクションアイテムのマスタデータを管理する
+// 更新後はガチャで使われるキャッシュを破棄し、次の参照時にDBから読み直させる
+type CollectionUseCase interface {
+	ListCollections(ctx context.Context) (model.Collections, error)
+	GetCollection(ctx context.Context, id string) (*model.Collection, error)
+	CreateCollection(ctx context.Context, input CollectionInput) (*model.Collection, error)
+	UpdateCollection(ctx context.Context, id string, input CollectionInput) (*model.Collection, error)
+	DeleteCollection(ctx context.Context, id string) error
+	// ImportCollections 全てのアイテムの検証に成功した場合のみ登録する
+	ImportCollections(ctx context.Context, inputs []CollectionInput) (model.Collections, error)
+}
+
+type collectionUseCase struct {
+	tr   repository.TransactionRepository
+	cr   repository.CollectionRepository
+	ccr  repository.CollectionCacheRepository
+	drcr repository.DropRatesCacheRepository
+}
+
+func NewCollectionUseCase(
+	tr repository.TransactionRepository,
+	cr repository.CollectionRepository,
+	ccr repository.CollectionCacheRepository,
+	drcr repository.DropRatesCacheRepository,
+) CollectionUseCase {
+	return &collectionUseCase{
+		tr:   tr,
+		cr:   cr,
+		ccr:  ccr,
+		drcr: drcr,
+	}
+}
+
+func (cuc *collectionUseCase) ListCollections(ctx context.Context) (model.Collections, error) {
+	// 更新直後の状態を確認できるよう、キャッシュではなくDBから取得する
+	collections, err := cuc.cr.List(ctx)
+	if err != nil {
+		log.Error("Failed to list collections", log.Ferror(err))
+		return nil, err
+	}
+	return collections, nil
+}
+
+func (cuc *collectionUseCase) GetCollection(ctx context.Context, id string) (*model.Collection, error) {
+	collection, err := cuc.cr.Get(ctx, id)
+	if err != nil {
+		log.Warn("Failed to get collection", log.Fstring("collection_id", id), log.Ferror(err))
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (cuc *collectionUseCase) CreateCollection(ctx context.Context, input CollectionInput) (*model.Collection, error) {
+	collection, err := newCollectionFromInput("collection", input)
+	if err != nil {
+		return nil, err
+	}
+	if err = cuc.cr.Create(ctx, *collection); err != nil {
+		log.Error("Failed to create collection", log.Ferror(err))
+		return nil, err
+	}
+	if err = cuc.invalidateCache(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("Collection created", log.Fstring("collection_id", collection.ID))
+	return collection, nil
+}
+
+func (cuc *collectionUseCase) UpdateCollection(ctx context.Context, id string, input CollectionInput) (*model.Collection, error) {
+	collection, err := newCollectionFromInput("collection", input)
+	if err != nil {
+		return nil, err
+	}
+	collection.ID = id
+
+	if err = cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if _, err = cuc.cr.Get(ctx, id); err != nil {
+			log.Warn("Failed to get collection", log.Fstring("collection_id", id), log.Ferror(err))
+			return err
+		}
+		if err = cuc.cr.Update(ctx, *collection); err != nil {
+			log.Error("Failed to update collection", log.Fstring("collection_id", id), log.Ferror(err))
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err = cuc.invalidateCache(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("Collection updated", log.Fstring("collection_id", id))
+	return collection, nil
+}
+
+func (cuc *collectionUseCase) DeleteCollection(ctx context.Context, id string) error {
+	if err := cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := cuc.cr.Get(ctx, id); err != nil {
+			log.Warn("Failed to get collection", log.Fstring("collection_id", id), log.Ferror(err))
+			return err
+		}
+		if err := cuc.cr.Delete(ctx, id); err != nil {
+			log.Warn("Failed to delete collection", log.Fstring("collection_id", id), log.Ferror(err))
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := cuc.invalidateCache(ctx); err != nil {
+		return err
+	}
+	log.Info("Collection deleted", log.Fstring("collection_id", id))
+	return nil
+}
+
+func (cuc *collectionUseCase) ImportCollections(ctx context.Context, inputs []CollectionInput) (model.Collections, error) {
+	if len(inputs) == 0 || len(inputs) > MaxCollectionImportSize {
+		return nil, &model.ValidationError{Fields: []model.FieldError{{
+			Field:   "collections",
+			Message: fmt.Sprintf("must contain between 1 and %d items", MaxCollectionImportSize),
+		}}}
+	}
+
+	collections := make(model.Collections, 0, len(inputs))
+	verr := &model.ValidationError{}
+	for i, input := range inputs {
+		collection, err := newCollectionFromInput(fmt.Sprintf("collections[%d]", i), input)
+		var itemErr *model.ValidationError
+		if errors.As(err, &itemErr) {
+			verr.Fields = append(verr.Fields, itemErr.Fields...)
+			continue
+		}
+		collections = append(collections, collection)
+	}
+	if len(verr.Fields) > 0 {
+		return nil, verr
+	}
+
+	if err := cuc.cr.BatchCreate(ctx, collections); err != nil {
+		log.Error("Failed to import collections", log.Ferror(err))
+		return nil, err
+	}
+	if err := cuc.invalidateCache(ctx); err != nil {
+		return nil, err
+	}
+	log.Info("Collections imported", log.Fint("count", len(collections)))
+	return collections, nil
+}
+
+// invalidateCache 古いマスタデータがキャッシュに残らないよう、コミット後にキャッシュを破棄する
+// 排出確率が古いアイテムのキャッシュから再計算されないよう、アイテムのキャッシュを先に破棄する
+func (cuc *collectionUseCase) invalidateCache(ctx context.Context) error {
+	if err := cuc.ccr.Delete(ctx, model.CollectionsCacheKey); err != nil {
+		log.Error("Failed to invalidate collections cache", log.Ferror(err))
+		return err
+	}
+	if err := cuc.drcr.Delete(ctx, model.DropRatesCacheKey); err != nil {
+		log.Error("Failed to invalidate drop rates cache", log.Ferror(err))
+		return err
+	}
+	return nil
+}
+
+// newCollectionFromInput 検証に失敗した場合はfieldを項目名とするValidationErrorを返す
+func newCollectionFromInput(field string, input CollectionInput) (*model.Collection, error) {
+	collection, err := model.NewCollection(input.Name, input.Rarity, input.Weight)
+	if err != nil {
+		return nil, &model.ValidationError{Fields: []model.FieldError{{Field: field, Message: err.Error()}}}
+	}
+	return collection, nil
+}
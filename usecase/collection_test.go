@@ -0,0 +1,302 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+)
+
+// newDropRatesCacheMock アイテムのキャッシュと同時に排出確率のキャッシュも破棄される
+func newDropRatesCacheMock(ctrl *gomock.Controller) *mock.MockDropRatesCacheRepository {
+	drcr := mock.NewMockDropRatesCacheRepository(ctrl)
+	drcr.EXPECT().Delete(gomock.Any(), model.DropRatesCacheKey).Return(nil).AnyTimes()
+	return drcr
+}
+
+func expectTransaction(tr *mock.MockTransactionRepository) {
+	tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	})
+}
+
+func TestCollectionUseCase_CreateCollection(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCollectionRepository,
+			m1 *mock.MockCollectionCacheRepository,
+		)
+		input     CollectionInput
+		wantErr   error
+		wantField string
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				ccr.EXPECT().Delete(gomock.Any(), model.CollectionsCacheKey).Return(nil)
+			},
+			input: CollectionInput{Name: "collection", Rarity: 3, Weight: 10},
+		},
+		{
+			name:      "Fail: invalid rarity",
+			input:     CollectionInput{Name: "collection", Rarity: 6, Weight: 10},
+			wantField: "collection",
+		},
+		{
+			name: "Fail: cache invalidation failed",
+			setup: func(cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				ccr.EXPECT().Delete(gomock.Any(), model.CollectionsCacheKey).Return(errors.New("redis down"))
+			},
+			input:   CollectionInput{Name: "collection", Rarity: 3, Weight: 10},
+			wantErr: errors.New("redis down"),
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(cr, ccr)
+			}
+
+			cuc := NewCollectionUseCase(mock.NewMockTransactionRepository(ctrl), cr, ccr, newDropRatesCacheMock(ctrl))
+			collection, err := cuc.CreateCollection(context.Background(), tt.input)
+
+			if tt.wantField != "" {
+				var verr *model.ValidationError
+				if !errors.As(err, &verr) || verr.Fields[0].Field != tt.wantField {
+					t.Errorf("CreateCollection() error = %v, want validation error on %v", err, tt.wantField)
+				}
+				return
+			}
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("CreateCollection() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil {
+				if err.Error() != tt.wantErr.Error() {
+					t.Errorf("CreateCollection() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if collection.ID == "" || collection.Name != tt.input.Name {
+				t.Errorf("CreateCollection() = %+v", collection)
+			}
+		})
+	}
+}
+
+func TestCollectionUseCase_UpdateCollection(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New().String()
+	input := CollectionInput{Name: "updated", Rarity: 4, Weight: 5}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockTransactionRepository,
+			m1 *mock.MockCollectionRepository,
+			m2 *mock.MockCollectionCacheRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(tr *mock.MockTransactionRepository, cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				expectTransaction(tr)
+				cr.EXPECT().Get(gomock.Any(), id).Return(&model.Collection{ID: id, Name: "before", Rarity: 1, Weight: 1}, nil)
+				cr.EXPECT().Update(gomock.Any(), model.Collection{ID: id, Name: "updated", Rarity: 4, Weight: 5}).Return(nil)
+				ccr.EXPECT().Delete(gomock.Any(), model.CollectionsCacheKey).Return(nil)
+			},
+		},
+		{
+			name: "Fail: collection not found",
+			setup: func(tr *mock.MockTransactionRepository, cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				expectTransaction(tr)
+				cr.EXPECT().Get(gomock.Any(), id).Return(nil, config.ErrNotFound)
+			},
+			wantErr: config.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			tt.setup(tr, cr, ccr)
+
+			cuc := NewCollectionUseCase(tr, cr, ccr, newDropRatesCacheMock(ctrl))
+			_, err := cuc.UpdateCollection(context.Background(), id, input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateCollection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCollectionUseCase_DeleteCollection(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockTransactionRepository,
+			m1 *mock.MockCollectionRepository,
+			m2 *mock.MockCollectionCacheRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(tr *mock.MockTransactionRepository, cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				expectTransaction(tr)
+				cr.EXPECT().Get(gomock.Any(), id).Return(&model.Collection{ID: id}, nil)
+				cr.EXPECT().Delete(gomock.Any(), id).Return(nil)
+				ccr.EXPECT().Delete(gomock.Any(), model.CollectionsCacheKey).Return(nil)
+			},
+		},
+		{
+			name: "Fail: owned by users",
+			setup: func(tr *mock.MockTransactionRepository, cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				expectTransaction(tr)
+				cr.EXPECT().Get(gomock.Any(), id).Return(&model.Collection{ID: id}, nil)
+				cr.EXPECT().Delete(gomock.Any(), id).Return(config.ErrReferenced)
+			},
+			wantErr: config.ErrReferenced,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			tt.setup(tr, cr, ccr)
+
+			cuc := NewCollectionUseCase(tr, cr, ccr, newDropRatesCacheMock(ctrl))
+			if err := cuc.DeleteCollection(context.Background(), id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteCollection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCollectionUseCase_ImportCollections(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockCollectionRepository, m1 *mock.MockCollectionCacheRepository)
+		inputs     []CollectionInput
+		wantCount  int
+		wantFields []string
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCollectionRepository, ccr *mock.MockCollectionCacheRepository) {
+				cr.EXPECT().BatchCreate(gomock.Any(), gomock.Len(2)).Return(nil)
+				ccr.EXPECT().Delete(gomock.Any(), model.CollectionsCacheKey).Return(nil)
+			},
+			inputs: []CollectionInput{
+				{Name: "collection1", Rarity: 1, Weight: 10},
+				{Name: "collection2", Rarity: 5, Weight: 1},
+			},
+			wantCount: 2,
+		},
+		{
+			name: "Fail: every invalid item is reported and nothing is imported",
+			inputs: []CollectionInput{
+				{Name: "", Rarity: 1, Weight: 10},
+				{Name: "collection2", Rarity: 5, Weight: 1},
+				{Name: "collection3", Rarity: 1, Weight: -1},
+			},
+			wantFields: []string{"collections[0]", "collections[2]"},
+		},
+		{
+			name:       "Fail: empty",
+			inputs:     nil,
+			wantFields: []string{"collections"},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(cr, ccr)
+			}
+
+			cuc := NewCollectionUseCase(mock.NewMockTransactionRepository(ctrl), cr, ccr, newDropRatesCacheMock(ctrl))
+			collections, err := cuc.ImportCollections(context.Background(), tt.inputs)
+
+			if tt.wantFields != nil {
+				var verr *model.ValidationError
+				if !errors.As(err, &verr) || len(verr.Fields) != len(tt.wantFields) {
+					t.Fatalf("ImportCollections() error = %v, want validation errors on %v", err, tt.wantFields)
+				}
+				for i, field := range tt.wantFields {
+					if verr.Fields[i].Field != field {
+						t.Errorf("ImportCollections() field = %v, want %v", verr.Fields[i].Field, field)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ImportCollections() error = %v", err)
+			}
+			if len(collections) != tt.wantCount {
+				t.Errorf("ImportCollections() count = %v, want %v", len(collections), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestUsecase_CollectionCacheInvalidation(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cr := mock.NewMockCollectionRepository(ctrl)
+	ccr := mock.NewMockCollectionCacheRepository(ctrl)
+	drcr := mock.NewMockDropRatesCacheRepository(ctrl)
+	cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	gomock.InOrder(
+		ccr.EXPECT().Delete(gomock.Any(), model.CollectionsCacheKey).Return(nil),
+		drcr.EXPECT().Delete(gomock.Any(), model.DropRatesCacheKey).Return(nil),
+	)
+
+	cuc := NewCollectionUseCase(mock.NewMockTransactionRepository(ctrl), cr, ccr, drcr)
+	if _, err := cuc.CreateCollection(context.Background(), CollectionInput{Name: "collection", Rarity: 3, Weight: 10}); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+}
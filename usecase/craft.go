@@ -0,0 +1,183 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// CraftUseCase 重複して所持しているアイテムの分解と、欠片を使ったアイテムの作成
+// ガチャと同じくユーザの行ロックで直列化し、所持数と欠片の残高の変更を1つのトランザクションで反映する
+type CraftUseCase interface {
+	// Dismantle 最後の1個を残して、アイテムをquantity個分解し欠片に変換する
+	Dismantle(ctx context.Context, collectionID string, quantity int) (*CraftResult, error)
+	// Craft 欠片を消費してアイテムを1個作成する
+	Craft(ctx context.Context, collectionID string) (*CraftResult, error)
+}
+
+// CraftResult 分解・作成後のアイテムの所持数と欠片の残高
+type CraftResult struct {
+	Collection *model.Collection
+	Quantity   int
+	Shards     int
+}
+
+type craftUseCase struct {
+	tr  repository.TransactionRepository
+	ur  repository.UserRepository
+	ucr repository.UserCollectionRepository
+	cr  repository.CollectionRepository
+	ctr repository.CoinTransactionRepository
+	wr  repository.WalletRepository
+}
+
+func NewCraftUseCase(
+	tr repository.TransactionRepository,
+	ur repository.UserRepository,
+	ucr repository.UserCollectionRepository,
+	cr repository.CollectionRepository,
+	ctr repository.CoinTransactionRepository,
+	wr repository.WalletRepository,
+) CraftUseCase {
+	return &craftUseCase{
+		tr:  tr,
+		ur:  ur,
+		ucr: ucr,
+		cr:  cr,
+		ctr: ctr,
+		wr:  wr,
+	}
+}
+
+func (cuc *craftUseCase) Dismantle(ctx context.Context, collectionID string, quantity int) (*CraftResult, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	if quantity < 1 {
+		log.Info("Invalid dismantle quantity", log.Fstring("user_id", userID), log.Fint("quantity", quantity))
+		return nil, model.ErrInvalidQuantity
+	}
+
+	collection, err := cuc.cr.Get(ctx, collectionID)
+	if err != nil {
+		log.Warn("Failed to get collection", log.Fstring("collection_id", collectionID), log.Ferror(err))
+		return nil, err
+	}
+
+	var result *CraftResult
+	if err = cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		// 同じユーザのガチャ・分解・作成を直列化する
+		user, err := cuc.ur.GetForUpdate(ctx, userID) //nolint:govet // This is a valid code
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+
+		wallet, err := cuc.wr.Get(ctx, user.ID)
+		if err != nil {
+			log.Error("Error getting wallet", log.Fstring("user_id", user.ID))
+			return err
+		}
+
+		if err = cuc.ucr.Decrement(ctx, user.ID, collection.ID, quantity); err != nil {
+			return err
+		}
+		if err = adjustWallet(
+			ctx, cuc.wr, cuc.ctr, wallet, user.ID, model.CurrencyShard, model.CoinReasonDismantle,
+			model.DismantleShards(collection, quantity), collection.ID,
+		); err != nil {
+			return err
+		}
+
+		userCollection, err := cuc.ucr.Get(ctx, user.ID, collection.ID)
+		if err != nil {
+			log.Error("Error getting user collection", log.Fstring("user_id", user.ID), log.Fstring("collection_id", collection.ID))
+			return err
+		}
+		result = &CraftResult{
+			Collection: collection,
+			Quantity:   userCollection.Quantity,
+			Shards:     wallet[model.CurrencyShard],
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (cuc *craftUseCase) Craft(ctx context.Context, collectionID string) (*CraftResult, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	collection, err := cuc.cr.Get(ctx, collectionID)
+	if err != nil {
+		log.Warn("Failed to get collection", log.Fstring("collection_id", collectionID), log.Ferror(err))
+		return nil, err
+	}
+	cost := model.CraftCost(collection)
+	if cost <= 0 {
+		log.Info("Collection is not craftable", log.Fstring("collection_id", collection.ID), log.Fint("cost", cost))
+		return nil, model.ErrNotCraftable
+	}
+
+	var result *CraftResult
+	if err = cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		user, err := cuc.ur.GetForUpdate(ctx, userID) //nolint:govet // This is a valid code
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+		wallet, err := cuc.wr.Get(ctx, user.ID)
+		if err != nil {
+			log.Error("Error getting wallet", log.Fstring("user_id", user.ID))
+			return err
+		}
+		if wallet[model.CurrencyShard] < cost {
+			log.Info("Insufficient shards", log.Fstring("user_id", userID), log.Fint("shards", wallet[model.CurrencyShard]))
+			return model.ErrInsufficientBalance
+		}
+
+		if err = adjustWallet(
+			ctx, cuc.wr, cuc.ctr, wallet, user.ID, model.CurrencyShard, model.CoinReasonCraft, -cost, collection.ID,
+		); err != nil {
+			return err
+		}
+		userCollection, err := model.NewUserCollection(user.ID, collection.ID, 1, time.Now())
+		if err != nil {
+			log.Error("Failed to create user collection", log.Ferror(err))
+			return err
+		}
+		if err = cuc.ucr.BatchIncrement(ctx, []*model.UserCollection{userCollection}); err != nil {
+			log.Error("Failed to add user collection", log.Ferror(err))
+			return err
+		}
+
+		if userCollection, err = cuc.ucr.Get(ctx, user.ID, collection.ID); err != nil {
+			log.Error("Error getting user collection", log.Fstring("user_id", user.ID), log.Fstring("collection_id", collection.ID))
+			return err
+		}
+		result = &CraftResult{
+			Collection: collection,
+			Quantity:   userCollection.Quantity,
+			Shards:     wallet[model.CurrencyShard],
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
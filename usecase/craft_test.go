@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-tech-dojo/config"
+	"github.com/tusmasoma/go-tech-dojo/domain/model"
+	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+)
+
+func TestCraftUseCase_Dismantle(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	collection := &model.Collection{ID: uuid.New().String(), Name: "collection", Rarity: 3, Weight: 10}
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+
+	patterns := []struct {
+		name  string
+		setup func(
+			tr *mock.MockTransactionRepository,
+			ur *mock.MockUserRepository,
+			ucr *mock.MockUserCollectionRepository,
+			cr *mock.MockCollectionRepository,
+			ctr *mock.MockCoinTransactionRepository,
+			wr *mock.MockWalletRepository,
+		)
+		quantity int
+		want     *CraftResult
+		wantErr  error
+	}{
+		{
+			name: "success",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(collection, nil)
+				expectTransaction(tr)
+				ur.EXPECT().GetForUpdate(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				wr.EXPECT().Get(gomock.Any(), userID).Return(model.Wallet{model.CurrencyShard: 5}, nil)
+				ucr.EXPECT().Decrement(gomock.Any(), userID, collection.ID, 2).Return(nil)
+				wr.EXPECT().Credit(gomock.Any(), userID, model.CurrencyShard, 60).Return(nil)
+				ctr.EXPECT().Create(gomock.Any(), coinTransactionOf(model.CurrencyShard, model.CoinReasonDismantle, 60, 65)).Return(nil)
+				ucr.EXPECT().Get(gomock.Any(), userID, collection.ID).Return(
+					&model.UserCollection{UserID: userID, CollectionID: collection.ID, Quantity: 1},
+					nil,
+				)
+			},
+			quantity: 2,
+			want:     &CraftResult{Collection: collection, Quantity: 1, Shards: 65},
+		},
+		{
+			name:     "Fail: quantity is less than 1",
+			quantity: 0,
+			wantErr:  model.ErrInvalidQuantity,
+		},
+		{
+			name: "Fail: collection not found",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(nil, config.ErrNotFound)
+			},
+			quantity: 1,
+			wantErr:  config.ErrNotFound,
+		},
+		{
+			name: "Fail: the last copy cannot be dismantled",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(collection, nil)
+				expectTransaction(tr)
+				ur.EXPECT().GetForUpdate(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				wr.EXPECT().Get(gomock.Any(), userID).Return(model.NewWallet(), nil)
+				ucr.EXPECT().Decrement(gomock.Any(), userID, collection.ID, 1).Return(model.ErrInsufficientItems)
+			},
+			quantity: 1,
+			wantErr:  model.ErrInsufficientItems,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ur := mock.NewMockUserRepository(ctrl)
+			ucr := mock.NewMockUserCollectionRepository(ctrl)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(tr, ur, ucr, cr, ctr, wr)
+			}
+
+			cuc := NewCraftUseCase(tr, ur, ucr, cr, ctr, wr)
+			result, err := cuc.Dismantle(ctx, collection.ID, tt.quantity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Dismantle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if result.Collection != tt.want.Collection || result.Quantity != tt.want.Quantity || result.Shards != tt.want.Shards {
+				t.Errorf("Dismantle() = %+v, want %+v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestCraftUseCase_Craft(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	collection := &model.Collection{ID: uuid.New().String(), Name: "collection", Rarity: 2, Weight: 10}
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+
+	patterns := []struct {
+		name  string
+		setup func(
+			tr *mock.MockTransactionRepository,
+			ur *mock.MockUserRepository,
+			ucr *mock.MockUserCollectionRepository,
+			cr *mock.MockCollectionRepository,
+			ctr *mock.MockCoinTransactionRepository,
+			wr *mock.MockWalletRepository,
+		)
+		want    *CraftResult
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(collection, nil)
+				expectTransaction(tr)
+				ur.EXPECT().GetForUpdate(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				wr.EXPECT().Get(gomock.Any(), userID).Return(model.Wallet{model.CurrencyCoin: 1000, model.CurrencyShard: 250}, nil)
+				wr.EXPECT().Debit(gomock.Any(), userID, model.CurrencyShard, 200).Return(nil)
+				ctr.EXPECT().Create(gomock.Any(), coinTransactionOf(model.CurrencyShard, model.CoinReasonCraft, -200, 50)).Return(nil)
+				ucr.EXPECT().BatchIncrement(gomock.Any(), userCollectionsOf(userID, map[string]int{collection.ID: 1})).Return(nil)
+				ucr.EXPECT().Get(gomock.Any(), userID, collection.ID).Return(
+					&model.UserCollection{UserID: userID, CollectionID: collection.ID, Quantity: 3},
+					nil,
+				)
+			},
+			want: &CraftResult{Collection: collection, Quantity: 3, Shards: 50},
+		},
+		{
+			name: "Fail: collection not found",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(nil, config.ErrNotFound)
+			},
+			wantErr: config.ErrNotFound,
+		},
+		{
+			name: "Fail: rarity 0 item cannot be crafted for free",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(&model.Collection{ID: collection.ID, Name: "free", Rarity: 0, Weight: 10}, nil)
+			},
+			wantErr: model.ErrNotCraftable,
+		},
+		{
+			name: "Fail: insufficient shards are not covered by other currencies",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				ucr *mock.MockUserCollectionRepository,
+				cr *mock.MockCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+			) {
+				cr.EXPECT().Get(gomock.Any(), collection.ID).Return(collection, nil)
+				expectTransaction(tr)
+				ur.EXPECT().GetForUpdate(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				wr.EXPECT().Get(gomock.Any(), userID).Return(model.Wallet{model.CurrencyCoin: 1000, model.CurrencyShard: 199}, nil)
+			},
+			wantErr: model.ErrInsufficientBalance,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ur := mock.NewMockUserRepository(ctrl)
+			ucr := mock.NewMockUserCollectionRepository(ctrl)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(tr, ur, ucr, cr, ctr, wr)
+			}
+
+			cuc := NewCraftUseCase(tr, ur, ucr, cr, ctr, wr)
+			result, err := cuc.Craft(ctx, collection.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Craft() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if result.Collection != tt.want.Collection || result.Quantity != tt.want.Quantity || result.Shards != tt.want.Shards {
+				t.Errorf("Craft() = %+v, want %+v", result, tt.want)
+			}
+		})
+	}
+}
@@ -3,28 +3,70 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/domain/repository"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 )
 
+const (
+	DefaultGachaHistoryLimit = 20
+	MaxGachaHistoryLimit     = 100
+)
+
 type GameUseCase interface {
-	FinishGame(ctx context.Context, scoreValue int) (int, error)
-	DrawGacha(ctx context.Context, times int) ([]*GachaResult, error)
+	StartGame(ctx context.Context) (*GameStart, error)
+	// FinishGame StartGameで発行したセッションのトークンが必要で、同じセッションは一度しか終了できない
+	FinishGame(ctx context.Context, sessionToken string, scoreValue int) (int, error)
+	ListBanners(ctx context.Context) ([]*BannerDetail, error)
+	// GetDropRates バナーの排出確率。アイテムのキャッシュとあわせてキャッシュする
+	GetDropRates(ctx context.Context, bannerID string) (*model.DropRates, error)
+	// DrawGacha 開催中のバナーでのみ引くことができ、排出対象とコストはバナーごとに異なる
+	// 天井カウンタはユーザのバナーごとに保持し、抽選と同じトランザクションで更新する
+	DrawGacha(ctx context.Context, bannerID string, times int) (*GachaDraw, error)
+	// ListGachaHistory 新しい順に最大limit件の抽選の記録と、続きを取得するためのカーソルを返す
+	// 続きがない場合のカーソルは空文字列
+	ListGachaHistory(ctx context.Context, cursor string, limit int) ([]*model.GachaDrawRecord, string, error)
 }
 
 type gameUseCase struct {
-	tr  repository.TransactionRepository
-	ur  repository.UserRepository
-	ucr repository.UserCollectionRepository
-	sr  repository.ScoreRepository
-	rr  repository.RankingRepository
-	cr  repository.CollectionRepository
-	ccr repository.CollectionCacheRepository
+	tr   repository.TransactionRepository
+	ur   repository.UserRepository
+	ucr  repository.UserCollectionRepository
+	sr   repository.ScoreRepository
+	rr   repository.RankingRepository
+	cr   repository.CollectionRepository
+	ccr  repository.CollectionCacheRepository
+	drcr repository.DropRatesCacheRepository
+	ctr  repository.CoinTransactionRepository
+	wr   repository.WalletRepository
+	gsr  repository.GameSessionRepository
+	br   repository.BannerRepository
+	gpr  repository.GachaPityRepository
+	gdr  repository.GachaDrawRepository
+
+	sessionConf GameSessionConfig
+	pityRules   model.PityRules
+	gacha       *model.Gacha
+	rngName     string
+	pools       *gachaPoolCache
+}
+
+// GameSessionConfig ゲームセッションのトークンの署名鍵とスコアの検証ルール
+type GameSessionConfig struct {
+	Secret []byte
+	Rules  model.GameSessionRules
 }
 
 func NewGameUseCase(
@@ -35,35 +77,196 @@ func NewGameUseCase(
 	rr repository.RankingRepository,
 	cr repository.CollectionRepository,
 	ccr repository.CollectionCacheRepository,
+	drcr repository.DropRatesCacheRepository,
+	ctr repository.CoinTransactionRepository,
+	wr repository.WalletRepository,
+	gsr repository.GameSessionRepository,
+	br repository.BannerRepository,
+	gpr repository.GachaPityRepository,
+	gdr repository.GachaDrawRepository,
+	sessionConf GameSessionConfig,
+	pityRules model.PityRules,
+	rng model.RandomSource,
 ) GameUseCase {
 	return &gameUseCase{
-		tr:  tr,
-		ur:  ur,
-		ucr: ucr,
-		sr:  sr,
-		rr:  rr,
-		cr:  cr,
-		ccr: ccr,
+		tr:   tr,
+		ur:   ur,
+		ucr:  ucr,
+		sr:   sr,
+		rr:   rr,
+		cr:   cr,
+		ccr:  ccr,
+		drcr: drcr,
+		ctr:  ctr,
+		wr:   wr,
+		gsr:  gsr,
+		br:   br,
+		gpr:  gpr,
+		gdr:  gdr,
+
+		sessionConf: sessionConf,
+		pityRules:   pityRules,
+		gacha:       model.NewGacha(rng),
+		rngName:     rng.Name(),
+		pools:       newGachaPoolCache(),
+	}
+}
+
+// gachaPoolCache 開催中のバナーごとの抽選用の表
+// 表の構築は排出対象の数に比例するため、アイテムのキャッシュのVersionが変わった場合のみ作り直す。
+// バナーは作成後に変更されないため、バナーの排出対象が変わるのはアイテムが更新された場合に限られる
+type gachaPoolCache struct {
+	mu      sync.Mutex
+	version int64
+	pools   map[string]*cachedGachaPool
+}
+
+type cachedGachaPool struct {
+	pool  *model.GachaPool
+	endAt time.Time
+}
+
+func newGachaPoolCache() *gachaPoolCache {
+	return &gachaPoolCache{pools: make(map[string]*cachedGachaPool)}
+}
+
+// get versionのアイテムから作った表を返す。表がなければloadで排出対象を取得して作る
+// 開催が終了したバナーの表は破棄する
+func (c *gachaPoolCache) get(
+	banner *model.Banner,
+	version int64,
+	now time.Time,
+	load func() (model.Collections, error),
+) (*model.GachaPool, error) {
+	c.mu.Lock()
+	if version != c.version {
+		c.version = version
+		c.pools = make(map[string]*cachedGachaPool)
+	}
+	for bannerID, cached := range c.pools {
+		if !now.Before(cached.endAt) {
+			delete(c.pools, bannerID)
+		}
+	}
+	cached, ok := c.pools[banner.ID]
+	c.mu.Unlock()
+	if ok {
+		return cached.pool, nil
+	}
+
+	// 排出対象の取得にはI/Oを伴うため、ロックを外して表を作る
+	items, err := load()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := model.NewGachaPool(items)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 表を作っている間に別のVersionに切り替わった場合は保存しない
+	if version == c.version {
+		c.pools[banner.ID] = &cachedGachaPool{pool: pool, endAt: banner.EndAt}
 	}
+	return pool, nil
 }
 
-func (guc *gameUseCase) FinishGame(ctx context.Context, scoreValue int) (int, error) {
-	userIDValue := ctx.Value(config.ContextUserIDKey)
-	userID, ok := userIDValue.(string)
+type GameStart struct {
+	SessionToken string
+	Seed         int64
+	StartedAt    time.Time
+}
+
+func (guc *gameUseCase) StartGame(ctx context.Context) (*GameStart, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
 	if !ok {
 		log.Error("User ID not found in request context")
-		return 0, fmt.Errorf("user name not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	seed, err := generateSeed()
+	if err != nil {
+		log.Error("Failed to generate seed", log.Ferror(err))
+		return nil, err
 	}
-	user, err := guc.ur.Get(ctx, userID)
+	session, err := model.NewGameSession(userID, seed, time.Now())
 	if err != nil {
-		log.Error("Error getting user", log.Fstring("user_id", userID))
+		log.Error("Failed to create game session", log.Ferror(err))
+		return nil, err
+	}
+	if err = guc.gsr.Create(ctx, *session); err != nil {
+		log.Error("Failed to create game session", log.Ferror(err))
+		return nil, err
+	}
+	return &GameStart{
+		SessionToken: auth.SignGameSession(guc.sessionConf.Secret, session.ID),
+		Seed:         session.Seed,
+		StartedAt:    session.StartedAt,
+	}, nil
+}
+
+// generateSeed クライアントがゲームの内容を再現するための乱数のシード
+func generateSeed() (int64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
 		return 0, err
 	}
+	return int64(binary.BigEndian.Uint64(b[:]) & math.MaxInt64), nil
+}
 
+func (guc *gameUseCase) FinishGame(ctx context.Context, sessionToken string, scoreValue int) (int, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return 0, fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	sessionID, err := auth.VerifyGameSession(guc.sessionConf.Secret, sessionToken)
+	if err != nil {
+		log.Warn("Invalid game session token", log.Fstring("user_id", userID))
+		return 0, model.ErrGameSessionInvalid
+	}
+
+	var user *model.User
 	var coin int
 	if err = guc.tr.Transaction(ctx, func(ctx context.Context) error {
+		// 同じセッションでの同時の終了リクエストを直列化し、2回目以降を拒否する
+		session, err := guc.gsr.GetForUpdate(ctx, sessionID) //nolint:govet // This is a valid code
+		if errors.Is(err, config.ErrNotFound) {
+			log.Warn("Game session not found", log.Fstring("session_id", sessionID))
+			return model.ErrGameSessionInvalid
+		} else if err != nil {
+			log.Error("Error getting game session", log.Fstring("session_id", sessionID))
+			return err
+		}
+		if err = session.Finish(userID, scoreValue, time.Now(), guc.sessionConf.Rules); err != nil {
+			log.Warn(
+				"Game session rejected",
+				log.Fstring("session_id", sessionID),
+				log.Fstring("user_id", userID),
+				log.Fint("score", scoreValue),
+				log.Ferror(err),
+			)
+			return err
+		}
+		if err = guc.gsr.Update(ctx, *session); err != nil {
+			log.Error("Failed to update game session", log.Ferror(err))
+			return err
+		}
+
+		// 同じユーザの他のリクエストによる更新が失われないよう、コミットまで行ロックを取得する
+		user, err = guc.ur.GetForUpdate(ctx, userID)
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+
 		var game model.Game
-		score, err := model.NewScore(user.ID, scoreValue) //nolint:govet // This is a valid code
+		score, err := model.NewScore(user.ID, scoreValue)
 		if err != nil {
 			log.Error("Failed to create score", log.Ferror(err))
 			return err
@@ -76,18 +279,23 @@ func (guc *gameUseCase) FinishGame(ctx context.Context, scoreValue int) (int, er
 		if user.HighScore < scoreValue {
 			user.HighScore = scoreValue
 		}
-		coin = game.Reward(scoreValue)
-		user.Coins += coin
 		if err = guc.ur.Update(ctx, *user); err != nil {
 			log.Error("Failed to update user", log.Ferror(err))
 			return err
 		}
-		return nil
+
+		wallet, err := guc.wr.Get(ctx, user.ID)
+		if err != nil {
+			log.Error("Error getting wallet", log.Fstring("user_id", user.ID))
+			return err
+		}
+		coin = game.Reward(scoreValue)
+		return adjustWallet(ctx, guc.wr, guc.ctr, wallet, user.ID, model.CurrencyCoin, model.CoinReasonGameReward, coin, score.ID)
 	}); err != nil {
 		return 0, err
 	}
 
-	if err = guc.rr.Create(
+	if err := guc.rr.Create(
 		ctx,
 		model.ScoreBoardKey,
 		&model.Ranking{
@@ -103,31 +311,89 @@ func (guc *gameUseCase) FinishGame(ctx context.Context, scoreValue int) (int, er
 
 type GachaResult struct {
 	*model.Collection
+	// Has 抽選前から所持していた、または同じ抽選内で既に排出されていたアイテム
 	Has bool `json:"has"`
 }
 
-func (guc *gameUseCase) DrawGacha(ctx context.Context, times int) ([]*GachaResult, error) { //nolint:gocognit // This is a valid code
-	userIDValue := ctx.Value(config.ContextUserIDKey)
-	userID, ok := userIDValue.(string)
-	if !ok {
-		log.Error("User ID not found in request context")
-		return nil, fmt.Errorf("user name not found in request context")
+type GachaDraw struct {
+	Results []*GachaResult
+	// Payments 支払いに使った通貨ごとの額。無償のコインから優先して消費する
+	Payments []model.Payment
+	// PityCount 抽選後の天井カウンタ
+	PityCount int
+}
+
+// BannerDetail 開催中のバナーと、その排出対象のアイテム
+type BannerDetail struct {
+	*model.Banner
+	// Pool 排出対象のアイテム。Weightはバナーごとの重み
+	Pool model.Collections
+}
+
+func (guc *gameUseCase) ListBanners(ctx context.Context) ([]*BannerDetail, error) {
+	banners, err := guc.br.ListActive(ctx, time.Now())
+	if err != nil {
+		log.Error("Error getting active banners", log.Ferror(err))
+		return nil, err
+	}
+	collections, err := guc.listCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*BannerDetail, 0, len(banners))
+	for _, banner := range banners {
+		pool, err := banner.Pool(collections) //nolint:govet // This is a valid code
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, &BannerDetail{Banner: banner, Pool: pool})
+	}
+	return details, nil
+}
+
+func (guc *gameUseCase) GetDropRates(ctx context.Context, bannerID string) (*model.DropRates, error) {
+	rates, err := guc.drcr.Get(ctx, model.DropRatesCacheKey, bannerID)
+	if err == nil {
+		return rates, nil
+	} else if !errors.Is(err, config.ErrCacheMiss) {
+		log.Error("Error getting drop rates from cache", log.Ferror(err))
+		return nil, err
 	}
-	user, err := guc.ur.Get(ctx, userID)
+
+	banner, err := guc.br.Get(ctx, bannerID)
 	if err != nil {
-		log.Error("Error getting user", log.Fstring("user_id", userID))
+		log.Error("Error getting banner", log.Fstring("banner_id", bannerID), log.Ferror(err))
+		return nil, err
+	}
+	collections, err := guc.listCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := banner.Pool(collections)
+	if err != nil {
+		return nil, err
+	}
+
+	rates = model.NewDropRates(banner.ID, pool)
+	if err = guc.drcr.Create(ctx, model.DropRatesCacheKey, *rates); err != nil {
+		log.Error("Error setting drop rates to cache", log.Ferror(err))
 		return nil, err
 	}
+	return rates, nil
+}
 
-	collections, err := guc.ccr.Get(ctx, "collections")
+// listCollections 全てのアイテムをキャッシュから取得し、キャッシュがなければDBから取得してキャッシュする
+func (guc *gameUseCase) listCollections(ctx context.Context) (model.Collections, error) {
+	collections, err := guc.ccr.Get(ctx, model.CollectionsCacheKey)
 	if errors.Is(err, config.ErrCacheMiss) {
-		log.Info("Cache miss", log.Fstring("key", "collections"))
+		log.Info("Cache miss", log.Fstring("key", model.CollectionsCacheKey))
 		collections, err = guc.cr.List(ctx)
 		if err != nil {
 			log.Error("Error getting collections", log.Ferror(err))
 			return nil, err
 		}
-		if err = guc.ccr.Create(ctx, "collections", collections); err != nil {
+		if err = guc.ccr.Create(ctx, model.CollectionsCacheKey, collections); err != nil {
 			log.Error("Error setting collections to cache", log.Ferror(err))
 			return nil, err
 		}
@@ -135,54 +401,151 @@ func (guc *gameUseCase) DrawGacha(ctx context.Context, times int) ([]*GachaResul
 		log.Error("Error getting collections from cache", log.Ferror(err))
 		return nil, err
 	}
+	return collections, nil
+}
 
-	var gacha model.Gacha
-	results := make(model.Collections, 0, times)
-	for i := 0; i < times; i++ {
-		result, err := gacha.Draw(collections) //nolint:govet // This is a valid code
-		if err != nil {
-			log.Error("Failed to draw gacha", log.Ferror(err))
-			return nil, err
-		}
-		results = append(results, result)
+func (guc *gameUseCase) DrawGacha(ctx context.Context, bannerID string, times int) (*GachaDraw, error) { //nolint:gocognit // This is a valid code
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
 	}
+	userID := principal.UserID
 
-	userCollections, err := guc.ucr.List(ctx, userID)
+	banner, err := guc.br.Get(ctx, bannerID)
 	if err != nil {
-		log.Error("Error getting user collections", log.Fstring("user_id", userID))
+		log.Error("Error getting banner", log.Fstring("banner_id", bannerID), log.Ferror(err))
 		return nil, err
 	}
-	userCollectionMap := make(map[string]bool)
-	for _, item := range userCollections {
-		userCollectionMap[item.CollectionID] = true
+	if !banner.IsActive(time.Now()) {
+		log.Info("Banner is not active", log.Fstring("banner_id", bannerID))
+		return nil, model.ErrBannerInactive
 	}
-	var gachaResults []*GachaResult
-	for _, item := range results {
-		gachaResult := &GachaResult{
-			Collection: item,
-			Has:        userCollectionMap[item.ID],
+
+	version, err := guc.ccr.Version(ctx, model.CollectionsCacheKey)
+	if err != nil {
+		log.Error("Error getting collections cache version", log.Ferror(err))
+		return nil, err
+	}
+	pool, err := guc.pools.get(banner, version, time.Now(), func() (model.Collections, error) {
+		collections, err := guc.listCollections(ctx) //nolint:govet // This is a valid code
+		if err != nil {
+			return nil, err
 		}
-		gachaResults = append(gachaResults, gachaResult)
+		return banner.Pool(collections)
+	})
+	if err != nil {
+		log.Error("Failed to build gacha pool", log.Fstring("banner_id", banner.ID), log.Ferror(err))
+		return nil, err
 	}
+	cost := banner.DrawCost(times)
 
+	var gachaResults []*GachaResult
+	var payments []model.Payment
+	var pity *model.GachaPity
 	if err = guc.tr.Transaction(ctx, func(ctx context.Context) error {
-		user.Coins -= gacha.Cost(times)
-		if err = guc.ur.Update(ctx, *user); err != nil {
-			log.Error("Failed to update user", log.Ferror(err))
+		// 同じユーザの抽選を直列化し、所持状況と残高の判定が他のリクエストと競合しないようにする
+		user, err := guc.ur.GetForUpdate(ctx, userID) //nolint:govet // This is a valid code
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+		wallet, err := guc.wr.Get(ctx, user.ID)
+		if err != nil {
+			log.Error("Error getting wallet", log.Fstring("user_id", userID))
+			return err
+		}
+		if payments, err = wallet.Pay(banner.Currency, cost); err != nil {
+			log.Info(
+				"Insufficient balance",
+				log.Fstring("user_id", userID),
+				log.Fstring("currency", string(banner.Currency)),
+				log.Fint("cost", cost),
+			)
+			return err
+		}
+
+		pity, err = guc.gpr.GetForUpdate(ctx, userID, banner.ID)
+		if errors.Is(err, config.ErrNotFound) {
+			pity, err = model.NewGachaPity(userID, banner.ID)
+		}
+		if err != nil {
+			log.Error("Error getting gacha pity", log.Fstring("user_id", userID), log.Fstring("banner_id", banner.ID))
 			return err
 		}
 
-		var newUserCollections []*model.UserCollection
-		for _, result := range results {
-			newUserCollection, err := model.NewUserCollection(user.ID, result.ID) //nolint:govet // This is a valid code
+		results := guc.gacha.DrawMulti(pool, times, pity, guc.pityRules, banner.Guarantee)
+		if err = guc.gpr.Upsert(ctx, *pity); err != nil {
+			log.Error("Failed to update gacha pity", log.Ferror(err))
+			return err
+		}
+
+		// 消費した通貨と抽選の記録を辿れるよう、台帳には抽選IDを記録する
+		// walletはPayで支払い後の残高になっている
+		drawID := uuid.New().String()
+		for _, payment := range payments {
+			if err = guc.wr.Debit(ctx, user.ID, payment.Currency, payment.Amount); err != nil {
+				log.Error("Failed to debit balance", log.Fstring("currency", string(payment.Currency)), log.Ferror(err))
+				return err
+			}
+			if err = recordCoinTransaction(
+				ctx, guc.ctr, user.ID, payment.Currency, model.CoinReasonGachaDraw, -payment.Amount, wallet[payment.Currency], drawID,
+			); err != nil {
+				return err
+			}
+		}
+
+		userCollections, err := guc.ucr.List(ctx, userID) //nolint:govet // This is a valid code
+		if err != nil {
+			log.Error("Error getting user collections", log.Fstring("user_id", userID))
+			return err
+		}
+		owned := make(map[string]bool, len(userCollections))
+		for _, item := range userCollections {
+			owned[item.CollectionID] = true
+		}
+
+		// 同じ抽選内で2回目以降に排出されたアイテムも所持済みとして扱い、所持数に加算する
+		gachaResults = make([]*GachaResult, 0, len(results))
+		records := make([]*model.GachaDrawRecord, 0, len(results))
+		drawnAt := time.Now()
+		var obtained []*model.UserCollection
+		quantities := make(map[string]*model.UserCollection)
+		for slot, result := range results {
+			gachaResults = append(gachaResults, &GachaResult{
+				Collection: result.Collection,
+				Has:        owned[result.ID],
+			})
+			owned[result.ID] = true
+
+			record, err := model.NewGachaDrawRecord( //nolint:govet // This is a valid code
+				drawID, slot, user.ID, banner, result, guc.rngName, pool.Version, drawnAt,
+			)
+			if err != nil {
+				log.Error("Failed to create gacha draw record", log.Ferror(err))
+				return err
+			}
+			records = append(records, record)
+
+			if userCollection, ok := quantities[result.ID]; ok {
+				userCollection.Quantity++
+				continue
+			}
+			userCollection, err := model.NewUserCollection(user.ID, result.ID, 1, drawnAt)
 			if err != nil {
 				log.Error("Failed to create user collection", log.Ferror(err))
 				return err
 			}
-			newUserCollections = append(newUserCollections, newUserCollection)
+			quantities[result.ID] = userCollection
+			obtained = append(obtained, userCollection)
 		}
-		if err = guc.ucr.BatchCreate(ctx, newUserCollections); err != nil {
-			log.Error("Failed to create user collections", log.Ferror(err))
+
+		if err = guc.ucr.BatchIncrement(ctx, obtained); err != nil {
+			log.Error("Failed to add user collections", log.Ferror(err))
+			return err
+		}
+		if err = guc.gdr.BatchCreate(ctx, records); err != nil {
+			log.Error("Failed to record gacha draws", log.Fstring("draw_id", drawID), log.Ferror(err))
 			return err
 		}
 		return nil
@@ -190,5 +553,62 @@ func (guc *gameUseCase) DrawGacha(ctx context.Context, times int) ([]*GachaResul
 		return nil, err
 	}
 
-	return gachaResults, nil
+	return &GachaDraw{
+		Results:   gachaResults,
+		Payments:  payments,
+		PityCount: pity.Count,
+	}, nil
+}
+
+func (guc *gameUseCase) ListGachaHistory(ctx context.Context, cursor string, limit int) ([]*model.GachaDrawRecord, string, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, "", fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
+
+	after, limit, err := parseGachaDrawPage(cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 1件多く取得し、続きがあるかどうかを判定する
+	records, err := guc.gdr.List(ctx, userID, after, limit+1)
+	if err != nil {
+		log.Error("Failed to list gacha draws", log.Fstring("user_id", userID), log.Ferror(err))
+		return nil, "", err
+	}
+	records, next := nextGachaDrawPage(records, limit)
+	return records, next, nil
+}
+
+// parseGachaDrawPage カーソルを復元し、limitを既定値と上限の範囲に収める
+func parseGachaDrawPage(cursor string, limit int) (*model.GachaDrawCursor, int, error) {
+	var after *model.GachaDrawCursor
+	if cursor != "" {
+		var err error
+		if after, err = model.DecodeGachaDrawCursor(cursor); err != nil {
+			log.Info("Invalid cursor", log.Fstring("cursor", cursor))
+			return nil, 0, err
+		}
+	}
+	if limit <= 0 {
+		limit = DefaultGachaHistoryLimit
+	}
+	if limit > MaxGachaHistoryLimit {
+		limit = MaxGachaHistoryLimit
+	}
+	return after, limit, nil
+}
+
+// nextGachaDrawPage limit+1件取得した記録をlimit件に切り詰め、続きがある場合は次のカーソルを返す
+func nextGachaDrawPage(records []*model.GachaDrawRecord, limit int) ([]*model.GachaDrawRecord, string) {
+	if len(records) <= limit {
+		return records, ""
+	}
+	records = records[:limit]
+	last := records[len(records)-1]
+	next := model.GachaDrawCursor{CreatedAt: last.CreatedAt, DrawID: last.DrawID, Slot: last.Slot}
+	return records, next.Encode()
 }
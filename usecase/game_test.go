@@ -2,7 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -10,13 +14,34 @@ import (
 	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
 )
 
+var testGameSessionConfig = GameSessionConfig{
+	Secret: []byte("secret"),
+	Rules: model.GameSessionRules{
+		MinDuration:       10 * time.Second,
+		MaxDuration:       30 * time.Minute,
+		MaxScorePerSecond: 50,
+	},
+}
+
+// startedGameSession 1分前に開始した未終了のセッション
+func startedGameSession(id, userID string) *model.GameSession {
+	return &model.GameSession{
+		ID:        id,
+		UserID:    userID,
+		StartedAt: time.Now().Add(-time.Minute),
+	}
+}
+
 func TestUserUseCase_FinishGame(t *testing.T) {
 	t.Parallel()
 
 	userID := uuid.New().String()
-	ctx := context.WithValue(context.Background(), config.ContextUserIDKey, userID)
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+	sessionID := uuid.New().String()
+	sessionToken := auth.SignGameSession(testGameSessionConfig.Secret, sessionID)
 
 	patterns := []struct {
 		name  string
@@ -25,10 +50,14 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 			m1 *mock.MockUserRepository,
 			m2 *mock.MockScoreRepository,
 			m3 *mock.MockRankingRepository,
+			m4 *mock.MockCoinTransactionRepository,
+			m5 *mock.MockWalletRepository,
+			m6 *mock.MockGameSessionRepository,
 		)
 		arg struct {
-			ctx   context.Context
-			score int
+			ctx          context.Context
+			sessionToken string
+			score        int
 		}
 		want struct {
 			coin int
@@ -37,18 +66,27 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 	}{
 		{
 			name: "Success: with high score",
-			setup: func(tr *mock.MockTransactionRepository, ur *mock.MockUserRepository, sr *mock.MockScoreRepository, rr *mock.MockRankingRepository) {
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				sr *mock.MockScoreRepository,
+				rr *mock.MockRankingRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				gsr *mock.MockGameSessionRepository,
+			) {
 				user := model.User{
 					ID:        userID,
 					Name:      "test",
 					Email:     "test@gmail.com",
-					Coins:     100,
 					HighScore: 1000,
 				}
-				ur.EXPECT().Get(ctx, userID).Return(&user, nil)
 				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 					return fn(ctx)
 				})
+				gsr.EXPECT().GetForUpdate(ctx, sessionID).Return(startedGameSession(sessionID, userID), nil)
+				gsr.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(&user, nil)
 				sr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 				ur.EXPECT().Update(
 					gomock.Any(),
@@ -56,10 +94,12 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 						ID:        user.ID,
 						Name:      user.Name,
 						Email:     user.Email,
-						Coins:     2600,
 						HighScore: 1200,
 					},
 				).Return(nil)
+				wr.EXPECT().Get(gomock.Any(), userID).Return(model.Wallet{model.CurrencyCoin: 100, model.CurrencyGem: 50}, nil)
+				wr.EXPECT().Credit(gomock.Any(), userID, model.CurrencyCoin, 2500).Return(nil)
+				ctr.EXPECT().Create(gomock.Any(), coinTransactionOf(model.CurrencyCoin, model.CoinReasonGameReward, 2500, 2600)).Return(nil)
 				rr.EXPECT().Create(
 					gomock.Any(),
 					model.ScoreBoardKey,
@@ -70,11 +110,13 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 				).Return(nil)
 			},
 			arg: struct {
-				ctx   context.Context
-				score int
+				ctx          context.Context
+				sessionToken string
+				score        int
 			}{
-				ctx:   ctx,
-				score: 1200,
+				ctx:          ctx,
+				sessionToken: sessionToken,
+				score:        1200,
 			},
 			want: struct {
 				coin int
@@ -89,18 +131,27 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 		},
 		{
 			name: "Success: with low score",
-			setup: func(tr *mock.MockTransactionRepository, ur *mock.MockUserRepository, sr *mock.MockScoreRepository, rr *mock.MockRankingRepository) {
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				sr *mock.MockScoreRepository,
+				rr *mock.MockRankingRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				gsr *mock.MockGameSessionRepository,
+			) {
 				user := model.User{
 					ID:        userID,
 					Name:      "test",
 					Email:     "test@gmail.com",
-					Coins:     100,
 					HighScore: 1000,
 				}
-				ur.EXPECT().Get(ctx, userID).Return(&user, nil)
 				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 					return fn(ctx)
 				})
+				gsr.EXPECT().GetForUpdate(ctx, sessionID).Return(startedGameSession(sessionID, userID), nil)
+				gsr.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(&user, nil)
 				sr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 				ur.EXPECT().Update(
 					gomock.Any(),
@@ -108,10 +159,12 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 						ID:        user.ID,
 						Name:      user.Name,
 						Email:     user.Email,
-						Coins:     400,
 						HighScore: 1000,
 					},
 				).Return(nil)
+				wr.EXPECT().Get(gomock.Any(), userID).Return(model.Wallet{model.CurrencyCoin: 100}, nil)
+				wr.EXPECT().Credit(gomock.Any(), userID, model.CurrencyCoin, 300).Return(nil)
+				ctr.EXPECT().Create(gomock.Any(), coinTransactionOf(model.CurrencyCoin, model.CoinReasonGameReward, 300, 400)).Return(nil)
 				rr.EXPECT().Create(
 					gomock.Any(),
 					model.ScoreBoardKey,
@@ -122,11 +175,13 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 				).Return(nil)
 			},
 			arg: struct {
-				ctx   context.Context
-				score int
+				ctx          context.Context
+				sessionToken string
+				score        int
 			}{
-				ctx:   ctx,
-				score: 100,
+				ctx:          ctx,
+				sessionToken: sessionToken,
+				score:        100,
 			},
 			want: struct {
 				coin int
@@ -139,6 +194,91 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "Fail: session already finished",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				sr *mock.MockScoreRepository,
+				rr *mock.MockRankingRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				gsr *mock.MockGameSessionRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				session := startedGameSession(sessionID, userID)
+				finishedAt := time.Now()
+				session.FinishedAt = &finishedAt
+				gsr.EXPECT().GetForUpdate(ctx, sessionID).Return(session, nil)
+			},
+			arg: struct {
+				ctx          context.Context
+				sessionToken string
+				score        int
+			}{
+				ctx:          ctx,
+				sessionToken: sessionToken,
+				score:        100,
+			},
+			want: struct {
+				coin int
+				err  error
+			}{
+				err: model.ErrGameSessionFinished,
+			},
+		},
+		{
+			name: "Fail: implausible score",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				sr *mock.MockScoreRepository,
+				rr *mock.MockRankingRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				gsr *mock.MockGameSessionRepository,
+			) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				gsr.EXPECT().GetForUpdate(ctx, sessionID).Return(startedGameSession(sessionID, userID), nil)
+			},
+			arg: struct {
+				ctx          context.Context
+				sessionToken string
+				score        int
+			}{
+				ctx:          ctx,
+				sessionToken: sessionToken,
+				score:        1000000,
+			},
+			want: struct {
+				coin int
+				err  error
+			}{
+				err: model.ErrImplausibleScore,
+			},
+		},
+		{
+			name: "Fail: forged session token",
+			arg: struct {
+				ctx          context.Context
+				sessionToken string
+				score        int
+			}{
+				ctx:          ctx,
+				sessionToken: auth.SignGameSession([]byte("forged"), sessionID),
+				score:        100,
+			},
+			want: struct {
+				coin int
+				err  error
+			}{
+				err: model.ErrGameSessionInvalid,
+			},
+		},
 	}
 
 	for _, tt := range patterns {
@@ -154,13 +294,16 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 			rr := mock.NewMockRankingRepository(ctrl)
 			cr := mock.NewMockCollectionRepository(ctrl)
 			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
+			gsr := mock.NewMockGameSessionRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(tr, ur, sr, rr)
+				tt.setup(tr, ur, sr, rr, ctr, wr, gsr)
 			}
 
-			usecase := NewGameUseCase(tr, ur, ucr, sr, rr, cr, ccr)
-			coin, err := usecase.FinishGame(tt.arg.ctx, tt.arg.score)
+			usecase := NewGameUseCase(tr, ur, ucr, sr, rr, cr, ccr, mock.NewMockDropRatesCacheRepository(ctrl), ctr, wr, gsr, mock.NewMockBannerRepository(ctrl), mock.NewMockGachaPityRepository(ctrl), mock.NewMockGachaDrawRepository(ctrl), testGameSessionConfig, testPityRules, model.NewSeededRandomSource(1))
+			coin, err := usecase.FinishGame(tt.arg.ctx, tt.arg.sessionToken, tt.arg.score)
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("FinishGame() error = %v, wantErr %v", err, tt.want.err)
@@ -175,10 +318,59 @@ func TestUserUseCase_FinishGame(t *testing.T) {
 	}
 }
 
+const testBannerCost = 100
+
+var testPityRules = model.PityRules{SoftPity: 5, SoftPityRateStep: 0.1, HardPity: 10}
+
+// activeBanner 開催中で、collectionsをマスタの重みのまま排出するバナー
+func activeBanner(id string, collections ...*model.Collection) *model.Banner {
+	items := make([]model.BannerItem, 0, len(collections))
+	for _, c := range collections {
+		items = append(items, model.BannerItem{CollectionID: c.ID, Weight: c.Weight})
+	}
+	return &model.Banner{
+		ID:       id,
+		Name:     "banner",
+		Cost:     testBannerCost,
+		Currency: model.CurrencyCoin,
+		StartAt:  time.Now().Add(-time.Hour),
+		EndAt:    time.Now().Add(time.Hour),
+		Items:    items,
+	}
+}
+
+// userCollectionsMatcher 入手日時を除いて、アイテムごとの所持数の加算を比較する
+type userCollectionsMatcher struct {
+	userID     string
+	quantities map[string]int
+}
+
+func userCollectionsOf(userID string, quantities map[string]int) gomock.Matcher {
+	return userCollectionsMatcher{userID: userID, quantities: quantities}
+}
+
+func (m userCollectionsMatcher) Matches(x interface{}) bool {
+	userCollections, ok := x.([]*model.UserCollection)
+	if !ok || len(userCollections) != len(m.quantities) {
+		return false
+	}
+	for _, uc := range userCollections {
+		if uc.UserID != m.userID || uc.Quantity != m.quantities[uc.CollectionID] || uc.FirstObtainedAt.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func (m userCollectionsMatcher) String() string {
+	return fmt.Sprintf("user collections of %s with quantities %v", m.userID, m.quantities)
+}
+
 func TestUsecase_DrawGacha(t *testing.T) {
 	t.Parallel()
 
 	userID := uuid.New().String()
+	bannerID := uuid.New().String()
 	collection1ID := uuid.New().String()
 	collection2ID := uuid.New().String()
 	collection3ID := uuid.New().String()
@@ -188,9 +380,8 @@ func TestUsecase_DrawGacha(t *testing.T) {
 		ID:    userID,
 		Name:  "test",
 		Email: "test@gmail.com",
-		Coins: 10000,
 	}
-	ctx := context.WithValue(context.Background(), config.ContextUserIDKey, userID)
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
 	collections := model.Collections{
 		{
 			ID:     collection1ID,
@@ -236,14 +427,21 @@ func TestUsecase_DrawGacha(t *testing.T) {
 			m2 *mock.MockCollectionRepository,
 			m3 *mock.MockCollectionCacheRepository,
 			m4 *mock.MockUserCollectionRepository,
+			m5 *mock.MockCoinTransactionRepository,
+			m6 *mock.MockWalletRepository,
+			m7 *mock.MockBannerRepository,
+			m8 *mock.MockGachaPityRepository,
+			m9 *mock.MockGachaDrawRepository,
 		)
 		arg struct {
-			ctx   context.Context
-			times int
+			ctx      context.Context
+			bannerID string
+			times    int
 		}
 		want struct {
-			results []*GachaResult
-			err     error
+			results   []*GachaResult
+			pityCount int
+			err       error
 		}
 	}{
 		{
@@ -254,8 +452,14 @@ func TestUsecase_DrawGacha(t *testing.T) {
 				cr *mock.MockCollectionRepository,
 				ccr *mock.MockCollectionCacheRepository,
 				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
 			) {
-				ur.EXPECT().Get(ctx, userID).Return(user, nil)
+				br.EXPECT().Get(ctx, bannerID).Return(activeBanner(bannerID, collections...), nil)
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
 				ccr.EXPECT().Get(ctx, "collections").Return(
 					collections,
 					nil,
@@ -264,32 +468,534 @@ func TestUsecase_DrawGacha(t *testing.T) {
 				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 					return fn(ctx)
 				})
-				ur.EXPECT().Update(ctx, model.User{
-					ID:    userID,
-					Name:  "test",
-					Email: "test@gmail.com",
-					Coins: 10000 - 2*model.GachaCost,
-				}).Return(nil)
-				ucr.EXPECT().BatchCreate(
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(nil, config.ErrNotFound)
+				gpr.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000}, nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, 2*testBannerCost).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyCoin, model.CoinReasonGachaDraw, -2*testBannerCost, 10000-2*testBannerCost)).Return(nil)
+				ucr.EXPECT().BatchIncrement(
 					ctx,
 					gomock.Any(),
 				).Return(nil)
+				gdr.EXPECT().BatchCreate(ctx, gomock.Any()).Return(nil)
 			},
 			arg: struct {
-				ctx   context.Context
-				times int
+				ctx      context.Context
+				bannerID string
+				times    int
 			}{
-				ctx:   ctx,
-				times: 2,
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    2,
 			},
 			want: struct {
-				results []*GachaResult
-				err     error
+				results   []*GachaResult
+				pityCount int
+				err       error
 			}{
 				results: nil,
 				err:     nil,
 			},
 		},
+		{
+			name: "success: duplicates within a multi-draw are added to the quantity",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				// 排出されるアイテムを1種類にして必ず重複させる
+				br.EXPECT().Get(ctx, bannerID).Return(activeBanner(bannerID, collections[2]), nil)
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(nil, config.ErrNotFound)
+				gpr.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000}, nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, 3*testBannerCost).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyCoin, model.CoinReasonGachaDraw, -3*testBannerCost, 10000-3*testBannerCost)).Return(nil)
+				ucr.EXPECT().List(ctx, userID).Return(userCollections, nil)
+				ucr.EXPECT().BatchIncrement(ctx, userCollectionsOf(userID, map[string]int{collection3ID: 3})).Return(nil)
+				// 抽選の記録は枠ごとに、バナーの価格と通貨を持つ。内容が異なる場合は抽選を失敗させる
+				gdr.EXPECT().BatchCreate(ctx, gomock.Len(3)).DoAndReturn(func(_ context.Context, records []*model.GachaDrawRecord) error {
+					for i, record := range records {
+						if record.Slot != i || record.UserID != userID || record.BannerID != bannerID ||
+							record.CollectionID != collection3ID || record.Cost != testBannerCost || record.Currency != model.CurrencyCoin ||
+							record.RNG != "math/rand(seed=1)" {
+							return fmt.Errorf("unexpected records[%d] = %+v", i, record)
+						}
+						if record.DrawID != records[0].DrawID || record.PoolVersion == "" {
+							return fmt.Errorf("records[%d] = %+v, want the same draw", i, record)
+						}
+					}
+					return nil
+				})
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    3,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: []*GachaResult{
+					{Collection: collections[2], Has: false},
+					{Collection: collections[2], Has: true},
+					{Collection: collections[2], Has: true},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "success: owned item is added to the quantity",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				br.EXPECT().Get(ctx, bannerID).Return(activeBanner(bannerID, collections[0]), nil)
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(nil, config.ErrNotFound)
+				gpr.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000}, nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, testBannerCost).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyCoin, model.CoinReasonGachaDraw, -testBannerCost, 10000-testBannerCost)).Return(nil)
+				ucr.EXPECT().List(ctx, userID).Return(userCollections, nil)
+				ucr.EXPECT().BatchIncrement(ctx, userCollectionsOf(userID, map[string]int{collection1ID: 1})).Return(nil)
+				gdr.EXPECT().BatchCreate(ctx, gomock.Any()).Return(nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: []*GachaResult{
+					{Collection: collections[0], Has: true},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "success: hard pity guarantees the top rarity",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				// 最高レアリティの排出率を極端に下げても、天井では必ず排出される
+				banner := activeBanner(bannerID, collections[0], collections[3])
+				banner.Items[0].Weight = 1 << 20
+				banner.Items[1].Weight = 1
+				br.EXPECT().Get(ctx, bannerID).Return(banner, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(&model.GachaPity{
+					UserID:   userID,
+					BannerID: bannerID,
+					Count:    testPityRules.HardPity - 1,
+				}, nil)
+				gpr.EXPECT().Upsert(ctx, model.GachaPity{UserID: userID, BannerID: bannerID, Count: 0}).Return(nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000}, nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, testBannerCost).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyCoin, model.CoinReasonGachaDraw, -testBannerCost, 10000-testBannerCost)).Return(nil)
+				ucr.EXPECT().List(ctx, userID).Return(userCollections, nil)
+				ucr.EXPECT().BatchIncrement(ctx, userCollectionsOf(userID, map[string]int{collection4ID: 1})).Return(nil)
+				gdr.EXPECT().BatchCreate(ctx, gomock.Any()).Return(nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: []*GachaResult{
+					{Collection: &model.Collection{ID: collection4ID, Name: "collection4", Rarity: 4, Weight: 1}, Has: false},
+				},
+				pityCount: 0,
+				err:       nil,
+			},
+		},
+		{
+			name: "success: multi-draw guarantee of the banner",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				// レアリティ2以上の排出率を極端に下げても、2回ごとに1つは排出される
+				banner := activeBanner(bannerID, collections[0], collections[1])
+				banner.Items[0].Weight = 1 << 20
+				banner.Items[1].Weight = 1
+				banner.Guarantee = model.MultiDrawRule{Draws: 2, MinRarity: 2}
+				br.EXPECT().Get(ctx, bannerID).Return(banner, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(nil, config.ErrNotFound)
+				gpr.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000}, nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, 2*testBannerCost).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyCoin, model.CoinReasonGachaDraw, -2*testBannerCost, 10000-2*testBannerCost)).Return(nil)
+				ucr.EXPECT().List(ctx, userID).Return(userCollections, nil)
+				ucr.EXPECT().BatchIncrement(ctx, userCollectionsOf(userID, map[string]int{collection1ID: 1, collection2ID: 1})).Return(nil)
+				gdr.EXPECT().BatchCreate(ctx, gomock.Any()).Return(nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    2,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: []*GachaResult{
+					{Collection: &model.Collection{ID: collection1ID, Name: "collection1", Rarity: 1, Weight: 1 << 20}, Has: true},
+					{Collection: &model.Collection{ID: collection2ID, Name: "collection2", Rarity: 2, Weight: 1}, Has: true},
+				},
+				pityCount: 0,
+				err:       nil,
+			},
+		},
+		{
+			name: "success: paid gems cover the shortage of free coins",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				br.EXPECT().Get(ctx, bannerID).Return(activeBanner(bannerID, collections[0]), nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 30, model.CurrencyGem: 500}, nil)
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(nil, config.ErrNotFound)
+				gpr.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+				// 無償のコインを使い切ってから、足りない分を有償のジェムで支払う
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, 30).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyCoin, model.CoinReasonGachaDraw, -30, 0)).Return(nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyGem, testBannerCost-30).Return(nil)
+				ctr.EXPECT().Create(ctx, coinTransactionOf(model.CurrencyGem, model.CoinReasonGachaDraw, -(testBannerCost-30), 500-(testBannerCost-30))).Return(nil)
+				ucr.EXPECT().List(ctx, userID).Return(userCollections, nil)
+				ucr.EXPECT().BatchIncrement(ctx, userCollectionsOf(userID, map[string]int{collection1ID: 1})).Return(nil)
+				gdr.EXPECT().BatchCreate(ctx, gomock.Any()).Return(nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: []*GachaResult{
+					{Collection: collections[0], Has: true},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "Fail: gem-priced banner cannot be paid with free coins",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				banner := activeBanner(bannerID, collections...)
+				banner.Currency = model.CurrencyGem
+				br.EXPECT().Get(ctx, bannerID).Return(banner, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000, model.CurrencyGem: testBannerCost - 1}, nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: nil,
+				err:     model.ErrInsufficientBalance,
+			},
+		},
+		{
+			name: "Fail: insufficient coins",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				br.EXPECT().Get(ctx, bannerID).Return(activeBanner(bannerID, collections...), nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: testBannerCost - 1}, nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: nil,
+				err:     model.ErrInsufficientBalance,
+			},
+		},
+		{
+			name: "Fail: balance spent concurrently",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				br.EXPECT().Get(ctx, bannerID).Return(activeBanner(bannerID, collections...), nil)
+				ur.EXPECT().GetForUpdate(ctx, userID).Return(user, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(
+					collections,
+					nil,
+				)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				gpr.EXPECT().GetForUpdate(ctx, userID, bannerID).Return(nil, config.ErrNotFound)
+				gpr.EXPECT().Upsert(ctx, gomock.Any()).Return(nil)
+				wr.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 10000}, nil)
+				wr.EXPECT().Debit(ctx, userID, model.CurrencyCoin, testBannerCost).Return(model.ErrInsufficientBalance)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: nil,
+				err:     model.ErrInsufficientBalance,
+			},
+		},
+		{
+			name: "Fail: banner not found",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				br.EXPECT().Get(ctx, bannerID).Return(nil, config.ErrNotFound)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: nil,
+				err:     config.ErrNotFound,
+			},
+		},
+		{
+			name: "Fail: banner is not active",
+			setup: func(
+				tr *mock.MockTransactionRepository,
+				ur *mock.MockUserRepository,
+				cr *mock.MockCollectionRepository,
+				ccr *mock.MockCollectionCacheRepository,
+				ucr *mock.MockUserCollectionRepository,
+				ctr *mock.MockCoinTransactionRepository,
+				wr *mock.MockWalletRepository,
+				br *mock.MockBannerRepository,
+				gpr *mock.MockGachaPityRepository,
+				gdr *mock.MockGachaDrawRepository,
+			) {
+				banner := activeBanner(bannerID, collections...)
+				banner.StartAt, banner.EndAt = time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)
+				br.EXPECT().Get(ctx, bannerID).Return(banner, nil)
+			},
+			arg: struct {
+				ctx      context.Context
+				bannerID string
+				times    int
+			}{
+				ctx:      ctx,
+				bannerID: bannerID,
+				times:    1,
+			},
+			want: struct {
+				results   []*GachaResult
+				pityCount int
+				err       error
+			}{
+				results: nil,
+				err:     model.ErrBannerInactive,
+			},
+		},
 	}
 
 	for _, tt := range patterns {
@@ -305,13 +1011,21 @@ func TestUsecase_DrawGacha(t *testing.T) {
 			rr := mock.NewMockRankingRepository(ctrl)
 			cr := mock.NewMockCollectionRepository(ctrl)
 			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			// 抽選用の表はテストごとに作り直されるため、Versionは固定でよい
+			ccr.EXPECT().Version(gomock.Any(), "collections").Return(int64(1), nil).AnyTimes()
+			ctr := mock.NewMockCoinTransactionRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
+			gsr := mock.NewMockGameSessionRepository(ctrl)
+			br := mock.NewMockBannerRepository(ctrl)
+			gpr := mock.NewMockGachaPityRepository(ctrl)
+			gdr := mock.NewMockGachaDrawRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(tr, ur, cr, ccr, ucr)
+				tt.setup(tr, ur, cr, ccr, ucr, ctr, wr, br, gpr, gdr)
 			}
 
-			usecase := NewGameUseCase(tr, ur, ucr, sr, rr, cr, ccr)
-			_, err := usecase.DrawGacha(tt.arg.ctx, tt.arg.times)
+			usecase := NewGameUseCase(tr, ur, ucr, sr, rr, cr, ccr, mock.NewMockDropRatesCacheRepository(ctrl), ctr, wr, gsr, br, gpr, gdr, testGameSessionConfig, testPityRules, model.NewSeededRandomSource(1))
+			gachaDraw, err := usecase.DrawGacha(tt.arg.ctx, tt.arg.bannerID, tt.arg.times)
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("DrawGacha() error = %v, wantErr %v", err, tt.want.err)
@@ -319,9 +1033,320 @@ func TestUsecase_DrawGacha(t *testing.T) {
 				t.Errorf("DrawGacha() error = %v, wantErr %v", err, tt.want.err)
 			}
 
-			// if !reflect.DeepEqual(getResults, tt.want.results) {
-			//  	t.Errorf("DrawGacha() results = %v, want %v", getResults, tt.want.results)
-			// }
+			if tt.want.results != nil {
+				if !reflect.DeepEqual(gachaDraw.Results, tt.want.results) {
+					t.Errorf("DrawGacha() results = %v, want %v", gachaDraw.Results, tt.want.results)
+				}
+				if gachaDraw.PityCount != tt.want.pityCount {
+					t.Errorf("DrawGacha() pity count = %v, want %v", gachaDraw.PityCount, tt.want.pityCount)
+				}
+			}
+		})
+	}
+}
+
+func TestUsecase_StartGame(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+
+	ctrl := gomock.NewController(t)
+	gsr := mock.NewMockGameSessionRepository(ctrl)
+	var created model.GameSession
+	gsr.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, session model.GameSession) error {
+		created = session
+		return nil
+	})
+
+	usecase := NewGameUseCase(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, gsr, nil, nil, nil, testGameSessionConfig, testPityRules, model.NewSeededRandomSource(1))
+	start, err := usecase.StartGame(ctx)
+	if err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+
+	if created.UserID != userID || created.FinishedAt != nil {
+		t.Errorf("StartGame() created session = %+v", created)
+	}
+	sessionID, err := auth.VerifyGameSession(testGameSessionConfig.Secret, start.SessionToken)
+	if err != nil || sessionID != created.ID {
+		t.Errorf("StartGame() token = %v, want a signed token of %v", start.SessionToken, created.ID)
+	}
+	if start.Seed != created.Seed || !start.StartedAt.Equal(created.StartedAt) {
+		t.Errorf("StartGame() = %+v, want seed %v and started at %v", start, created.Seed, created.StartedAt)
+	}
+}
+
+func TestUsecase_ListBanners(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	collections := model.Collections{
+		{ID: uuid.New().String(), Name: "collection1", Rarity: 1, Weight: 10},
+		{ID: uuid.New().String(), Name: "collection2", Rarity: 5, Weight: 1},
+	}
+	banner := activeBanner(uuid.New().String(), collections[1])
+	banner.Items[0].Weight = 50
+	banner.Items[0].RateUp = true
+
+	ctrl := gomock.NewController(t)
+	br := mock.NewMockBannerRepository(ctrl)
+	br.EXPECT().ListActive(ctx, gomock.Any()).Return([]*model.Banner{banner}, nil)
+	ccr := mock.NewMockCollectionCacheRepository(ctrl)
+	ccr.EXPECT().Get(ctx, "collections").Return(nil, config.ErrCacheMiss)
+	cr := mock.NewMockCollectionRepository(ctrl)
+	cr.EXPECT().List(ctx).Return(collections, nil)
+	ccr.EXPECT().Create(ctx, "collections", collections).Return(nil)
+
+	usecase := NewGameUseCase(nil, nil, nil, nil, nil, cr, ccr, nil, nil, nil, nil, br, nil, nil, testGameSessionConfig, testPityRules, model.NewSeededRandomSource(1))
+	banners, err := usecase.ListBanners(ctx)
+	if err != nil {
+		t.Fatalf("ListBanners() error = %v", err)
+	}
+
+	want := []*BannerDetail{
+		{
+			Banner: banner,
+			Pool:   model.Collections{{ID: collections[1].ID, Name: "collection2", Rarity: 5, Weight: 50}},
+		},
+	}
+	if !reflect.DeepEqual(banners, want) {
+		t.Errorf("ListBanners() = %v, want %v", banners, want)
+	}
+}
+
+func TestUsecase_GetDropRates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bannerID := uuid.New().String()
+	collections := model.Collections{
+		{ID: uuid.New().String(), Name: "collection1", Rarity: 1, Weight: 3},
+		{ID: uuid.New().String(), Name: "collection2", Rarity: 2, Weight: 1},
+	}
+	banner := activeBanner(bannerID, collections...)
+	rates := model.NewDropRates(bannerID, collections)
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockDropRatesCacheRepository,
+			m1 *mock.MockBannerRepository,
+			m2 *mock.MockCollectionCacheRepository,
+		)
+		want    *model.DropRates
+		wantErr error
+	}{
+		{
+			name: "success: cache hit",
+			setup: func(drcr *mock.MockDropRatesCacheRepository, br *mock.MockBannerRepository, ccr *mock.MockCollectionCacheRepository) {
+				drcr.EXPECT().Get(ctx, model.DropRatesCacheKey, bannerID).Return(rates, nil)
+			},
+			want: rates,
+		},
+		{
+			name: "success: cache miss",
+			setup: func(drcr *mock.MockDropRatesCacheRepository, br *mock.MockBannerRepository, ccr *mock.MockCollectionCacheRepository) {
+				drcr.EXPECT().Get(ctx, model.DropRatesCacheKey, bannerID).Return(nil, config.ErrCacheMiss)
+				br.EXPECT().Get(ctx, bannerID).Return(banner, nil)
+				ccr.EXPECT().Get(ctx, "collections").Return(collections, nil)
+				drcr.EXPECT().Create(ctx, model.DropRatesCacheKey, *rates).Return(nil)
+			},
+			want: rates,
+		},
+		{
+			name: "Fail: banner not found",
+			setup: func(drcr *mock.MockDropRatesCacheRepository, br *mock.MockBannerRepository, ccr *mock.MockCollectionCacheRepository) {
+				drcr.EXPECT().Get(ctx, model.DropRatesCacheKey, bannerID).Return(nil, config.ErrCacheMiss)
+				br.EXPECT().Get(ctx, bannerID).Return(nil, config.ErrNotFound)
+			},
+			wantErr: config.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			drcr := mock.NewMockDropRatesCacheRepository(ctrl)
+			br := mock.NewMockBannerRepository(ctrl)
+			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			tt.setup(drcr, br, ccr)
+
+			usecase := NewGameUseCase(nil, nil, nil, nil, nil, nil, ccr, drcr, nil, nil, nil, br, nil, nil, testGameSessionConfig, testPityRules, model.NewSeededRandomSource(1))
+			got, err := usecase.GetDropRates(ctx, bannerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetDropRates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDropRates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsecase_GachaPoolCache(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	banner := &model.Banner{ID: "banner", EndAt: now.Add(time.Hour)}
+	ended := &model.Banner{ID: "ended", EndAt: now.Add(time.Minute)}
+	items := model.Collections{
+		{ID: "a", Name: "A", Rarity: 1, Weight: 9},
+		{ID: "b", Name: "B", Rarity: 3, Weight: 1},
+	}
+	var loads int
+	load := func(items model.Collections) func() (model.Collections, error) {
+		return func() (model.Collections, error) {
+			loads++
+			return items, nil
+		}
+	}
+
+	cache := newGachaPoolCache()
+	pool, err := cache.get(banner, 1, now, load(items))
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	// Versionが同じ間は排出対象を取得せずに同じ表を使い回す
+	if got, _ := cache.get(banner, 1, now, load(items)); got != pool || loads != 1 {
+		t.Errorf("get() should reuse the pool while the version is unchanged, loads = %v", loads)
+	}
+	// Versionが変わった場合は作り直す
+	changed := model.Collections{items[0], {ID: "b", Name: "B", Rarity: 3, Weight: 2}}
+	if got, _ := cache.get(banner, 2, now, load(changed)); got == pool || got.Version != changed.Fingerprint() {
+		t.Error("get() should rebuild the pool when the version changes")
+	}
+	// 開催が終了したバナーの表は破棄する
+	if _, err = cache.get(ended, 2, now, load(items)); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if _, err = cache.get(banner, 2, now.Add(time.Minute), load(items)); err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if _, ok := cache.pools[ended.ID]; ok {
+		t.Error("get() should drop the pool of an ended banner")
+	}
+	if _, err = cache.get(&model.Banner{ID: "empty", EndAt: now.Add(time.Hour)}, 2, now, load(model.Collections{})); err == nil {
+		t.Error("get() with no items should return an error")
+	}
+}
+
+func TestUsecase_ListGachaHistory(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*model.GachaDrawRecord{
+		{DrawID: "2", Slot: 0, UserID: userID, CreatedAt: now},
+		{DrawID: "1", Slot: 1, UserID: userID, CreatedAt: now.Add(-time.Second)},
+		{DrawID: "1", Slot: 0, UserID: userID, CreatedAt: now.Add(-time.Second)},
+	}
+	cursor := model.GachaDrawCursor{CreatedAt: records[1].CreatedAt, DrawID: records[1].DrawID, Slot: records[1].Slot}
+
+	patterns := []struct {
+		name  string
+		setup func(m *mock.MockGachaDrawRepository)
+		arg   struct {
+			ctx    context.Context
+			cursor string
+			limit  int
+		}
+		want struct {
+			len        int
+			nextCursor string
+			err        error
+		}
+	}{
+		{
+			name: "success: has next page",
+			setup: func(m *mock.MockGachaDrawRepository) {
+				m.EXPECT().List(ctx, userID, nil, 3).Return(records, nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: ctx, limit: 2},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{len: 2, nextCursor: cursor.Encode()},
+		},
+		{
+			name: "success: last page",
+			setup: func(m *mock.MockGachaDrawRepository) {
+				m.EXPECT().List(ctx, userID, &cursor, DefaultGachaHistoryLimit+1).Return(records[2:], nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: ctx, cursor: cursor.Encode()},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{len: 1},
+		},
+		{
+			name: "Fail: invalid cursor",
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: ctx, cursor: "invalid"},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{err: model.ErrInvalidCursor},
+		},
+		{
+			name: "Fail: User ID not found in request context",
+			arg: struct {
+				ctx    context.Context
+				cursor string
+				limit  int
+			}{ctx: context.Background()},
+			want: struct {
+				len        int
+				nextCursor string
+				err        error
+			}{err: fmt.Errorf("user name not found in request context")},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			gdr := mock.NewMockGachaDrawRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(gdr)
+			}
+
+			usecase := NewGameUseCase(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, gdr, testGameSessionConfig, testPityRules, model.NewSeededRandomSource(1))
+			got, nextCursor, err := usecase.ListGachaHistory(tt.arg.ctx, tt.arg.cursor, tt.arg.limit)
+
+			if (err != nil) != (tt.want.err != nil) {
+				t.Fatalf("ListGachaHistory() error = %v, wantErr %v", err, tt.want.err)
+			} else if err != nil && err.Error() != tt.want.err.Error() {
+				t.Errorf("ListGachaHistory() error = %v, wantErr %v", err, tt.want.err)
+			}
+			if len(got) != tt.want.len {
+				t.Errorf("ListGachaHistory() len = %v, want %v", len(got), tt.want.len)
+			}
+			if nextCursor != tt.want.nextCursor {
+				t.Errorf("ListGachaHistory() nextCursor = %v, want %v", nextCursor, tt.want.nextCursor)
+			}
 		})
 	}
 }
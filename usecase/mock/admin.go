@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admin.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+	usecase "github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+// MockAdminUseCase is a mock of AdminUseCase interface.
+type MockAdminUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminUseCaseMockRecorder
+}
+
+// MockAdminUseCaseMockRecorder is the mock recorder for MockAdminUseCase.
+type MockAdminUseCaseMockRecorder struct {
+	mock *MockAdminUseCase
+}
+
+// NewMockAdminUseCase creates a new mock instance.
+func NewMockAdminUseCase(ctrl *gomock.Controller) *MockAdminUseCase {
+	mock := &MockAdminUseCase{ctrl: ctrl}
+	mock.recorder = &MockAdminUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminUseCase) EXPECT() *MockAdminUseCaseMockRecorder {
+	return m.recorder
+}
+
+// ListAuditLogs mocks base method.
+func (m *MockAdminUseCase) ListAuditLogs(ctx context.Context, targetID string) ([]*model.AdminAuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", ctx, targetID)
+	ret0, _ := ret[0].([]*model.AdminAuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockAdminUseCaseMockRecorder) ListAuditLogs(ctx, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockAdminUseCase)(nil).ListAuditLogs), ctx, targetID)
+}
+
+// ListGachaDraws mocks base method.
+func (m *MockAdminUseCase) ListGachaDraws(ctx context.Context, from, to time.Time, cursor string, limit int) ([]*model.GachaDrawRecord, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGachaDraws", ctx, from, to, cursor, limit)
+	ret0, _ := ret[0].([]*model.GachaDrawRecord)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListGachaDraws indicates an expected call of ListGachaDraws.
+func (mr *MockAdminUseCaseMockRecorder) ListGachaDraws(ctx, from, to, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGachaDraws", reflect.TypeOf((*MockAdminUseCase)(nil).ListGachaDraws), ctx, from, to, cursor, limit)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockAdminUseCase) UpdateUserRole(ctx context.Context, userID string, role model.Role, note string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, userID, role, note)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockAdminUseCaseMockRecorder) UpdateUserRole(ctx, userID, role, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockAdminUseCase)(nil).UpdateUserRole), ctx, userID, role, note)
+}
+
+// UpdateUserStats mocks base method.
+func (m *MockAdminUseCase) UpdateUserStats(ctx context.Context, userID string, update usecase.UserStatsUpdate) (*usecase.UserDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStats", ctx, userID, update)
+	ret0, _ := ret[0].(*usecase.UserDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStats indicates an expected call of UpdateUserStats.
+func (mr *MockAdminUseCaseMockRecorder) UpdateUserStats(ctx, userID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStats", reflect.TypeOf((*MockAdminUseCase)(nil).UpdateUserStats), ctx, userID, update)
+}
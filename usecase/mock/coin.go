@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: coin.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+)
+
+// MockCoinUseCase is a mock of CoinUseCase interface.
+type MockCoinUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoinUseCaseMockRecorder
+}
+
+// MockCoinUseCaseMockRecorder is the mock recorder for MockCoinUseCase.
+type MockCoinUseCaseMockRecorder struct {
+	mock *MockCoinUseCase
+}
+
+// NewMockCoinUseCase creates a new mock instance.
+func NewMockCoinUseCase(ctrl *gomock.Controller) *MockCoinUseCase {
+	mock := &MockCoinUseCase{ctrl: ctrl}
+	mock.recorder = &MockCoinUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoinUseCase) EXPECT() *MockCoinUseCaseMockRecorder {
+	return m.recorder
+}
+
+// ListCoinHistory mocks base method.
+func (m *MockCoinUseCase) ListCoinHistory(ctx context.Context, cursor string, limit int) ([]*model.CoinTransaction, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoinHistory", ctx, cursor, limit)
+	ret0, _ := ret[0].([]*model.CoinTransaction)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCoinHistory indicates an expected call of ListCoinHistory.
+func (mr *MockCoinUseCaseMockRecorder) ListCoinHistory(ctx, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoinHistory", reflect.TypeOf((*MockCoinUseCase)(nil).ListCoinHistory), ctx, cursor, limit)
+}
+
+// ReconcileCoins mocks base method.
+func (m *MockCoinUseCase) ReconcileCoins(ctx context.Context) ([]*model.CoinBalanceMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileCoins", ctx)
+	ret0, _ := ret[0].([]*model.CoinBalanceMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileCoins indicates an expected call of ReconcileCoins.
+func (mr *MockCoinUseCaseMockRecorder) ReconcileCoins(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileCoins", reflect.TypeOf((*MockCoinUseCase)(nil).ReconcileCoins), ctx)
+}
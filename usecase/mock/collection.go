@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: collection.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
+	usecase "github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+// MockCollectionUseCase is a mock of CollectionUseCase interface.
+type MockCollectionUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionUseCaseMockRecorder
+}
+
+// MockCollectionUseCaseMockRecorder is the mock recorder for MockCollectionUseCase.
+type MockCollectionUseCaseMockRecorder struct {
+	mock *MockCollectionUseCase
+}
+
+// NewMockCollectionUseCase creates a new mock instance.
+func NewMockCollectionUseCase(ctrl *gomock.Controller) *MockCollectionUseCase {
+	mock := &MockCollectionUseCase{ctrl: ctrl}
+	mock.recorder = &MockCollectionUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionUseCase) EXPECT() *MockCollectionUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollectionUseCase) CreateCollection(ctx context.Context, input usecase.CollectionInput) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", ctx, input)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionUseCaseMockRecorder) CreateCollection(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollectionUseCase)(nil).CreateCollection), ctx, input)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionUseCase) DeleteCollection(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionUseCaseMockRecorder) DeleteCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionUseCase)(nil).DeleteCollection), ctx, id)
+}
+
+// GetCollection mocks base method.
+func (m *MockCollectionUseCase) GetCollection(ctx context.Context, id string) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", ctx, id)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockCollectionUseCaseMockRecorder) GetCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockCollectionUseCase)(nil).GetCollection), ctx, id)
+}
+
+// ImportCollections mocks base method.
+func (m *MockCollectionUseCase) ImportCollections(ctx context.Context, inputs []usecase.CollectionInput) (model.Collections, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCollections", ctx, inputs)
+	ret0, _ := ret[0].(model.Collections)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCollections indicates an expected call of ImportCollections.
+func (mr *MockCollectionUseCaseMockRecorder) ImportCollections(ctx, inputs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCollections", reflect.TypeOf((*MockCollectionUseCase)(nil).ImportCollections), ctx, inputs)
+}
+
+// ListCollections mocks base method.
+func (m *MockCollectionUseCase) ListCollections(ctx context.Context) (model.Collections, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollections", ctx)
+	ret0, _ := ret[0].(model.Collections)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockCollectionUseCaseMockRecorder) ListCollections(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockCollectionUseCase)(nil).ListCollections), ctx)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionUseCase) UpdateCollection(ctx context.Context, id string, input usecase.CollectionInput) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", ctx, id, input)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionUseCaseMockRecorder) UpdateCollection(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionUseCase)(nil).UpdateCollection), ctx, id, input)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: craft.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+
+	usecase "github.com/tusmasoma/go-tech-dojo/usecase"
+)
+
+// MockCraftUseCase is a mock of CraftUseCase interface.
+type MockCraftUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCraftUseCaseMockRecorder
+}
+
+// MockCraftUseCaseMockRecorder is the mock recorder for MockCraftUseCase.
+type MockCraftUseCaseMockRecorder struct {
+	mock *MockCraftUseCase
+}
+
+// NewMockCraftUseCase creates a new mock instance.
+func NewMockCraftUseCase(ctrl *gomock.Controller) *MockCraftUseCase {
+	mock := &MockCraftUseCase{ctrl: ctrl}
+	mock.recorder = &MockCraftUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCraftUseCase) EXPECT() *MockCraftUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Craft mocks base method.
+func (m *MockCraftUseCase) Craft(ctx context.Context, collectionID string) (*usecase.CraftResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Craft", ctx, collectionID)
+	ret0, _ := ret[0].(*usecase.CraftResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Craft indicates an expected call of Craft.
+func (mr *MockCraftUseCaseMockRecorder) Craft(ctx, collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Craft", reflect.TypeOf((*MockCraftUseCase)(nil).Craft), ctx, collectionID)
+}
+
+// Dismantle mocks base method.
+func (m *MockCraftUseCase) Dismantle(ctx context.Context, collectionID string, quantity int) (*usecase.CraftResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dismantle", ctx, collectionID, quantity)
+	ret0, _ := ret[0].(*usecase.CraftResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dismantle indicates an expected call of Dismantle.
+func (mr *MockCraftUseCaseMockRecorder) Dismantle(ctx, collectionID, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dismantle", reflect.TypeOf((*MockCraftUseCase)(nil).Dismantle), ctx, collectionID, quantity)
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 
+	model "github.com/tusmasoma/go-tech-dojo/domain/model"
 	usecase "github.com/tusmasoma/go-tech-dojo/usecase"
 )
 
@@ -37,31 +38,92 @@ func (m *MockGameUseCase) EXPECT() *MockGameUseCaseMockRecorder {
 }
 
 // DrawGacha mocks base method.
-func (m *MockGameUseCase) DrawGacha(ctx context.Context, times int) ([]*usecase.GachaResult, error) {
+func (m *MockGameUseCase) DrawGacha(ctx context.Context, bannerID string, times int) (*usecase.GachaDraw, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DrawGacha", ctx, times)
-	ret0, _ := ret[0].([]*usecase.GachaResult)
+	ret := m.ctrl.Call(m, "DrawGacha", ctx, bannerID, times)
+	ret0, _ := ret[0].(*usecase.GachaDraw)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DrawGacha indicates an expected call of DrawGacha.
-func (mr *MockGameUseCaseMockRecorder) DrawGacha(ctx, times interface{}) *gomock.Call {
+func (mr *MockGameUseCaseMockRecorder) DrawGacha(ctx, bannerID, times interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrawGacha", reflect.TypeOf((*MockGameUseCase)(nil).DrawGacha), ctx, times)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrawGacha", reflect.TypeOf((*MockGameUseCase)(nil).DrawGacha), ctx, bannerID, times)
 }
 
 // FinishGame mocks base method.
-func (m *MockGameUseCase) FinishGame(ctx context.Context, scoreValue int) (int, error) {
+func (m *MockGameUseCase) FinishGame(ctx context.Context, sessionToken string, scoreValue int) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FinishGame", ctx, scoreValue)
+	ret := m.ctrl.Call(m, "FinishGame", ctx, sessionToken, scoreValue)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FinishGame indicates an expected call of FinishGame.
-func (mr *MockGameUseCaseMockRecorder) FinishGame(ctx, scoreValue interface{}) *gomock.Call {
+func (mr *MockGameUseCaseMockRecorder) FinishGame(ctx, sessionToken, scoreValue interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishGame", reflect.TypeOf((*MockGameUseCase)(nil).FinishGame), ctx, scoreValue)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishGame", reflect.TypeOf((*MockGameUseCase)(nil).FinishGame), ctx, sessionToken, scoreValue)
+}
+
+// GetDropRates mocks base method.
+func (m *MockGameUseCase) GetDropRates(ctx context.Context, bannerID string) (*model.DropRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDropRates", ctx, bannerID)
+	ret0, _ := ret[0].(*model.DropRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDropRates indicates an expected call of GetDropRates.
+func (mr *MockGameUseCaseMockRecorder) GetDropRates(ctx, bannerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDropRates", reflect.TypeOf((*MockGameUseCase)(nil).GetDropRates), ctx, bannerID)
+}
+
+// ListBanners mocks base method.
+func (m *MockGameUseCase) ListBanners(ctx context.Context) ([]*usecase.BannerDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBanners", ctx)
+	ret0, _ := ret[0].([]*usecase.BannerDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBanners indicates an expected call of ListBanners.
+func (mr *MockGameUseCaseMockRecorder) ListBanners(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBanners", reflect.TypeOf((*MockGameUseCase)(nil).ListBanners), ctx)
+}
+
+// ListGachaHistory mocks base method.
+func (m *MockGameUseCase) ListGachaHistory(ctx context.Context, cursor string, limit int) ([]*model.GachaDrawRecord, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGachaHistory", ctx, cursor, limit)
+	ret0, _ := ret[0].([]*model.GachaDrawRecord)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListGachaHistory indicates an expected call of ListGachaHistory.
+func (mr *MockGameUseCaseMockRecorder) ListGachaHistory(ctx, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGachaHistory", reflect.TypeOf((*MockGameUseCase)(nil).ListGachaHistory), ctx, cursor, limit)
+}
+
+// StartGame mocks base method.
+func (m *MockGameUseCase) StartGame(ctx context.Context) (*usecase.GameStart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartGame", ctx)
+	ret0, _ := ret[0].(*usecase.GameStart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartGame indicates an expected call of StartGame.
+func (mr *MockGameUseCaseMockRecorder) StartGame(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartGame", reflect.TypeOf((*MockGameUseCase)(nil).StartGame), ctx)
 }
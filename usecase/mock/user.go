@@ -38,10 +38,10 @@ func (m *MockUserUseCase) EXPECT() *MockUserUseCaseMockRecorder {
 }
 
 // CreateUserAndToken mocks base method.
-func (m *MockUserUseCase) CreateUserAndToken(ctx context.Context, email, passward string) (string, error) {
+func (m *MockUserUseCase) CreateUserAndToken(ctx context.Context, email, passward string) (*usecase.TokenPair, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateUserAndToken", ctx, email, passward)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*usecase.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -53,10 +53,10 @@ func (mr *MockUserUseCaseMockRecorder) CreateUserAndToken(ctx, email, passward i
 }
 
 // GetUser mocks base method.
-func (m *MockUserUseCase) GetUser(ctx context.Context) (*model.User, error) {
+func (m *MockUserUseCase) GetUser(ctx context.Context) (*usecase.UserDetail, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUser", ctx)
-	ret0, _ := ret[0].(*model.User)
+	ret0, _ := ret[0].(*usecase.UserDetail)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -82,17 +82,32 @@ func (mr *MockUserUseCaseMockRecorder) ListUserCollections(ctx interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserCollections", reflect.TypeOf((*MockUserUseCase)(nil).ListUserCollections), ctx)
 }
 
-// UpdateUser mocks base method.
-func (m *MockUserUseCase) UpdateUser(ctx context.Context, coins, highscore int) (*model.User, error) {
+// Login mocks base method.
+func (m *MockUserUseCase) Login(ctx context.Context, email, password string) (*usecase.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", ctx, coins, highscore)
-	ret0, _ := ret[0].(*model.User)
+	ret := m.ctrl.Call(m, "Login", ctx, email, password)
+	ret0, _ := ret[0].(*usecase.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserUseCaseMockRecorder) UpdateUser(ctx, coins, highscore interface{}) *gomock.Call {
+// Login indicates an expected call of Login.
+func (mr *MockUserUseCaseMockRecorder) Login(ctx, email, password interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserUseCase)(nil).UpdateUser), ctx, coins, highscore)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUseCase)(nil).Login), ctx, email, password)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserUseCase) UpdateProfile(ctx context.Context, update model.ProfileUpdate) (*usecase.UserDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, update)
+	ret0, _ := ret[0].(*usecase.UserDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserUseCaseMockRecorder) UpdateProfile(ctx, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserUseCase)(nil).UpdateProfile), ctx, update)
 }
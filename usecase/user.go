@@ -5,6 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
@@ -14,53 +17,87 @@ import (
 )
 
 type UserUseCase interface {
-	GetUser(ctx context.Context) (*model.User, error)
+	GetUser(ctx context.Context) (*UserDetail, error)
 	ListUserCollections(ctx context.Context) ([]*Collection, error)
-	CreateUserAndToken(ctx context.Context, email string, passward string) (string, error)
-	UpdateUser(ctx context.Context, coins, highscore int) (*model.User, error)
+	CreateUserAndToken(ctx context.Context, email string, passward string) (*TokenPair, error)
+	Login(ctx context.Context, email string, password string) (*TokenPair, error)
+	// UpdateProfile 残高やハイスコアは変更できない。管理者による変更はAdminUseCaseを使う
+	UpdateProfile(ctx context.Context, update model.ProfileUpdate) (*UserDetail, error)
+}
+
+// UserDetail ユーザと、その通貨ごとの残高
+type UserDetail struct {
+	*model.User
+	Wallet model.Wallet
 }
 
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
 type userUseCase struct {
 	ur  repository.UserRepository
+	wr  repository.WalletRepository
 	tr  repository.TransactionRepository
 	ucr repository.UserCollectionRepository
 	cr  repository.CollectionRepository
 	ccr repository.CollectionCacheRepository
+	rtr repository.RefreshTokenRepository
+	ph  auth.PasswordHasher
+	// dummyHash 存在しないユーザのログイン時にも同じコストで検証を行うためのハッシュ
+	dummyHash string
 }
 
+// NewUserUseCase 存在しないユーザのログインを同じコストで検証できないため、ダミーのハッシュを作れない場合はエラーを返す
 func NewUserUseCase(
 	ur repository.UserRepository,
+	wr repository.WalletRepository,
 	tr repository.TransactionRepository,
 	ucr repository.UserCollectionRepository,
 	cr repository.CollectionRepository,
 	ccr repository.CollectionCacheRepository,
-) UserUseCase {
+	rtr repository.RefreshTokenRepository,
+	ph auth.PasswordHasher,
+) (UserUseCase, error) {
+	dummyHash, err := ph.Hash(uuid.New().String())
+	if err != nil {
+		log.Error("Failed to create dummy password hash", log.Ferror(err))
+		return nil, err
+	}
 	return &userUseCase{
 		ur:  ur,
+		wr:  wr,
 		tr:  tr,
 		ucr: ucr,
 		cr:  cr,
 		ccr: ccr,
-	}
+		rtr: rtr,
+		ph:  ph,
+
+		dummyHash: dummyHash,
+	}, nil
 }
 
-func (uuc *userUseCase) GetUser(ctx context.Context) (*model.User, error) {
-	userIDValue := ctx.Value(config.ContextUserIDKey)
-	userID, ok := userIDValue.(string)
+func (uuc *userUseCase) GetUser(ctx context.Context) (*UserDetail, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
 	if !ok {
 		log.Error("User ID not found in request context")
 		return nil, fmt.Errorf("user name not found in request context")
 	}
+	userID := principal.UserID
 	user, err := uuc.ur.Get(ctx, userID)
 	if err != nil {
 		log.Error("Error getting user", log.Fstring("user_id", userID))
 		return nil, err
 	}
-	return user, nil
+	wallet, err := uuc.wr.Get(ctx, userID)
+	if err != nil {
+		log.Error("Error getting wallet", log.Fstring("user_id", userID))
+		return nil, err
+	}
+	return &UserDetail{User: user, Wallet: wallet}, nil
 }
 
-func (uuc *userUseCase) CreateUserAndToken(ctx context.Context, email string, password string) (string, error) {
-	var user *model.User
+func (uuc *userUseCase) CreateUserAndToken(ctx context.Context, email string, password string) (*TokenPair, error) {
+	var pair *TokenPair
 	if err := uuc.tr.Transaction(ctx, func(ctx context.Context) error {
 		exists, err := uuc.ur.LockUserByEmail(ctx, email)
 		if err != nil {
@@ -72,73 +109,148 @@ func (uuc *userUseCase) CreateUserAndToken(ctx context.Context, email string, pa
 			return fmt.Errorf("user with this email already exists")
 		}
 
-		user, err = model.NewUser(email, password)
+		user, err := model.NewUser(email, password)
 		if err != nil {
 			log.Error("Error creating new user", log.Fstring("email", email))
 			return err
 		}
 
+		user.Password, err = uuc.ph.Hash(password)
+		if err != nil {
+			log.Error("Error hashing password", log.Ferror(err))
+			return err
+		}
+
 		if err = uuc.ur.Create(ctx, *user); err != nil {
 			log.Error("Error creating new user", log.Fstring("email", email))
 			return err
 		}
 
-		return nil
+		pair, err = issueTokenPair(ctx, uuc.rtr, user, "")
+		return err
 	}); err != nil {
-		return "", err
+		return nil, err
 	}
-
-	jwt, _ := auth.GenerateToken(user.ID, user.Email)
-	return jwt, nil
+	return pair, nil
 }
 
-func (uuc *userUseCase) UpdateUser(ctx context.Context, coins, highscore int) (*model.User, error) {
-	userIDValue := ctx.Value(config.ContextUserIDKey)
-	userID, ok := userIDValue.(string)
-	if !ok {
-		log.Error("User ID not found in request context")
-		return nil, fmt.Errorf("user name not found in request context")
+func (uuc *userUseCase) Login(ctx context.Context, email string, password string) (*TokenPair, error) {
+	user, err := uuc.ur.GetByEmail(ctx, email)
+	if errors.Is(err, config.ErrNotFound) {
+		// ユーザの存在有無が応答時間から推測されないよう、ダミーのハッシュで検証を行う
+		_, _ = uuc.ph.Verify(password, uuc.dummyHash)
+		log.Info("Login failed", log.Fstring("email", email))
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		log.Error("Error getting user by email", log.Fstring("email", email))
+		return nil, err
 	}
-	user, err := uuc.ur.Get(ctx, userID)
+
+	ok, err := uuc.ph.Verify(password, user.Password)
 	if err != nil {
-		log.Error("Error getting user", log.Fstring("user_id", userID))
+		log.Error("Error verifying password", log.Fstring("user_id", user.ID), log.Ferror(err))
 		return nil, err
 	}
+	if !ok {
+		log.Info("Login failed", log.Fstring("email", email))
+		return nil, ErrInvalidCredentials
+	}
 
-	// TODO: setter method for user
-	user.Coins = coins
-	user.HighScore = highscore
+	if uuc.ph.NeedsRehash(user.Password) {
+		uuc.rehashPassword(ctx, user, password)
+	}
+
+	return issueTokenPair(ctx, uuc.rtr, user, "")
+}
+
+// rehashPassword 旧アルゴリズム・旧パラメータのハッシュを現在の設定で再ハッシュする
+// userは行ロックなしで読み込んでいるため、パスワードの列だけを更新して他の項目の同時更新を上書きしない
+// 失敗してもログインは継続し、次回ログイン時に再試行する
+func (uuc *userUseCase) rehashPassword(ctx context.Context, user *model.User, password string) {
+	hashed, err := uuc.ph.Hash(password)
+	if err != nil {
+		log.Warn("Failed to rehash password", log.Fstring("user_id", user.ID), log.Ferror(err))
+		return
+	}
+	updated, err := uuc.ur.UpdatePassword(ctx, user.ID, user.Password, hashed)
+	if err != nil {
+		log.Warn("Failed to update rehashed password", log.Fstring("user_id", user.ID), log.Ferror(err))
+		return
+	}
+	if !updated {
+		// 読み込んだ後に他のリクエストでハッシュが変わっている
+		log.Info("Password was changed concurrently, skip rehash", log.Fstring("user_id", user.ID))
+		return
+	}
+	user.Password = hashed
+	log.Info("Password rehashed", log.Fstring("user_id", user.ID))
+}
+
+func (uuc *userUseCase) UpdateProfile(ctx context.Context, update model.ProfileUpdate) (*UserDetail, error) {
+	principal, ok := model.PrincipalFromContext(ctx)
+	if !ok {
+		log.Error("User ID not found in request context")
+		return nil, fmt.Errorf("user name not found in request context")
+	}
+	userID := principal.UserID
 
-	if err = uuc.ur.Update(ctx, *user); err != nil {
-		log.Error("Error updating user", log.Fstring("user_id", userID))
+	var user *model.User
+	var wallet model.Wallet
+	if err := uuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		// 同時に行われたゲーム終了などによるハイスコアの更新を上書きしないよう、行ロックを取得してから更新する
+		user, err = uuc.ur.GetForUpdate(ctx, userID)
+		if err != nil {
+			log.Error("Error getting user", log.Fstring("user_id", userID))
+			return err
+		}
+		if err = user.UpdateProfile(update); err != nil {
+			log.Info("Invalid profile update", log.Fstring("user_id", userID), log.Ferror(err))
+			return err
+		}
+		if err = uuc.ur.Update(ctx, *user); err != nil {
+			log.Error("Error updating user", log.Fstring("user_id", userID))
+			return err
+		}
+		if wallet, err = uuc.wr.Get(ctx, userID); err != nil {
+			log.Error("Error getting wallet", log.Fstring("user_id", userID))
+			return err
+		}
+		return nil
+	}); err != nil {
 		return nil, err
 	}
-	return user, nil
+	return &UserDetail{User: user, Wallet: wallet}, nil
 }
 
 type Collection struct {
 	*model.Collection
 	Has bool
+	// Quantity 所持数。所持していない場合は0
+	Quantity int
+	// FirstObtainedAt,LastObtainedAt 所持していない場合はnil
+	FirstObtainedAt *time.Time
+	LastObtainedAt  *time.Time
 }
 
 func (uuc *userUseCase) ListUserCollections(ctx context.Context) ([]*Collection, error) {
-	userIDValue := ctx.Value(config.ContextUserIDKey)
-	userID, ok := userIDValue.(string)
+	principal, ok := model.PrincipalFromContext(ctx)
 	if !ok {
 		log.Error("User ID not found in request context")
 		return nil, fmt.Errorf("user name not found in request context")
 	}
+	userID := principal.UserID
 
-	collections, err := uuc.ccr.Get(ctx, "collections")
+	collections, err := uuc.ccr.Get(ctx, model.CollectionsCacheKey)
 	if errors.Is(err, config.ErrCacheMiss) {
-		log.Info("Cache miss", log.Fstring("key", "collections"))
+		log.Info("Cache miss", log.Fstring("key", model.CollectionsCacheKey))
 		collections, err = uuc.cr.List(ctx)
 		if err != nil {
 			log.Error("Error getting collections", log.Ferror(err))
 			return nil, err
 		}
 
-		if err = uuc.ccr.Create(ctx, "collections", collections); err != nil {
+		if err = uuc.ccr.Create(ctx, model.CollectionsCacheKey, collections); err != nil {
 			log.Error("Error setting collections to cache", log.Ferror(err))
 			return nil, err
 		}
@@ -153,18 +265,21 @@ func (uuc *userUseCase) ListUserCollections(ctx context.Context) ([]*Collection,
 		return nil, err
 	}
 
-	userCollectionMap := make(map[string]bool)
+	userCollectionMap := make(map[string]*model.UserCollection)
 	for _, uc := range userCollections {
-		userCollectionMap[uc.CollectionID] = true
+		userCollectionMap[uc.CollectionID] = uc
 	}
 
 	var reusult []*Collection
 	for _, c := range collections {
-		has := userCollectionMap[c.ID]
-		reusult = append(reusult, &Collection{
-			Collection: c,
-			Has:        has,
-		})
+		collection := &Collection{Collection: c}
+		if uc, ok := userCollectionMap[c.ID]; ok {
+			collection.Has = true
+			collection.Quantity = uc.Quantity
+			collection.FirstObtainedAt = &uc.FirstObtainedAt
+			collection.LastObtainedAt = &uc.LastObtainedAt
+		}
+		reusult = append(reusult, collection)
 	}
 
 	return reusult, nil
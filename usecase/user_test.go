@@ -2,28 +2,90 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/tusmasoma/go-tech-dojo/config"
 	"github.com/tusmasoma/go-tech-dojo/domain/model"
 	"github.com/tusmasoma/go-tech-dojo/domain/repository/mock"
+	"github.com/tusmasoma/go-tech-dojo/pkg/auth"
 )
 
+func newTestPasswordHasher(t *testing.T) auth.PasswordHasher {
+	t.Helper()
+	ph, err := auth.NewPasswordHasher(auth.PasswordHashParams{
+		Algorithm:  auth.HashAlgorithmBcrypt,
+		BcryptCost: bcrypt.MinCost,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create password hasher: %v", err)
+	}
+	return ph
+}
+
+// failingPasswordHasher ハッシュ化に失敗するPasswordHasher
+type failingPasswordHasher struct {
+	auth.PasswordHasher
+	err error
+}
+
+func (fph failingPasswordHasher) Hash(string) (string, error) {
+	return "", fph.err
+}
+
+func TestNewUserUseCase(t *testing.T) {
+	t.Parallel()
+
+	errHash := errors.New("hash failed")
+	patterns := []struct {
+		name    string
+		ph      auth.PasswordHasher
+		wantErr error
+	}{
+		{
+			name: "success",
+			ph:   newTestPasswordHasher(t),
+		},
+		{
+			name:    "Fail: dummy hash cannot be created",
+			ph:      failingPasswordHasher{err: errHash},
+			wantErr: errHash,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			usecase, err := NewUserUseCase(nil, nil, nil, nil, nil, nil, nil, tt.ph)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewUserUseCase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (usecase == nil) != (tt.wantErr != nil) {
+				t.Errorf("NewUserUseCase() usecase = %v, wantErr %v", usecase, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestUserUseCase_GetUser(t *testing.T) {
 	t.Parallel()
 
 	userID := uuid.New().String()
-	ctx := context.WithValue(context.Background(), config.ContextUserIDKey, userID)
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
 
 	user := model.User{
 		ID:        userID,
 		Name:      "test",
 		Email:     "test@gmail.com",
-		Coins:     100,
 		HighScore: 1000,
 	}
 
@@ -33,17 +95,19 @@ func TestUserUseCase_GetUser(t *testing.T) {
 		setup func(
 			m *mock.MockUserRepository,
 			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockWalletRepository,
 		)
 		wantErr error
 	}{
 		{
 			name: "success",
 			ctx:  ctx,
-			setup: func(m *mock.MockUserRepository, m1 *mock.MockTransactionRepository) {
+			setup: func(m *mock.MockUserRepository, m1 *mock.MockTransactionRepository, m2 *mock.MockWalletRepository) {
 				m.EXPECT().Get(
 					ctx,
 					userID,
 				).Return(&user, nil)
+				m2.EXPECT().Get(ctx, userID).Return(model.Wallet{model.CurrencyCoin: 100}, nil)
 			},
 			wantErr: nil,
 		},
@@ -64,13 +128,18 @@ func TestUserUseCase_GetUser(t *testing.T) {
 			ucr := mock.NewMockUserCollectionRepository(ctrl)
 			cr := mock.NewMockCollectionRepository(ctrl)
 			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(ur, tr)
+				tt.setup(ur, tr, wr)
 			}
 
-			usecase := NewUserUseCase(ur, tr, ucr, cr, ccr)
-			_, err := usecase.GetUser(tt.ctx)
+			usecase, err := NewUserUseCase(ur, wr, tr, ucr, cr, ccr, rtr, newTestPasswordHasher(t))
+			if err != nil {
+				t.Fatalf("Failed to create user usecase: %v", err)
+			}
+			_, err = usecase.GetUser(tt.ctx)
 
 			if (err != nil) != (tt.wantErr != nil) {
 				t.Errorf("GetUser() error = %v, wantErr %v", err, tt.wantErr)
@@ -112,7 +181,12 @@ func TestUserUseCase_CreateUserAndToken(t *testing.T) {
 				m.EXPECT().Create(
 					gomock.Any(),
 					gomock.Any(),
-				).Return(nil)
+				).DoAndReturn(func(_ context.Context, user model.User) error {
+					if user.Password == "password123" {
+						return fmt.Errorf("password is stored in plaintext")
+					}
+					return nil
+				})
 			},
 			arg: CreateUserAndTokenArg{
 				ctx:      context.Background(),
@@ -151,13 +225,22 @@ func TestUserUseCase_CreateUserAndToken(t *testing.T) {
 			ucr := mock.NewMockUserCollectionRepository(ctrl)
 			cr := mock.NewMockCollectionRepository(ctrl)
 			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
 
 			if tt.setup != nil {
 				tt.setup(ur, tr)
 			}
 
-			usecase := NewUserUseCase(ur, tr, ucr, cr, ccr)
-			jwt, err := usecase.CreateUserAndToken(tt.arg.ctx, tt.arg.email, tt.arg.passward)
+			if tt.wantErr == nil {
+				rtr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			usecase, err := NewUserUseCase(ur, wr, tr, ucr, cr, ccr, rtr, newTestPasswordHasher(t))
+			if err != nil {
+				t.Fatalf("Failed to create user usecase: %v", err)
+			}
+			pair, err := usecase.CreateUserAndToken(tt.arg.ctx, tt.arg.email, tt.arg.passward)
 
 			if (err != nil) != (tt.wantErr != nil) {
 				t.Errorf("CreateUserAndToken() error = %v, wantErr %v", err, tt.wantErr)
@@ -165,69 +248,209 @@ func TestUserUseCase_CreateUserAndToken(t *testing.T) {
 				t.Errorf("CreateUserAndToken() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
-			if tt.wantErr == nil && jwt == "" {
+			if tt.wantErr == nil && (pair == nil || pair.AccessToken == "" || pair.RefreshToken == "") {
 				t.Error("Failed to generate token")
 			}
 		})
 	}
 }
 
-type UpdateUserArg struct {
-	ctx       context.Context
-	coins     int
-	highscore int
+func TestUserUseCase_Login(t *testing.T) {
+	t.Parallel()
+
+	ph := newTestPasswordHasher(t)
+	hashed, err := ph.Hash("password123")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	userID := uuid.New().String()
+	user := model.User{
+		ID:       userID,
+		Name:     "test",
+		Email:    "test@gmail.com",
+		Password: hashed,
+	}
+
+	patterns := []struct {
+		name     string
+		setup    func(m *mock.MockUserRepository)
+		email    string
+		password string
+		wantErr  error
+	}{
+		{
+			name: "success",
+			setup: func(m *mock.MockUserRepository) {
+				u := user
+				m.EXPECT().GetByEmail(gomock.Any(), "test@gmail.com").Return(&u, nil)
+			},
+			email:    "test@gmail.com",
+			password: "password123",
+			wantErr:  nil,
+		},
+		{
+			name: "success: legacy plaintext password is rehashed",
+			setup: func(m *mock.MockUserRepository) {
+				u := user
+				u.Password = "password123"
+				m.EXPECT().GetByEmail(gomock.Any(), "test@gmail.com").Return(&u, nil)
+				// パスワードの列だけを、読み込んだ時のハッシュを条件に更新する
+				m.EXPECT().UpdatePassword(gomock.Any(), user.ID, "password123", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, _ string, newHash string) (bool, error) {
+						if ok, _ := ph.Verify("password123", newHash); !ok || newHash == "password123" {
+							t.Errorf("UpdatePassword() password was not rehashed: %v", newHash)
+						}
+						return true, nil
+					},
+				)
+			},
+			email:    "test@gmail.com",
+			password: "password123",
+			wantErr:  nil,
+		},
+		{
+			name: "Fail: wrong password",
+			setup: func(m *mock.MockUserRepository) {
+				u := user
+				m.EXPECT().GetByEmail(gomock.Any(), "test@gmail.com").Return(&u, nil)
+			},
+			email:    "test@gmail.com",
+			password: "wrong",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name: "Fail: user not found",
+			setup: func(m *mock.MockUserRepository) {
+				m.EXPECT().GetByEmail(gomock.Any(), "unknown@gmail.com").Return(nil, config.ErrNotFound)
+			},
+			email:    "unknown@gmail.com",
+			password: "password123",
+			wantErr:  ErrInvalidCredentials,
+		},
+	}
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ur := mock.NewMockUserRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			ucr := mock.NewMockUserCollectionRepository(ctrl)
+			cr := mock.NewMockCollectionRepository(ctrl)
+			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(ur)
+			}
+
+			if tt.wantErr == nil {
+				rtr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			usecase, err := NewUserUseCase(ur, wr, tr, ucr, cr, ccr, rtr, ph)
+			if err != nil {
+				t.Fatalf("Failed to create user usecase: %v", err)
+			}
+			pair, err := usecase.Login(context.Background(), tt.email, tt.password)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (pair == nil || pair.AccessToken == "" || pair.RefreshToken == "") {
+				t.Error("Failed to generate token")
+			}
+		})
+	}
 }
 
-func TestUserUseCase_UpdateUser(t *testing.T) {
+func TestUserUseCase_UpdateProfile(t *testing.T) {
 	t.Parallel()
 
 	userID := uuid.New().String()
-	ctx := context.WithValue(context.Background(), config.ContextUserIDKey, userID)
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
 
 	user := model.User{
 		ID:        userID,
 		Name:      "test",
 		Email:     "test@gmail.com",
-		Coins:     100,
 		HighScore: 1000,
 	}
+	name := "updated"
+	locale := "ja"
 
 	patterns := []struct {
 		name  string
 		setup func(
 			m *mock.MockUserRepository,
 			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockWalletRepository,
 		)
-		arg     UpdateUserArg
+		arg struct {
+			ctx    context.Context
+			update model.ProfileUpdate
+		}
 		wantErr error
 	}{
 		{
 			name: "success",
-			setup: func(m *mock.MockUserRepository, m1 *mock.MockTransactionRepository) {
-				m.EXPECT().Get(
-					ctx,
-					userID,
-				).Return(&user, nil)
-				user.Coins = 120
-				user.HighScore = 1100
+			setup: func(m *mock.MockUserRepository, m1 *mock.MockTransactionRepository, m2 *mock.MockWalletRepository) {
+				m1.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				current := user
+				m.EXPECT().GetForUpdate(ctx, userID).Return(&current, nil)
+				// ハイスコアは変更されない
 				m.EXPECT().Update(
 					gomock.Any(),
-					user,
+					model.User{
+						ID:        userID,
+						Name:      name,
+						Email:     user.Email,
+						HighScore: user.HighScore,
+						Locale:    locale,
+					},
 				).Return(nil)
+				m2.EXPECT().Get(gomock.Any(), userID).Return(model.Wallet{model.CurrencyCoin: 100}, nil)
 			},
-			arg: UpdateUserArg{
-				ctx:       ctx,
-				coins:     120,
-				highscore: 1100,
+			arg: struct {
+				ctx    context.Context
+				update model.ProfileUpdate
+			}{
+				ctx:    ctx,
+				update: model.ProfileUpdate{Name: &name, Locale: &locale},
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Fail: invalid profile",
+			setup: func(m *mock.MockUserRepository, m1 *mock.MockTransactionRepository, m2 *mock.MockWalletRepository) {
+				m1.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				current := user
+				m.EXPECT().GetForUpdate(ctx, userID).Return(&current, nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				update model.ProfileUpdate
+			}{
+				ctx:    ctx,
+				update: model.ProfileUpdate{Locale: func() *string { s := "invalid locale"; return &s }()},
+			},
+			wantErr: &model.ValidationError{Fields: []model.FieldError{{Field: "locale", Message: "must be a BCP 47 language tag such as ja or en-US"}}},
+		},
 		{
 			name: "Fail: User ID not found in request context",
-			arg: UpdateUserArg{
-				ctx:       context.Background(),
-				coins:     100,
-				highscore: 1000,
+			arg: struct {
+				ctx    context.Context
+				update model.ProfileUpdate
+			}{
+				ctx:    context.Background(),
+				update: model.ProfileUpdate{Name: &name},
 			},
 			wantErr: fmt.Errorf("user name not found in request context"),
 		},
@@ -243,18 +466,23 @@ func TestUserUseCase_UpdateUser(t *testing.T) {
 			ucr := mock.NewMockUserCollectionRepository(ctrl)
 			cr := mock.NewMockCollectionRepository(ctrl)
 			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(ur, tr)
+				tt.setup(ur, tr, wr)
 			}
 
-			usecase := NewUserUseCase(ur, tr, ucr, cr, ccr)
-			updateUser, err := usecase.UpdateUser(tt.arg.ctx, tt.arg.coins, tt.arg.highscore)
+			usecase, err := NewUserUseCase(ur, wr, tr, ucr, cr, ccr, rtr, newTestPasswordHasher(t))
+			if err != nil {
+				t.Fatalf("Failed to create user usecase: %v", err)
+			}
+			updateUser, err := usecase.UpdateProfile(tt.arg.ctx, tt.arg.update)
 
 			if (err != nil) != (tt.wantErr != nil) {
-				t.Errorf("UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
 			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
-				t.Errorf("UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
 			if tt.wantErr == nil && updateUser == nil {
@@ -272,7 +500,7 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 	collection2ID := uuid.New().String()
 	collection3ID := uuid.New().String()
 
-	ctx := context.WithValue(context.Background(), config.ContextUserIDKey, userID)
+	ctx := model.ContextWithPrincipal(context.Background(), model.Principal{UserID: userID, Role: model.RolePlayer})
 
 	collections := model.Collections{
 		{
@@ -294,6 +522,24 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 			Weight: 1,
 		},
 	}
+	firstObtainedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastObtainedAt := firstObtainedAt.Add(time.Hour)
+	userCollections := []*model.UserCollection{
+		{
+			UserID:          userID,
+			CollectionID:    collection1ID,
+			Quantity:        3,
+			FirstObtainedAt: firstObtainedAt,
+			LastObtainedAt:  lastObtainedAt,
+		},
+		{
+			UserID:          userID,
+			CollectionID:    collection2ID,
+			Quantity:        1,
+			FirstObtainedAt: firstObtainedAt,
+			LastObtainedAt:  firstObtainedAt,
+		},
+	}
 
 	patterns := []struct {
 		name  string
@@ -321,19 +567,7 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 					collections,
 					nil,
 				)
-				m2.EXPECT().List(ctx, userID).Return(
-					[]*model.UserCollection{
-						{
-							UserID:       userID,
-							CollectionID: collection1ID,
-						},
-						{
-							UserID:       userID,
-							CollectionID: collection2ID,
-						},
-					},
-					nil,
-				)
+				m2.EXPECT().List(ctx, userID).Return(userCollections, nil)
 			},
 			want: struct {
 				collections []*Collection
@@ -347,7 +581,10 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 							Rarity: 3,
 							Weight: 3,
 						},
-						Has: true,
+						Has:             true,
+						Quantity:        3,
+						FirstObtainedAt: &firstObtainedAt,
+						LastObtainedAt:  &lastObtainedAt,
 					},
 					{
 						Collection: &model.Collection{
@@ -356,7 +593,10 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 							Rarity: 2,
 							Weight: 2,
 						},
-						Has: true,
+						Has:             true,
+						Quantity:        1,
+						FirstObtainedAt: &firstObtainedAt,
+						LastObtainedAt:  &firstObtainedAt,
 					},
 					{
 						Collection: &model.Collection{
@@ -394,19 +634,7 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 					"collections",
 					collections,
 				).Return(nil)
-				m2.EXPECT().List(ctx, userID).Return(
-					[]*model.UserCollection{
-						{
-							UserID:       userID,
-							CollectionID: collection1ID,
-						},
-						{
-							UserID:       userID,
-							CollectionID: collection2ID,
-						},
-					},
-					nil,
-				)
+				m2.EXPECT().List(ctx, userID).Return(userCollections, nil)
 			},
 			want: struct {
 				collections []*Collection
@@ -420,7 +648,10 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 							Rarity: 3,
 							Weight: 3,
 						},
-						Has: true,
+						Has:             true,
+						Quantity:        3,
+						FirstObtainedAt: &firstObtainedAt,
+						LastObtainedAt:  &lastObtainedAt,
 					},
 					{
 						Collection: &model.Collection{
@@ -429,7 +660,10 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 							Rarity: 2,
 							Weight: 2,
 						},
-						Has: true,
+						Has:             true,
+						Quantity:        1,
+						FirstObtainedAt: &firstObtainedAt,
+						LastObtainedAt:  &firstObtainedAt,
 					},
 					{
 						Collection: &model.Collection{
@@ -456,12 +690,17 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 			ucr := mock.NewMockUserCollectionRepository(ctrl)
 			cr := mock.NewMockCollectionRepository(ctrl)
 			ccr := mock.NewMockCollectionCacheRepository(ctrl)
+			rtr := mock.NewMockRefreshTokenRepository(ctrl)
+			wr := mock.NewMockWalletRepository(ctrl)
 
 			if tt.setup != nil {
 				tt.setup(cr, ccr, ucr)
 			}
 
-			usecase := NewUserUseCase(ur, tr, ucr, cr, ccr)
+			usecase, err := NewUserUseCase(ur, wr, tr, ucr, cr, ccr, rtr, newTestPasswordHasher(t))
+			if err != nil {
+				t.Fatalf("Failed to create user usecase: %v", err)
+			}
 			collections, err := usecase.ListUserCollections(ctx)
 
 			if (err != nil) != (tt.want.err != nil) {
@@ -473,6 +712,9 @@ func TestUserUseCase_ListUserCollections(t *testing.T) {
 			if tt.want.err == nil && collections == nil {
 				t.Error("Failed to list user collections")
 			}
+			if d := cmp.Diff(collections, tt.want.collections); len(d) != 0 {
+				t.Errorf("ListUserCollections() mismatch (-got +want):\n%s", d)
+			}
 		})
 	}
 }